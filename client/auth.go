@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"yplanning/pkg/models"
+)
+
+// Register creates an account and signs the client in with it.
+func (client *Client) Register(ctx context.Context, req models.UserRequest) (*models.TokenResponse, error) {
+	return client.authenticate(ctx, "/api/auth/register", req)
+}
+
+// Login signs the client in; later calls use the returned tokens.
+func (client *Client) Login(ctx context.Context, email string, password string) (*models.TokenResponse, error) {
+	return client.authenticate(ctx, "/api/auth/login", models.UserRequest{Email: email, Password: password})
+}
+
+// Refresh exchanges the refresh token for new tokens. Calls rejected with
+// 401 do this automatically.
+func (client *Client) Refresh(ctx context.Context) (*models.TokenResponse, error) {
+	_, refreshToken := client.Tokens()
+	return client.authenticate(ctx, "/api/auth/refresh", models.TokenRequest{RefreshToken: refreshToken})
+}
+
+// Logout revokes the refresh token on the server and forgets the tokens.
+func (client *Client) Logout(ctx context.Context) error {
+	_, refreshToken := client.Tokens()
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/auth/logout", body: models.TokenRequest{RefreshToken: refreshToken}, anonymous: true}); err != nil {
+		return err
+	}
+	client.SetTokens("", "")
+	return nil
+}
+
+// LogoutAll revokes the refresh tokens of every device the user signed in
+// on, this one included.
+func (client *Client) LogoutAll(ctx context.Context) error {
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/auth/logout-all"}); err != nil {
+		return err
+	}
+	client.SetTokens("", "")
+	return nil
+}
+
+// Sessions lists the devices the user is signed in on.
+func (client *Client) Sessions(ctx context.Context) ([]models.SessionResponse, error) {
+	var sessions []models.SessionResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/auth/sessions", out: &sessions}); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's devices out.
+func (client *Client) RevokeSession(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/auth/sessions/%d", id)})
+}
+
+func (client *Client) authenticate(ctx context.Context, path string, body interface{}) (*models.TokenResponse, error) {
+	tokens := &models.TokenResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: path, body: body, out: tokens, anonymous: true}); err != nil {
+		return nil, err
+	}
+	client.SetTokens(tokens.AccessToken, tokens.RefreshToken)
+	return tokens, nil
+}
+
+// refresh renews the tokens after stale was rejected, unless a concurrent
+// call already did.
+func (client *Client) refresh(ctx context.Context, stale string) error {
+	client.refreshing.Lock()
+	defer client.refreshing.Unlock()
+	if accessToken, _ := client.Tokens(); accessToken != stale {
+		return nil
+	}
+	tokens, err := client.Refresh(ctx)
+	if err != nil {
+		return err
+	}
+	if client.OnTokenRefresh != nil {
+		client.OnTokenRefresh(*tokens)
+	}
+	return nil
+}
+
+// ForgotPassword asks the server to email a password reset token to the
+// account with email, if there is one.
+func (client *Client) ForgotPassword(ctx context.Context, email string) error {
+	return client.do(ctx, request{method: http.MethodPost, path: "/api/auth/forgot-password", body: models.ForgotPasswordRequest{Email: email}, anonymous: true})
+}
+
+// ResetPassword sets a new password with a token from a reset email. The
+// account's devices, this one included, are signed out.
+func (client *Client) ResetPassword(ctx context.Context, token, password string) error {
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/auth/reset-password", body: models.ResetPasswordRequest{Token: token, Password: password}, anonymous: true}); err != nil {
+		return err
+	}
+	client.SetTokens("", "")
+	return nil
+}
+
+// Email returns the user's email, whether it is verified and the address
+// waiting for verification to replace it.
+func (client *Client) Email(ctx context.Context) (*models.EmailResponse, error) {
+	email := &models.EmailResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/auth/email", out: email}); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// ChangeEmail asks to change the user's email. It changes once the link
+// sent to the new address is followed.
+func (client *Client) ChangeEmail(ctx context.Context, newEmail string) (*models.EmailResponse, error) {
+	email := &models.EmailResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: "/api/auth/email", body: models.EmailRequest{Email: newEmail}, out: email}); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// ResendVerification sends a new verification link to the address waiting
+// for verification.
+func (client *Client) ResendVerification(ctx context.Context) (*models.EmailResponse, error) {
+	email := &models.EmailResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/auth/verify-email/resend", out: email}); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// VerifyEmail verifies an address with the token of a verification link.
+func (client *Client) VerifyEmail(ctx context.Context, token string) (*models.EmailResponse, error) {
+	email := &models.EmailResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/auth/verify-email", query: url.Values{"token": {token}}, out: email, anonymous: true}); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
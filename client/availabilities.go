@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) CreateAvailability(ctx context.Context, req models.AvailabilityRequest) (*models.AvailabilityResponse, error) {
+	availability := &models.AvailabilityResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/availability/", body: req, out: availability}); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+func (client *Client) GetAvailabilities(ctx context.Context) ([]models.AvailabilityResponse, error) {
+	var availabilities []models.AvailabilityResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/availability/availabilities", out: &availabilities}); err != nil {
+		return nil, err
+	}
+	return availabilities, nil
+}
+
+func (client *Client) GetAvailabilityByID(ctx context.Context, id uint) (*models.AvailabilityResponse, error) {
+	availability := &models.AvailabilityResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/availability/%d", id), out: availability}); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+func (client *Client) GetAvailabilitiesByUserID(ctx context.Context, userID uint) ([]models.AvailabilityResponse, error) {
+	var availabilities []models.AvailabilityResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/availability/user/%d", userID), out: &availabilities}); err != nil {
+		return nil, err
+	}
+	return availabilities, nil
+}
+
+func (client *Client) UpdateAvailability(ctx context.Context, id uint, req models.AvailabilityRequest) error {
+	return client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/availability/%d", id), body: req})
+}
+
+func (client *Client) DeleteAvailability(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/availability/%d", id)})
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) GetAppPasswords(ctx context.Context) ([]models.AppPasswordResponse, error) {
+	var appPasswords []models.AppPasswordResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/caldav/app-passwords", out: &appPasswords}); err != nil {
+		return nil, err
+	}
+	return appPasswords, nil
+}
+
+// CreateAppPassword returns the CalDAV credentials; the password is not
+// shown again.
+func (client *Client) CreateAppPassword(ctx context.Context, req models.AppPasswordRequest) (*models.AppPasswordResponse, error) {
+	appPassword := &models.AppPasswordResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/caldav/app-passwords", body: req, out: appPassword}); err != nil {
+		return nil, err
+	}
+	return appPassword, nil
+}
+
+func (client *Client) RevokeAppPassword(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/caldav/app-passwords/%d", id)})
+}
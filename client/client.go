@@ -0,0 +1,187 @@
+// Package client is a Go client for the yplanning REST API.
+//
+//	c := client.New("http://localhost:8080")
+//	if _, err := c.Login(ctx, "alice@example.com", "secret"); err != nil {
+//		return err
+//	}
+//	dates, err := c.GetDatesByUserID(ctx, userID)
+//
+// Access tokens are refreshed through /api/auth/refresh when a call is
+// rejected with 401, idempotent calls are retried on transient failures,
+// and API errors can be matched with errors.Is against ErrBadRequest,
+// ErrUnauthorized, ErrNotFound and ErrConflict.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"yplanning/pkg/models"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultRetryWait  = 200 * time.Millisecond
+	maxRetryWait      = 5 * time.Second
+)
+
+type Client struct {
+	// BaseURL is the server root, without the /api prefix.
+	BaseURL    string
+	HTTPClient *http.Client
+	// MaxRetries is the number of times an idempotent call is retried
+	// after a network error or a 429, 502, 503 or 504 response.
+	MaxRetries int
+	// RetryWait is the delay before the first retry; it doubles for each
+	// following one.
+	RetryWait time.Duration
+	// OnTokenRefresh, if set, is called with the new tokens after an
+	// automatic refresh, so that callers can persist them.
+	OnTokenRefresh func(tokens models.TokenResponse)
+
+	mutex        sync.Mutex
+	refreshing   sync.Mutex
+	accessToken  string
+	refreshToken string
+}
+
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: defaultMaxRetries,
+		RetryWait:  defaultRetryWait,
+	}
+}
+
+// SetTokens sets the tokens used to authenticate, e.g. ones saved from a
+// previous session.
+func (client *Client) SetTokens(accessToken string, refreshToken string) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.accessToken, client.refreshToken = accessToken, refreshToken
+}
+
+// Tokens returns the current access and refresh tokens.
+func (client *Client) Tokens() (string, string) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.accessToken, client.refreshToken
+}
+
+// request describes one API call. body is sent as JSON unless raw is set,
+// and the response is decoded into out when it is not nil.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        interface{}
+	raw         []byte
+	contentType string
+	out         interface{}
+	// anonymous calls carry no token and are never refreshed.
+	anonymous bool
+}
+
+func (client *Client) do(ctx context.Context, req request) error {
+	body, contentType := req.raw, req.contentType
+	if req.body != nil {
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return err
+		}
+		body, contentType = encoded, "application/json"
+	}
+	sentToken, _ := client.Tokens()
+	resp, err := client.send(ctx, req, body, contentType)
+	if err != nil {
+		return err
+	}
+	if _, refreshToken := client.Tokens(); resp.StatusCode == http.StatusUnauthorized && !req.anonymous && refreshToken != "" {
+		resp.Body.Close()
+		if err := client.refresh(ctx, sentToken); err != nil {
+			return err
+		}
+		if resp, err = client.send(ctx, req, body, contentType); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return newError(resp)
+	}
+	if req.out == nil {
+		return nil
+	}
+	if raw, ok := req.out.(*[]byte); ok {
+		*raw, err = io.ReadAll(resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(req.out)
+}
+
+// send performs the call, retrying idempotent ones on transient failures.
+func (client *Client) send(ctx context.Context, req request, body []byte, contentType string) (*http.Response, error) {
+	idempotent := req.method != http.MethodPost
+	wait := client.RetryWait
+	for attempt := 0; ; attempt++ {
+		httpRequest, err := client.newRequest(ctx, req, body, contentType)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.HTTPClient.Do(httpRequest)
+		if !idempotent || attempt >= client.MaxRetries || !transient(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxRetryWait)
+	}
+}
+
+func (client *Client) newRequest(ctx context.Context, req request, body []byte, contentType string) (*http.Request, error) {
+	target := client.BaseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, req.method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		httpRequest.Header.Set("Content-Type", contentType)
+	}
+	if accessToken, _ := client.Tokens(); accessToken != "" && !req.anonymous {
+		httpRequest.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return httpRequest, nil
+}
+
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) CreateColor(ctx context.Context, req models.ColorRequest) (*models.ColorResponse, error) {
+	color := &models.ColorResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/color/", body: req, out: color}); err != nil {
+		return nil, err
+	}
+	return color, nil
+}
+
+func (client *Client) GetColors(ctx context.Context) ([]models.ColorResponse, error) {
+	var colors []models.ColorResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/color/colors", out: &colors}); err != nil {
+		return nil, err
+	}
+	return colors, nil
+}
+
+func (client *Client) GetColorByID(ctx context.Context, id uint) (*models.ColorResponse, error) {
+	color := &models.ColorResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/color/%d", id), out: color}); err != nil {
+		return nil, err
+	}
+	return color, nil
+}
+
+// GetColorByHexCode looks a color up by its hex code, e.g. "#ff0000".
+func (client *Client) GetColorByHexCode(ctx context.Context, hexCode string) (*models.ColorResponse, error) {
+	color := &models.ColorResponse{}
+	// The endpoint validates the body as a ColorRequest, which needs a name.
+	req := models.ColorRequest{HexCode: hexCode, Name: hexCode}
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/color/hexcode", body: req, out: color}); err != nil {
+		return nil, err
+	}
+	return color, nil
+}
+
+func (client *Client) UpdateColor(ctx context.Context, id uint, req models.ColorRequest) (*models.ColorResponse, error) {
+	color := &models.ColorResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/color/%d", id), body: req, out: color}); err != nil {
+		return nil, err
+	}
+	return color, nil
+}
+
+func (client *Client) DeleteColor(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/color/%d", id)})
+}
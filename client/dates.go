@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) CreateDate(ctx context.Context, req models.DateRequest) (*models.DateResponse, error) {
+	date := &models.DateResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/date/", body: req, out: date}); err != nil {
+		return nil, err
+	}
+	return date, nil
+}
+
+// ImportDates creates or updates the current user's dates from an
+// iCalendar document. With dryRun, nothing is written and the report
+// describes what would happen.
+func (client *Client) ImportDates(ctx context.Context, ics []byte, dryRun bool) (*models.ImportReport, error) {
+	report := &models.ImportReport{}
+	query := url.Values{"dry_run": {strconv.FormatBool(dryRun)}}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/date/import", query: query, raw: ics, contentType: "text/calendar", out: report}); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (client *Client) GetDates(ctx context.Context) ([]models.DateResponse, error) {
+	var dates []models.DateResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/date/dates", out: &dates}); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+func (client *Client) GetDateByID(ctx context.Context, id uint) (*models.DateResponse, error) {
+	date := &models.DateResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/date/%d", id), out: date}); err != nil {
+		return nil, err
+	}
+	return date, nil
+}
+
+func (client *Client) GetDatesByUserID(ctx context.Context, userID uint) ([]models.DateResponse, error) {
+	var dates []models.DateResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/date/user/%d", userID), out: &dates}); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+func (client *Client) GetDateByRecurrenceID(ctx context.Context, recurrenceID uint) (*models.DateResponse, error) {
+	date := &models.DateResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/date/recurrence/%d", recurrenceID), out: date}); err != nil {
+		return nil, err
+	}
+	return date, nil
+}
+
+// GetDatesInRange returns the dates of a user between begin and end.
+func (client *Client) GetDatesInRange(ctx context.Context, userID uint, begin time.Time, end time.Time) ([]models.DateResponse, error) {
+	var dates []models.DateResponse
+	req := models.AvailabilityRequest{DateBegin: begin, DateEnd: end, UserID: userID}
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/date/range", body: req, out: &dates}); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+func (client *Client) UpdateDate(ctx context.Context, id uint, req models.DateRequest) error {
+	return client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/date/%d", id), body: req})
+}
+
+func (client *Client) DeleteDate(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/date/%d", id)})
+}
+
+// ExportDates downloads the current user's dates as an iCalendar document.
+func (client *Client) ExportDates(ctx context.Context) ([]byte, error) {
+	var ics []byte
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/date/export", out: &ics}); err != nil {
+		return nil, err
+	}
+	return ics, nil
+}
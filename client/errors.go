@@ -0,0 +1,51 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// Error is a response from the API with a status of 300 or more. It
+// matches ErrBadRequest, ErrUnauthorized, ErrNotFound or ErrConflict with
+// errors.Is, depending on its status.
+type Error struct {
+	StatusCode int
+	// Message is the error text sent by the server.
+	Message string
+}
+
+func newError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+func (err *Error) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("yplanning: %d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	}
+	return fmt.Sprintf("yplanning: %d %s", err.StatusCode, err.Message)
+}
+
+func (err *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return err.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return err.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return err.StatusCode == http.StatusConflict
+	}
+	return false
+}
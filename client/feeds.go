@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) GetFeedTokens(ctx context.Context) ([]models.FeedTokenResponse, error) {
+	var feedTokens []models.FeedTokenResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/feed/", out: &feedTokens}); err != nil {
+		return nil, err
+	}
+	return feedTokens, nil
+}
+
+// CreateFeedToken returns the token and the feed URL; they are not shown
+// again.
+func (client *Client) CreateFeedToken(ctx context.Context, req models.FeedTokenRequest) (*models.FeedTokenResponse, error) {
+	feedToken := &models.FeedTokenResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/feed/", body: req, out: feedToken}); err != nil {
+		return nil, err
+	}
+	return feedToken, nil
+}
+
+func (client *Client) RevokeFeedToken(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/feed/%d", id)})
+}
+
+// GetFeed downloads the iCalendar document of a feed token. It needs no
+// access token.
+func (client *Client) GetFeed(ctx context.Context, token string) ([]byte, error) {
+	var ics []byte
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/feeds/" + url.PathEscape(token) + ".ics", out: &ics, anonymous: true}); err != nil {
+		return nil, err
+	}
+	return ics, nil
+}
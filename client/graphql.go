@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"yplanning/pkg/models"
+)
+
+// GraphQLError is an error reported by a GraphQL query, as opposed to an
+// HTTP error.
+type GraphQLError struct {
+	Messages []string
+}
+
+func (err *GraphQLError) Error() string {
+	return "yplanning: graphql: " + strings.Join(err.Messages, "; ")
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Query runs a GraphQL query and decodes its data into out. If some fields
+// failed, the others are still decoded and a *GraphQLError is returned.
+func (client *Client) Query(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	req := models.GraphQLRequest{Query: query, Variables: variables}
+	resp := &graphQLResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/graphql/", body: req, out: resp}); err != nil {
+		return err
+	}
+	if out != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return err
+		}
+	}
+	if len(resp.Errors) > 0 {
+		graphQLError := &GraphQLError{}
+		for _, queryError := range resp.Errors {
+			graphQLError.Messages = append(graphQLError.Messages, queryError.Message)
+		}
+		return graphQLError
+	}
+	return nil
+}
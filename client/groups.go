@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) CreateGroup(ctx context.Context, req models.GroupRequest) (*models.GroupResponse, error) {
+	group := &models.GroupResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/group/", body: req, out: group}); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (client *Client) GetGroups(ctx context.Context) ([]models.GroupResponse, error) {
+	var groups []models.GroupResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/group/groups", out: &groups}); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (client *Client) GetGroupByID(ctx context.Context, id uint) (*models.GroupResponse, error) {
+	group := &models.GroupResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d", id), out: group}); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (client *Client) GetGroupByCreatorID(ctx context.Context, creatorID uint) (*models.GroupResponse, error) {
+	group := &models.GroupResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/creator/%d", creatorID), out: group}); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetFreeSlots returns the periods between from and to in which no member
+// of the group has a date, lasting at least minimum.
+func (client *Client) GetFreeSlots(ctx context.Context, groupID uint, from time.Time, to time.Time, minimum time.Duration) ([]models.PeriodResponse, error) {
+	var periods []models.PeriodResponse
+	query := url.Values{
+		"from":    {from.Format(time.RFC3339)},
+		"to":      {to.Format(time.RFC3339)},
+		"minutes": {strconv.Itoa(int(minimum.Minutes()))},
+	}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/free", groupID), query: query, out: &periods}); err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (client *Client) UpdateGroup(ctx context.Context, id uint, req models.GroupRequest) (*models.GroupResponse, error) {
+	group := &models.GroupResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/group/%d", id), body: req, out: group}); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (client *Client) DeleteGroup(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/group/%d", id)})
+}
+
+// SetMemberRole changes the role of a group member to admin, member or
+// guest.
+func (client *Client) SetMemberRole(ctx context.Context, groupID uint, userID uint, role string) (*models.MembershipResponse, error) {
+	membership := &models.MembershipResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/group/%d/members/%d/role", groupID, userID), body: models.GroupRoleRequest{Role: role}, out: membership}); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// TransferOwnership makes another member the owner of a group.
+func (client *Client) TransferOwnership(ctx context.Context, groupID uint, userID uint) (*models.GroupResponse, error) {
+	group := &models.GroupResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/transfer", groupID), body: models.TransferOwnershipRequest{UserID: userID}, out: group}); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetMyGroups returns the groups of the authenticated user, with their role
+// and color in each.
+func (client *Client) GetMyGroups(ctx context.Context) ([]models.MyGroupResponse, error) {
+	var groups []models.MyGroupResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/group/mine", out: &groups}); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (client *Client) GetMembers(ctx context.Context, groupID uint) ([]models.MembershipResponse, error) {
+	var members []models.MembershipResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/members", groupID), out: &members}); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (client *Client) AddMember(ctx context.Context, groupID uint, req models.AddMemberRequest) (*models.MembershipResponse, error) {
+	membership := &models.MembershipResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/members", groupID), body: req, out: membership}); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func (client *Client) RemoveMember(ctx context.Context, groupID uint, userID uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/group/%d/members/%d", groupID, userID)})
+}
+
+func (client *Client) LeaveGroup(ctx context.Context, groupID uint) error {
+	return client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/leave", groupID)})
+}
+
+// SetGroupColor sets the authenticated user's color in a group; 0 clears
+// it.
+func (client *Client) SetGroupColor(ctx context.Context, groupID uint, colorID uint) (*models.MembershipResponse, error) {
+	membership := &models.MembershipResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/group/%d/color", groupID), body: models.MemberColorRequest{ColorID: colorID}, out: membership}); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// CreateInvite creates an invite code for a group; see JoinGroup.
+func (client *Client) CreateInvite(ctx context.Context, groupID uint, req models.GroupInviteRequest) (*models.GroupInviteResponse, error) {
+	invite := &models.GroupInviteResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/invites", groupID), body: req, out: invite}); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+func (client *Client) GetInvites(ctx context.Context, groupID uint) ([]models.GroupInviteResponse, error) {
+	var invites []models.GroupInviteResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/invites", groupID), out: &invites}); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (client *Client) RevokeInvite(ctx context.Context, groupID uint, inviteID uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/group/%d/invites/%d", groupID, inviteID)})
+}
+
+// JoinGroup joins the group of an invite code.
+func (client *Client) JoinGroup(ctx context.Context, code string) (*models.MembershipResponse, error) {
+	membership := &models.MembershipResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/group/join/" + url.PathEscape(code), out: membership}); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// DiscoverGroups lists the discoverable groups whose name contains name.
+func (client *Client) DiscoverGroups(ctx context.Context, name string) ([]models.DiscoverableGroupResponse, error) {
+	var groups []models.DiscoverableGroupResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/group/discover", query: url.Values{"name": {name}}, out: &groups}); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (client *Client) GetGroupSettings(ctx context.Context, groupID uint) (*models.GroupSettingsResponse, error) {
+	settings := &models.GroupSettingsResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/settings", groupID), out: settings}); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (client *Client) UpdateGroupSettings(ctx context.Context, groupID uint, req models.GroupSettingsRequest) (*models.GroupSettingsResponse, error) {
+	settings := &models.GroupSettingsResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/group/%d/settings", groupID), body: req, out: settings}); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// RequestToJoin asks to join a discoverable group.
+func (client *Client) RequestToJoin(ctx context.Context, groupID uint, message string) (*models.JoinRequestResponse, error) {
+	joinRequest := &models.JoinRequestResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/requests", groupID), body: models.JoinRequestRequest{Message: message}, out: joinRequest}); err != nil {
+		return nil, err
+	}
+	return joinRequest, nil
+}
+
+func (client *Client) GetMyJoinRequests(ctx context.Context) ([]models.JoinRequestResponse, error) {
+	var joinRequests []models.JoinRequestResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/group/requests/mine", out: &joinRequests}); err != nil {
+		return nil, err
+	}
+	return joinRequests, nil
+}
+
+func (client *Client) GetJoinRequests(ctx context.Context, groupID uint) ([]models.JoinRequestResponse, error) {
+	var joinRequests []models.JoinRequestResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/requests", groupID), out: &joinRequests}); err != nil {
+		return nil, err
+	}
+	return joinRequests, nil
+}
+
+func (client *Client) ApproveJoinRequest(ctx context.Context, groupID uint, requestID uint) (*models.JoinRequestResponse, error) {
+	joinRequest := &models.JoinRequestResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/requests/%d/approve", groupID, requestID), out: joinRequest}); err != nil {
+		return nil, err
+	}
+	return joinRequest, nil
+}
+
+func (client *Client) RejectJoinRequest(ctx context.Context, groupID uint, requestID uint) (*models.JoinRequestResponse, error) {
+	joinRequest := &models.JoinRequestResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/group/%d/requests/%d/reject", groupID, requestID), out: joinRequest}); err != nil {
+		return nil, err
+	}
+	return joinRequest, nil
+}
+
+// SetGroupParent nests a group in parentID, or makes it a root when
+// parentID is nil.
+func (client *Client) SetGroupParent(ctx context.Context, groupID uint, parentID *uint) (*models.GroupResponse, error) {
+	group := &models.GroupResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/group/%d/parent", groupID), body: models.GroupParentRequest{ParentID: parentID}, out: group}); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (client *Client) GetGroupTree(ctx context.Context, groupID uint) (*models.GroupTreeResponse, error) {
+	tree := &models.GroupTreeResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/tree", groupID), out: tree}); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (client *Client) GetEffectiveMembers(ctx context.Context, groupID uint) ([]models.EffectiveMemberResponse, error) {
+	var members []models.EffectiveMemberResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/group/%d/members/effective", groupID), out: &members}); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"yplanning/pkg/events"
+)
+
+// ResetEvent is the type of the event sent when the server could not
+// replay the events missed since lastEventID: the calendar must be loaded
+// again.
+const ResetEvent = "reset"
+
+// UserEvents streams the live events of a user to handle until ctx is done,
+// the connection drops or handle returns an error. Pass the ID of the last
+// event seen to resume a stream.
+func (client *Client) UserEvents(ctx context.Context, userID uint, lastEventID uint64, handle func(event events.Event) error) error {
+	return client.stream(ctx, fmt.Sprintf("/api/live/user/%d", userID), lastEventID, handle)
+}
+
+// GroupEvents streams the live events of a group's members, like
+// UserEvents.
+func (client *Client) GroupEvents(ctx context.Context, groupID uint, lastEventID uint64, handle func(event events.Event) error) error {
+	return client.stream(ctx, fmt.Sprintf("/api/live/group/%d", groupID), lastEventID, handle)
+}
+
+func (client *Client) stream(ctx context.Context, path string, lastEventID uint64, handle func(event events.Event) error) error {
+	resp, err := client.openStream(ctx, path, lastEventID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			event := events.Event{}
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return err
+			}
+			data.Reset()
+			if err := handle(event); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
+
+// openStream connects without the client timeout, which would cut the
+// stream, and refreshes the access token once if it is rejected.
+func (client *Client) openStream(ctx context.Context, path string, lastEventID uint64) (*http.Response, error) {
+	streamClient := *client.HTTPClient
+	streamClient.Timeout = 0
+	for refreshed := false; ; refreshed = true {
+		sentToken, refreshToken := client.Tokens()
+		httpRequest, err := client.newRequest(ctx, request{method: http.MethodGet, path: path}, nil, "")
+		if err != nil {
+			return nil, err
+		}
+		httpRequest.Header.Set("Accept", "text/event-stream")
+		if lastEventID > 0 {
+			httpRequest.Header.Set("Last-Event-ID", fmt.Sprint(lastEventID))
+		}
+		resp, err := streamClient.Do(httpRequest)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && !refreshed && refreshToken != "" {
+			resp.Body.Close()
+			if err := client.refresh(ctx, sentToken); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode >= 300 {
+			defer resp.Body.Close()
+			return nil, newError(resp)
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) GetSubscriptions(ctx context.Context) ([]models.SubscriptionResponse, error) {
+	var subscriptions []models.SubscriptionResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/subscription/", out: &subscriptions}); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (client *Client) CreateSubscription(ctx context.Context, req models.SubscriptionRequest) (*models.SubscriptionResponse, error) {
+	subscription := &models.SubscriptionResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/subscription/", body: req, out: subscription}); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (client *Client) UpdateSubscription(ctx context.Context, id uint, req models.SubscriptionRequest) (*models.SubscriptionResponse, error) {
+	subscription := &models.SubscriptionResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/subscription/%d", id), body: req, out: subscription}); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// SyncSubscription re-fetches the calendar now instead of waiting for its
+// refresh interval.
+func (client *Client) SyncSubscription(ctx context.Context, id uint) (*models.SubscriptionResponse, error) {
+	subscription := &models.SubscriptionResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/subscription/%d/sync", id), out: subscription}); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (client *Client) DeleteSubscription(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/subscription/%d", id)})
+}
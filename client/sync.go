@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"yplanning/pkg/models"
+)
+
+// GetChanges returns what changed since a sync token, or a full snapshot
+// when since is empty. Keep calling with the returned token while HasMore
+// is set. A token too old to resume from fails with a 410 Error.
+func (client *Client) GetChanges(ctx context.Context, since string) (*models.SyncResponse, error) {
+	changes := &models.SyncResponse{}
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/sync/", query: query, out: changes}); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Replay applies writes queued while offline and reports the outcome of
+// each one.
+func (client *Client) Replay(ctx context.Context, req models.ReplayRequest) (*models.ReplayResponse, error) {
+	replay := &models.ReplayResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/sync/replay", body: req, out: replay}); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
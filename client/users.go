@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yplanning/pkg/models"
+)
+
+// GetUser looks a user up by username or, if it is empty, by email.
+func (client *Client) GetUser(ctx context.Context, req models.GetUserRequest) (*models.UserResponse, error) {
+	user := &models.UserResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/user/", body: req, out: user}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (client *Client) GetUsers(ctx context.Context) ([]models.UserResponse, error) {
+	var users []models.UserResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/user/users", out: &users}); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (client *Client) GetUserByID(ctx context.Context, id uint) (*models.UserResponse, error) {
+	user := &models.UserResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/user/%d", id), out: user}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (client *Client) UpdateUser(ctx context.Context, id uint, req models.UpdateUserRequest) (*models.UserResponse, error) {
+	user := &models.UserResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/user/%d", id), body: req, out: user}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (client *Client) DeleteUser(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/user/%d", id)})
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yplanning/pkg/models"
+)
+
+func (client *Client) GetWebhooks(ctx context.Context) ([]models.WebhookResponse, error) {
+	var webhooks []models.WebhookResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: "/api/webhook/", out: &webhooks}); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook returns the signing secret; it is not shown again.
+func (client *Client) CreateWebhook(ctx context.Context, req models.WebhookRequest) (*models.WebhookResponse, error) {
+	webhook := &models.WebhookResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: "/api/webhook/", body: req, out: webhook}); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (client *Client) GetWebhookByID(ctx context.Context, id uint) (*models.WebhookResponse, error) {
+	webhook := &models.WebhookResponse{}
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/webhook/%d", id), out: webhook}); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (client *Client) UpdateWebhook(ctx context.Context, id uint, req models.WebhookRequest) (*models.WebhookResponse, error) {
+	webhook := &models.WebhookResponse{}
+	if err := client.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/api/webhook/%d", id), body: req, out: webhook}); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (client *Client) DeleteWebhook(ctx context.Context, id uint) error {
+	return client.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api/webhook/%d", id)})
+}
+
+func (client *Client) GetDeliveries(ctx context.Context, webhookID uint) ([]models.WebhookDeliveryResponse, error) {
+	var deliveries []models.WebhookDeliveryResponse
+	if err := client.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/webhook/%d/deliveries", webhookID), out: &deliveries}); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver sends a delivery again and returns the new delivery.
+func (client *Client) Redeliver(ctx context.Context, webhookID uint, deliveryID uint) (*models.WebhookDeliveryResponse, error) {
+	delivery := &models.WebhookDeliveryResponse{}
+	if err := client.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/webhook/%d/deliveries/%d/redeliver", webhookID, deliveryID), out: delivery}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
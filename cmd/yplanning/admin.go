@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const adminUsage = `Usage: yplanning admin <command> [-db FILE] [flags]
+
+Commands:
+  create-admin    Create an administrator, or promote an existing user
+  reset-password  Set a user's password
+  migrate         Create or update the database schema
+  vacuum          Compact the database file
+  export          Write a JSON backup of the database
+  import          Restore a JSON backup into an empty database
+`
+
+var adminCommands = map[string]command{
+	"create-admin":   createAdmin,
+	"reset-password": resetPassword,
+	"migrate":        migrate,
+	"vacuum":         vacuum,
+	"export":         exportBackup,
+	"import":         importBackup,
+}
+
+// admin runs the administration commands. They open the SQLite file
+// directly, so they must run on the server's machine.
+func admin(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, adminUsage)
+		os.Exit(2)
+	}
+	run, ok := adminCommands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "yplanning: unknown admin command %q\n\n%s", args[0], adminUsage)
+		os.Exit(2)
+	}
+	return run(args[1:])
+}
+
+// adminFlags returns the flags of an admin command, with the -db flag every
+// one of them takes.
+func adminFlags(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet("admin "+name, flag.ExitOnError)
+	path := flags.String("db", config.DefaultDatabase, "SQLite database file")
+	return flags, path
+}
+
+func openDatabase(path string) (*config.Config, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("cannot open database: %w", err)
+	}
+	cfg, err := config.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	// Lookups of missing users are expected here, not worth a log line.
+	cfg.Database.Logger = logger.Default.LogMode(logger.Silent)
+	return cfg, nil
+}
+
+func createAdmin(args []string) error {
+	flags, path := adminFlags("create-admin")
+	email := flags.String("email", "", "Email of the administrator (required)")
+	username := flags.String("username", "", "Username, when creating the user")
+	flags.Parse(args)
+	if *email == "" {
+		return errors.New("create-admin needs -email")
+	}
+	cfg, err := openDatabase(*path)
+	if err != nil {
+		return err
+	}
+
+	user, err := cfg.UserRepository.FindByEmail(*email)
+	if err == nil {
+		if _, err := cfg.UserRepository.UpdateByID(user.ID, &dbmodel.User{IsAdmin: true}); err != nil {
+			return err
+		}
+		fmt.Printf("%s is now an administrator\n", user.Email)
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if *username == "" {
+		return errors.New("no user with this email: pass -username to create one")
+	}
+	if err := models.ValidateUsername(*username); err != nil {
+		return err
+	}
+	password, err := readPassword("Password: ")
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	// The operator vouches for the address, no verification email is sent
+	verifiedAt := time.Now()
+	user, err = cfg.UserRepository.Create(&dbmodel.User{
+		Username:   *username,
+		Email:      *email,
+		Password:   hashedPassword,
+		IsAdmin:    true,
+		Verified:   true,
+		VerifiedAt: &verifiedAt,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created administrator %s (id %d)\n", user.Email, user.ID)
+	return nil
+}
+
+func resetPassword(args []string) error {
+	flags, path := adminFlags("reset-password")
+	email := flags.String("email", "", "Email of the user (required)")
+	flags.Parse(args)
+	if *email == "" {
+		return errors.New("reset-password needs -email")
+	}
+	cfg, err := openDatabase(*path)
+	if err != nil {
+		return err
+	}
+	user, err := cfg.UserRepository.FindByEmail(*email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("no user with email %s", *email)
+	} else if err != nil {
+		return err
+	}
+	password, err := readPassword("New password: ")
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.UserRepository.UpdateByID(user.ID, &dbmodel.User{Password: hashedPassword}); err != nil {
+		return err
+	}
+	fmt.Printf("Password of %s updated\n", user.Email)
+	return nil
+}
+
+// migrate creates the database if needed; opening it runs the migrations.
+func migrate(args []string) error {
+	flags, path := adminFlags("migrate")
+	flags.Parse(args)
+	_, err := config.Open(*path)
+	return err
+}
+
+func vacuum(args []string) error {
+	flags, path := adminFlags("vacuum")
+	flags.Parse(args)
+	cfg, err := openDatabase(*path)
+	if err != nil {
+		return err
+	}
+	return cfg.Database.Exec("VACUUM").Error
+}
+
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hashedPassword), err
+}
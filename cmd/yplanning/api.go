@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"yplanning/client"
+	"yplanning/pkg/models"
+)
+
+func login(args []string) error {
+	flags := flag.NewFlagSet("login", flag.ExitOnError)
+	server := flags.String("server", defaultServer, "URL of the yplanning server")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: yplanning login [-server URL] <email>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	email := flags.Arg(0)
+	password, err := readPassword("Password: ")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	apiClient := client.New(*server)
+	tokens, err := apiClient.Login(ctx, email, password)
+	if errors.Is(err, client.ErrUnauthorized) || errors.Is(err, client.ErrNotFound) {
+		return errors.New("wrong email or password")
+	} else if err != nil {
+		return err
+	}
+	user, err := apiClient.GetUser(ctx, models.GetUserRequest{Email: email})
+	if err != nil {
+		return err
+	}
+	saved := &session{
+		Server:       apiClient.BaseURL,
+		Email:        user.Email,
+		UserID:       user.ID,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+	if err := saved.save(); err != nil {
+		return err
+	}
+	fmt.Printf("Logged in to %s as %s\n", saved.Server, user.Username)
+	return nil
+}
+
+func logout(args []string) error {
+	flags := flag.NewFlagSet("logout", flag.ExitOnError)
+	all := flags.Bool("all", false, "Sign out every device, not only this one")
+	flags.Parse(args)
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	// The session is forgotten even when the server cannot be reached
+	if apiClient, _, err := newClient(); err == nil {
+		revoke := apiClient.Logout
+		if *all {
+			revoke = apiClient.LogoutAll
+		}
+		if err := revoke(context.Background()); err != nil {
+			fmt.Fprintln(os.Stderr, "yplanning: failed to sign out on the server:", err)
+		}
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func agenda(args []string) error {
+	flags := flag.NewFlagSet("agenda", flag.ExitOnError)
+	day := flags.String("date", "", "Day to list (YYYY-MM-DD), today by default")
+	flags.Parse(args)
+	apiClient, saved, err := newClient()
+	if err != nil {
+		return err
+	}
+	begin := time.Now()
+	if *day != "" {
+		if begin, err = time.ParseInLocation("2006-01-02", *day, time.Local); err != nil {
+			return fmt.Errorf("invalid date %q: use YYYY-MM-DD", *day)
+		}
+	}
+	begin = time.Date(begin.Year(), begin.Month(), begin.Day(), 0, 0, 0, 0, time.Local)
+	end := begin.AddDate(0, 0, 1)
+
+	dates, err := apiClient.GetDatesInRange(context.Background(), saved.UserID, begin, end)
+	if err != nil {
+		return err
+	}
+	if len(dates) == 0 {
+		fmt.Printf("Nothing planned on %s\n", begin.Format("Monday 2 January 2006"))
+		return nil
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].DateBegin.Before(dates[j].DateBegin) })
+	fmt.Println(begin.Format("Monday 2 January 2006"))
+	for _, date := range dates {
+		fmt.Printf("  %s-%s  %s\n", date.DateBegin.Local().Format("15:04"), date.DateEnd.Local().Format("15:04"), date.Title)
+	}
+	return nil
+}
+
+func addDate(args []string) error {
+	flags := flag.NewFlagSet("add", flag.ExitOnError)
+	title := flags.String("title", "", "Title of the date (required)")
+	body := flags.String("body", "", "Description")
+	beginFlag := flags.String("begin", "", "Start, e.g. \"2026-01-09 14:00\" (required)")
+	endFlag := flags.String("end", "", "End; defaults to begin plus -duration")
+	duration := flags.Duration("duration", time.Hour, "Length of the date when -end is not set")
+	private := flags.Bool("private", false, "Show the date as busy time to other members")
+	color := flags.Uint("color", 0, "Color ID")
+	flags.Parse(args)
+	if *title == "" || *beginFlag == "" {
+		return errors.New("add needs -title and -begin")
+	}
+	begin, err := parseTime(*beginFlag)
+	if err != nil {
+		return err
+	}
+	end := begin.Add(*duration)
+	if *endFlag != "" {
+		if end, err = parseTime(*endFlag); err != nil {
+			return err
+		}
+	}
+	apiClient, saved, err := newClient()
+	if err != nil {
+		return err
+	}
+	date, err := apiClient.CreateDate(context.Background(), models.DateRequest{
+		Title:     *title,
+		Body:      *body,
+		DateBegin: begin,
+		DateEnd:   end,
+		UserID:    saved.UserID,
+		Private:   *private,
+		ColorID:   *color,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added date %d: %s, %s-%s\n", date.ID, date.Title, date.DateBegin.Local().Format("Mon 2 Jan 15:04"), date.DateEnd.Local().Format("15:04"))
+	return nil
+}
+
+func freeSlots(args []string) error {
+	flags := flag.NewFlagSet("free", flag.ExitOnError)
+	group := flags.Uint("group", 0, "Group ID (required)")
+	fromFlag := flags.String("from", "", "Start of the search, now by default")
+	toFlag := flags.String("to", "", "End of the search, 7 days after -from by default")
+	minimum := flags.Duration("min", 30*time.Minute, "Minimum length of a slot")
+	flags.Parse(args)
+	if *group == 0 {
+		return errors.New("free needs -group")
+	}
+	from := time.Now().Truncate(time.Minute)
+	var err error
+	if *fromFlag != "" {
+		if from, err = parseTime(*fromFlag); err != nil {
+			return err
+		}
+	}
+	to := from.AddDate(0, 0, 7)
+	if *toFlag != "" {
+		if to, err = parseTime(*toFlag); err != nil {
+			return err
+		}
+	}
+	apiClient, _, err := newClient()
+	if err != nil {
+		return err
+	}
+	periods, err := apiClient.GetFreeSlots(context.Background(), *group, from, to, *minimum)
+	if err != nil {
+		return err
+	}
+	if len(periods) == 0 {
+		fmt.Println("No free slot")
+		return nil
+	}
+	for _, period := range periods {
+		begin, end := period.DateBegin.Local(), period.DateEnd.Local()
+		endLayout := "15:04"
+		if end.YearDay() != begin.YearDay() || end.Year() != begin.Year() {
+			endLayout = "Mon 2 Jan 15:04"
+		}
+		fmt.Printf("%s - %s  (%s)\n", begin.Format("Mon 2 Jan 15:04"), end.Format(endLayout), end.Sub(begin))
+	}
+	return nil
+}
+
+func exportDates(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "", "File to write, standard output by default")
+	flags.Parse(args)
+	apiClient, _, err := newClient()
+	if err != nil {
+		return err
+	}
+	ics, err := apiClient.ExportDates(context.Background())
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(ics)
+		return err
+	}
+	return os.WriteFile(*output, ics, 0o644)
+}
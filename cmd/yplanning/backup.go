@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"yplanning/database/dbmodel"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backup is the JSON form of the data export and import work on. Tables
+// are listed in the order they must be restored in; webhooks, feed tokens,
+// app passwords and the change log are not part of it.
+type backup struct {
+	Users          []dbmodel.User                 `json:"users"`
+	Colors         []dbmodel.Color                `json:"colors"`
+	Groups         []dbmodel.Group                `json:"groups"`
+	UserGroups     []dbmodel.UserGroup            `json:"user_groups"`
+	Subscriptions  []dbmodel.CalendarSubscription `json:"subscriptions"`
+	Dates          []dbmodel.Date                 `json:"dates"`
+	Availabilities []dbmodel.Availability         `json:"availabilities"`
+}
+
+// tables returns pointers to the slices of the backup, in restore order.
+func (data *backup) tables() []interface{} {
+	return []interface{}{&data.Users, &data.Colors, &data.Groups, &data.UserGroups, &data.Subscriptions, &data.Dates, &data.Availabilities}
+}
+
+func exportBackup(args []string) error {
+	flags, path := adminFlags("export")
+	output := flags.String("o", "", "File to write, standard output by default")
+	flags.Parse(args)
+	cfg, err := openDatabase(*path)
+	if err != nil {
+		return err
+	}
+	data := &backup{}
+	for _, table := range data.tables() {
+		if err := cfg.Database.Order(clause.OrderByColumn{Column: clause.Column{Name: "rowid"}}).Find(table).Error; err != nil {
+			return err
+		}
+	}
+
+	var writer io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func importBackup(args []string) error {
+	flags, path := adminFlags("import")
+	input := flags.String("i", "", "Backup file to read, standard input by default")
+	flags.Parse(args)
+
+	var reader io.Reader = os.Stdin
+	if *input != "" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	}
+	data := &backup{}
+	if err := json.NewDecoder(reader).Decode(data); err != nil {
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+
+	cfg, err := openDatabase(*path)
+	if err != nil {
+		return err
+	}
+	var users int64
+	if err := cfg.Database.Model(&dbmodel.User{}).Count(&users).Error; err != nil {
+		return err
+	}
+	if users > 0 {
+		return errors.New("the database already has users: import only restores into an empty database")
+	}
+
+	err = cfg.Database.Transaction(func(tx *gorm.DB) error {
+		for _, table := range data.tables() {
+			if err := tx.Omit(clause.Associations).CreateInBatches(table, 100).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d users, %d groups, %d dates and %d availabilities\n", len(data.Users), len(data.Groups), len(data.Dates), len(data.Availabilities))
+	return nil
+}
@@ -0,0 +1,77 @@
+// Command yplanning is a command-line client for the yplanning API, with
+// administration commands that work directly on the database.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"yplanning/client"
+)
+
+const usage = `Usage: yplanning <command> [flags]
+
+Commands using the API (run "yplanning login" first):
+  login     Sign in and remember the session
+  logout    Sign out and forget the session (-all signs out every device)
+  agenda    List the dates of a day, today by default
+  add       Add a date
+  free      Find free slots shared by the members of a group
+  export    Export your dates as an iCalendar file
+
+Administration commands, run on the server's database:
+  admin create-admin    Create an administrator, or promote an existing user
+  admin reset-password  Set a user's password
+  admin migrate         Create or update the database schema
+  admin vacuum          Compact the database file
+  admin export          Write a JSON backup of the database
+  admin import          Restore a JSON backup into an empty database
+
+Run "yplanning <command> -h" for the flags of a command.
+`
+
+type command func(args []string) error
+
+var commands = map[string]command{
+	"login":  login,
+	"logout": logout,
+	"agenda": agenda,
+	"add":    addDate,
+	"free":   freeSlots,
+	"export": exportDates,
+	"admin":  admin,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "yplanning: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "yplanning:", describe(err))
+		os.Exit(1)
+	}
+}
+
+// describe returns the message of an API error without the client's
+// prefix, or the error itself.
+func describe(err error) string {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	if apiErr.StatusCode == http.StatusUnauthorized {
+		return `session expired: run "yplanning login" again`
+	}
+	if apiErr.Message == "" {
+		return http.StatusText(apiErr.StatusCode)
+	}
+	return apiErr.Message
+}
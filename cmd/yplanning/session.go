@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"yplanning/client"
+	"yplanning/pkg/models"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// session is what login remembers between commands.
+type session struct {
+	Server       string `json:"server"`
+	Email        string `json:"email"`
+	UserID       uint   `json:"user_id"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+var errNotLoggedIn = errors.New(`not logged in: run "yplanning login" first`)
+
+func sessionPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "yplanning", "session.json"), nil
+}
+
+func loadSession() (*session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotLoggedIn
+	} else if err != nil {
+		return nil, err
+	}
+	saved := &session{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	return saved, nil
+}
+
+// save writes the session readable by the current user only, since it
+// holds the tokens.
+func (saved *session) save() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// newClient returns a client for the saved session that saves the tokens
+// again whenever they are refreshed.
+func newClient() (*client.Client, *session, error) {
+	saved, err := loadSession()
+	if err != nil {
+		return nil, nil, err
+	}
+	apiClient := client.New(saved.Server)
+	apiClient.SetTokens(saved.AccessToken, saved.RefreshToken)
+	apiClient.OnTokenRefresh = func(tokens models.TokenResponse) {
+		saved.AccessToken, saved.RefreshToken = tokens.AccessToken, tokens.RefreshToken
+		if err := saved.save(); err != nil {
+			fmt.Fprintln(os.Stderr, "yplanning: failed to save the session:", err)
+		}
+	}
+	return apiClient, saved, nil
+}
+
+// readPassword returns the YPLANNING_PASSWORD environment variable, or
+// prompts for the password on standard input.
+func readPassword(prompt string) (string, error) {
+	if password := os.Getenv("YPLANNING_PASSWORD"); password != "" {
+		return password, nil
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("no password given")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// parseTime reads a time in the local time zone unless it carries an
+// offset.
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD HH:MM or RFC 3339", value)
+}
@@ -3,35 +3,86 @@ package config
 import (
 	"yplanning/database"
 	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+	"yplanning/pkg/mail"
 
 	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
 
+// DefaultDatabase is the SQLite file used by the server.
+const DefaultDatabase = "test.db"
+
+// DefaultPublicURL is where the server is reached when PUBLIC_URL is not
+// set.
+const DefaultPublicURL = "http://localhost:8080"
+
 type Config struct {
-	GroupRepository        dbmodel.GroupRepository
-	UserRepository         dbmodel.UserRepository
-	ColorRepository        dbmodel.ColorRepository
-	AvailabilityRepository dbmodel.AvailabilityRepository
-	DateRepository         dbmodel.DateRepository
-	UserGroupRepository    dbmodel.UserGroupRepository
+	Database                *gorm.DB
+	GroupRepository         dbmodel.GroupRepository
+	UserRepository          dbmodel.UserRepository
+	ColorRepository         dbmodel.ColorRepository
+	AvailabilityRepository  dbmodel.AvailabilityRepository
+	DateRepository          dbmodel.DateRepository
+	UserGroupRepository     dbmodel.UserGroupRepository
+	FeedTokenRepository     dbmodel.FeedTokenRepository
+	SubscriptionRepository  dbmodel.CalendarSubscriptionRepository
+	AppPasswordRepository   dbmodel.AppPasswordRepository
+	WebhookRepository       dbmodel.WebhookRepository
+	DeliveryRepository      dbmodel.WebhookDeliveryRepository
+	ChangeRepository        dbmodel.ChangeRepository
+	InviteRepository        dbmodel.GroupInviteRepository
+	JoinRequestRepository   dbmodel.JoinRequestRepository
+	RefreshTokenRepository  dbmodel.RefreshTokenRepository
+	SessionRepository       dbmodel.SessionRepository
+	PasswordResetRepository dbmodel.PasswordResetRepository
+	Events                  *events.Bus
+	// Mailer sends emails. It writes them to the log until the server
+	// replaces it with the sender configured by the environment.
+	Mailer mail.Sender
+	// PublicURL is the address clients reach the server at, without a
+	// trailing slash, for the links sent by email or given to calendar
+	// apps. The request's Host header is not trusted for them.
+	PublicURL string
 }
 
 func New() (*Config, error) {
+	return Open(DefaultDatabase)
+}
+
+// Open migrates the SQLite database at path and builds the repositories
+// over it.
+func Open(path string) (*Config, error) {
 	config := &Config{}
 
-	databaseSession, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{})
+	databaseSession, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
 
 	database.Migrate(databaseSession)
 
+	config.Database = databaseSession
+
 	config.GroupRepository = dbmodel.NewGroupRepository(databaseSession)
 	config.UserRepository = dbmodel.NewUserRepository(databaseSession)
 	config.ColorRepository = dbmodel.NewColorRepository(databaseSession)
 	config.AvailabilityRepository = dbmodel.NewAvailabilityRepository(databaseSession)
 	config.DateRepository = dbmodel.NewDateRepository(databaseSession)
 	config.UserGroupRepository = dbmodel.NewUserGroupRepository(databaseSession)
+	config.FeedTokenRepository = dbmodel.NewFeedTokenRepository(databaseSession)
+	config.SubscriptionRepository = dbmodel.NewCalendarSubscriptionRepository(databaseSession)
+	config.AppPasswordRepository = dbmodel.NewAppPasswordRepository(databaseSession)
+	config.WebhookRepository = dbmodel.NewWebhookRepository(databaseSession)
+	config.DeliveryRepository = dbmodel.NewWebhookDeliveryRepository(databaseSession)
+	config.ChangeRepository = dbmodel.NewChangeRepository(databaseSession)
+	config.InviteRepository = dbmodel.NewGroupInviteRepository(databaseSession)
+	config.JoinRequestRepository = dbmodel.NewJoinRequestRepository(databaseSession)
+	config.RefreshTokenRepository = dbmodel.NewRefreshTokenRepository(databaseSession)
+	config.SessionRepository = dbmodel.NewSessionRepository(databaseSession)
+	config.PasswordResetRepository = dbmodel.NewPasswordResetRepository(databaseSession)
+	config.Events = events.NewBus()
+	config.Mailer = mail.LogSender{}
+	config.PublicURL = DefaultPublicURL
 	return config, nil
 }
@@ -22,6 +22,10 @@ func InitDatabase(db *gorm.DB) {
 }
 
 func Migrate(db *gorm.DB) {
+	// Users registered before emails were verified keep their access
+	verifyExistingUsers := db.Migrator().HasTable(&dbmodel.User{}) && !db.Migrator().HasColumn(&dbmodel.User{}, "verified")
+	// Groups created before roles existed get an owner membership for their creator
+	assignOwners := db.Migrator().HasTable(&dbmodel.UserGroup{}) && !db.Migrator().HasColumn(&dbmodel.UserGroup{}, "role")
 	err := db.AutoMigrate(
 		&dbmodel.User{},
 		&dbmodel.Availability{},
@@ -29,13 +33,44 @@ func Migrate(db *gorm.DB) {
 		&dbmodel.Date{},
 		&dbmodel.Group{},
 		&dbmodel.UserGroup{},
+		&dbmodel.FeedToken{},
+		&dbmodel.CalendarSubscription{},
+		&dbmodel.AppPassword{},
+		&dbmodel.Webhook{},
+		&dbmodel.WebhookDelivery{},
+		&dbmodel.Change{},
+		&dbmodel.GroupInvite{},
+		&dbmodel.JoinRequest{},
+		&dbmodel.Session{},
+		&dbmodel.RefreshToken{},
+		&dbmodel.PasswordReset{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+	// Hex codes are unique per owner since colors have owners
+	if db.Migrator().HasIndex(&dbmodel.Color{}, "idx_colors_hex_code") {
+		if err := db.Migrator().DropIndex(&dbmodel.Color{}, "idx_colors_hex_code"); err != nil {
+			log.Fatal("Failed to drop the color hex code index:", err)
+		}
+	}
+	if verifyExistingUsers {
+		if err := db.Exec("UPDATE users SET verified = ?, verified_at = created_at", true).Error; err != nil {
+			log.Fatal("Failed to verify existing users:", err)
+		}
+	}
 	err = db.SetupJoinTable(&dbmodel.User{}, "Groups", &dbmodel.UserGroup{})
 	if err != nil {
 		log.Fatal("Failed to setup join table:", err)
 	}
+	if assignOwners {
+		err = db.Exec("UPDATE user_groups SET role = ? WHERE EXISTS (SELECT 1 FROM groups WHERE groups.id = user_groups.group_id AND groups.creator_id = user_groups.user_id)", dbmodel.RoleOwner).Error
+		if err == nil {
+			err = db.Exec("INSERT INTO user_groups (user_id, group_id, role) SELECT creator_id, id, ? FROM groups WHERE NOT EXISTS (SELECT 1 FROM user_groups WHERE user_groups.group_id = groups.id AND user_groups.user_id = groups.creator_id)", dbmodel.RoleOwner).Error
+		}
+		if err != nil {
+			log.Fatal("Failed to assign group owners:", err)
+		}
+	}
 	log.Println("Database migrated successfully")
 }
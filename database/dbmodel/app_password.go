@@ -0,0 +1,81 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AppPassword struct {
+	gorm.Model
+	UserID       uint       `json:"user_id"`
+	User         *User      `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	Name         string     `gorm:"not null" json:"name"`
+	PasswordHash string     `gorm:"not null" json:"-"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	// Prefix is the start of the password, kept in clear to find which hash
+	// to compare a password with.
+	Prefix string `gorm:"index" json:"-"`
+}
+
+type AppPasswordRepository interface {
+	Create(appPassword *AppPassword) (*AppPassword, error)
+	FindByID(id uint) (*AppPassword, error)
+	FindByUserID(userID uint) ([]AppPassword, error)
+	FindByUserIDAndPrefix(userID uint, prefix string) ([]AppPassword, error)
+	UpdateLastUsedAt(id uint, lastUsedAt time.Time) error
+	DeleteByID(id uint) error
+}
+
+type appPasswordRepository struct {
+	DB *gorm.DB
+}
+
+func NewAppPasswordRepository(db *gorm.DB) AppPasswordRepository {
+	return &appPasswordRepository{DB: db}
+}
+
+func (appPasswordRepository *appPasswordRepository) Create(appPassword *AppPassword) (*AppPassword, error) {
+	if err := appPasswordRepository.DB.Create(appPassword).Error; err != nil {
+		return nil, err
+	}
+	return appPassword, nil
+}
+
+func (appPasswordRepository *appPasswordRepository) FindByID(id uint) (*AppPassword, error) {
+	var appPassword AppPassword
+	if err := appPasswordRepository.DB.First(&appPassword, id).Error; err != nil {
+		return nil, err
+	}
+	return &appPassword, nil
+}
+
+func (appPasswordRepository *appPasswordRepository) FindByUserID(userID uint) ([]AppPassword, error) {
+	var appPasswords []AppPassword
+	if err := appPasswordRepository.DB.Where("user_id = ?", userID).Find(&appPasswords).Error; err != nil {
+		return nil, err
+	}
+	return appPasswords, nil
+}
+
+func (appPasswordRepository *appPasswordRepository) FindByUserIDAndPrefix(userID uint, prefix string) ([]AppPassword, error) {
+	var appPasswords []AppPassword
+	if err := appPasswordRepository.DB.Where("user_id = ? AND prefix = ?", userID, prefix).Find(&appPasswords).Error; err != nil {
+		return nil, err
+	}
+	return appPasswords, nil
+}
+
+func (appPasswordRepository *appPasswordRepository) UpdateLastUsedAt(id uint, lastUsedAt time.Time) error {
+	if err := appPasswordRepository.DB.Model(&AppPassword{}).Where("id = ?", id).Update("last_used_at", lastUsedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (appPasswordRepository *appPasswordRepository) DeleteByID(id uint) error {
+	if err := appPasswordRepository.DB.Delete(&AppPassword{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
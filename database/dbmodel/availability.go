@@ -19,7 +19,11 @@ type AvailabilityRepository interface {
 	FindAll() ([]Availability, error)
 	FindByID(id uint) (*Availability, error)
 	FindByUserID(userID uint) ([]Availability, error)
+	FindByIDWithDeleted(id uint) (*Availability, error)
+	FindByUserIDsInRange(userIDs []uint, begin time.Time, end time.Time) ([]Availability, error)
+	Save(availability *Availability) error
 	UpdateByID(id uint, availability *Availability) error
+	Delete(availability *Availability) error
 	DeleteByID(id uint) error
 }
 
@@ -56,12 +60,43 @@ func (availabilityRepository *availabilityRepository) FindByID(id uint) (*Availa
 
 func (availabilityRepository *availabilityRepository) FindByUserID(userID uint) ([]Availability, error) {
 	var availabilities []Availability
-	if err := availabilityRepository.DB.Preload("User").Find(&availabilities, userID).Error; err != nil {
+	if err := availabilityRepository.DB.Preload("User").Where("user_id = ?", userID).Find(&availabilities).Error; err != nil {
 		return nil, err
 	}
 	return availabilities, nil
 }
 
+// FindByUserIDsInRange returns the availabilities of several users
+// overlapping the range. A zero begin or end leaves that side open.
+func (availabilityRepository *availabilityRepository) FindByUserIDsInRange(userIDs []uint, begin time.Time, end time.Time) ([]Availability, error) {
+	var availabilities []Availability
+	query := availabilityRepository.DB.Where("user_id IN ?", userIDs)
+	if !begin.IsZero() {
+		query = query.Where("end_time > ?", begin)
+	}
+	if !end.IsZero() {
+		query = query.Where("begin_time < ?", end)
+	}
+	if err := query.Order("begin_time").Find(&availabilities).Error; err != nil {
+		return nil, err
+	}
+	return availabilities, nil
+}
+
+func (availabilityRepository *availabilityRepository) FindByIDWithDeleted(id uint) (*Availability, error) {
+	var availability Availability
+	if err := availabilityRepository.DB.Unscoped().First(&availability, id).Error; err != nil {
+		return nil, err
+	}
+	return &availability, nil
+}
+
+// Save saves an availability if it is still at the version (UpdatedAt) it
+// was read at, otherwise it returns ErrModified.
+func (availabilityRepository *availabilityRepository) Save(availability *Availability) error {
+	return updateVersion(availabilityRepository.DB, availability, availability.UpdatedAt)
+}
+
 func (availabilityRepository *availabilityRepository) UpdateByID(id uint, availability *Availability) error {
 	if err := availabilityRepository.DB.Model(&Availability{}).Where("id = ?", id).Updates(availability).Error; err != nil {
 		return err
@@ -69,6 +104,12 @@ func (availabilityRepository *availabilityRepository) UpdateByID(id uint, availa
 	return nil
 }
 
+// Delete deletes an availability if it is still at the version it was read
+// at, otherwise it returns ErrModified.
+func (availabilityRepository *availabilityRepository) Delete(availability *Availability) error {
+	return deleteVersion(availabilityRepository.DB, &Availability{Model: gorm.Model{ID: availability.ID}}, availability.UpdatedAt)
+}
+
 func (availabilityRepository *availabilityRepository) DeleteByID(id uint) error {
 	if err := availabilityRepository.DB.Delete(&Availability{}, id).Error; err != nil {
 		return err
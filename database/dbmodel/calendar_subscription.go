@@ -0,0 +1,116 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CalendarSubscription struct {
+	gorm.Model
+	UserID         uint       `json:"user_id"`
+	User           *User      `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	Name           string     `gorm:"not null" json:"name"`
+	URL            string     `gorm:"not null" json:"url"`
+	RefreshMinutes int        `gorm:"not null" json:"refresh_minutes"`
+	ETag           string     `json:"-"`
+	LastModified   string     `json:"-"`
+	LastSyncedAt   *time.Time `json:"last_synced_at"`
+	LastError      string     `json:"last_error"`
+}
+
+func (subscription *CalendarSubscription) IsDue(now time.Time) bool {
+	if subscription.LastSyncedAt == nil {
+		return true
+	}
+	return !subscription.LastSyncedAt.Add(time.Duration(subscription.RefreshMinutes) * time.Minute).After(now)
+}
+
+type CalendarSubscriptionRepository interface {
+	Create(subscription *CalendarSubscription) (*CalendarSubscription, error)
+	FindAll() ([]CalendarSubscription, error)
+	FindByID(id uint) (*CalendarSubscription, error)
+	FindByUserID(userID uint) ([]CalendarSubscription, error)
+	UpdateByID(id uint, subscription *CalendarSubscription) error
+	UpdateSyncStatus(id uint, syncedAt time.Time, etag string, lastModified string, lastError string) error
+	ResetSyncStatusByID(id uint) error
+	DeleteByID(id uint) error
+}
+
+type calendarSubscriptionRepository struct {
+	DB *gorm.DB
+}
+
+func NewCalendarSubscriptionRepository(db *gorm.DB) CalendarSubscriptionRepository {
+	return &calendarSubscriptionRepository{DB: db}
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) Create(subscription *CalendarSubscription) (*CalendarSubscription, error) {
+	if err := calendarSubscriptionRepository.DB.Create(subscription).Error; err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) FindAll() ([]CalendarSubscription, error) {
+	var subscriptions []CalendarSubscription
+	if err := calendarSubscriptionRepository.DB.Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) FindByID(id uint) (*CalendarSubscription, error) {
+	var subscription CalendarSubscription
+	if err := calendarSubscriptionRepository.DB.First(&subscription, id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) FindByUserID(userID uint) ([]CalendarSubscription, error) {
+	var subscriptions []CalendarSubscription
+	if err := calendarSubscriptionRepository.DB.Where("user_id = ?", userID).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) UpdateByID(id uint, subscription *CalendarSubscription) error {
+	if err := calendarSubscriptionRepository.DB.Model(&CalendarSubscription{}).Where("id = ?", id).Updates(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) UpdateSyncStatus(id uint, syncedAt time.Time, etag string, lastModified string, lastError string) error {
+	if err := calendarSubscriptionRepository.DB.Model(&CalendarSubscription{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_synced_at": syncedAt,
+		"e_tag":          etag,
+		"last_modified":  lastModified,
+		"last_error":     lastError,
+	}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ResetSyncStatusByID forgets when and what was last fetched, so that the
+// subscription is due at once and fetched in full.
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) ResetSyncStatusByID(id uint) error {
+	if err := calendarSubscriptionRepository.DB.Model(&CalendarSubscription{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_synced_at": nil,
+		"e_tag":          "",
+		"last_modified":  "",
+	}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (calendarSubscriptionRepository *calendarSubscriptionRepository) DeleteByID(id uint) error {
+	if err := calendarSubscriptionRepository.DB.Delete(&CalendarSubscription{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Change is one entry of the change log behind incremental sync. Its ID is
+// a monotonic sequence number; the resource itself is looked up when the
+// log is read, so only the latest state of each resource is sent.
+type Change struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Resource   string    `gorm:"not null" json:"resource"`
+	ResourceID uint      `gorm:"not null" json:"resource_id"`
+	Action     string    `gorm:"not null" json:"action"`
+	UserID     uint      `gorm:"index" json:"user_id"`
+	GroupID    uint      `gorm:"index" json:"group_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ChangeRepository interface {
+	Create(change *Change) (*Change, error)
+	FindByID(id uint) (*Change, error)
+	FindAfter(id uint, limit int) ([]Change, error)
+	FindLatestID() (uint, error)
+}
+
+type changeRepository struct {
+	DB *gorm.DB
+}
+
+func NewChangeRepository(db *gorm.DB) ChangeRepository {
+	return &changeRepository{DB: db}
+}
+
+func (changeRepository *changeRepository) Create(change *Change) (*Change, error) {
+	if err := changeRepository.DB.Create(change).Error; err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (changeRepository *changeRepository) FindByID(id uint) (*Change, error) {
+	var change Change
+	if err := changeRepository.DB.First(&change, id).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (changeRepository *changeRepository) FindAfter(id uint, limit int) ([]Change, error) {
+	var changes []Change
+	if err := changeRepository.DB.Where("id > ?", id).Order("id").Limit(limit).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (changeRepository *changeRepository) FindLatestID() (uint, error) {
+	var id uint
+	if err := changeRepository.DB.Model(&Change{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
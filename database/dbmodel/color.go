@@ -2,18 +2,24 @@ package dbmodel
 
 import "gorm.io/gorm"
 
+// Color is a color users give their dates and memberships. Colors created
+// by administrators, or before colors had owners, are shared by everyone;
+// the others belong to the user who created them.
 type Color struct {
 	gorm.Model
-	HexCode string  `gorm:"uniqueIndex;not null;size:7" json:"hex_code"`
+	HexCode string  `gorm:"uniqueIndex:idx_colors_owner_hex_code;not null;size:7" json:"hex_code"`
 	Name    string  `gorm:"not null" json:"name"`
 	Users   []User  `gorm:"many2many:user_group;" json:"users"`
 	Groups  []Group `gorm:"many2many:user_group;" json:"groups"`
+	// UserID is the owner, 0 for shared colors.
+	UserID uint `gorm:"uniqueIndex:idx_colors_owner_hex_code" json:"user_id"`
 }
 
 type ColorRepository interface {
 	Create(color *Color) (*Color, error)
 	FindAll() ([]Color, error)
 	FindByID(id uint) (*Color, error)
+	FindByIDs(ids []uint) ([]Color, error)
 	FindByHexCode(hexCode string) (*Color, error)
 	UpdateByID(id uint, color *Color) error
 	DeleteByID(id uint) error
@@ -50,6 +56,14 @@ func (colorRepository *colorRepository) FindByID(id uint) (*Color, error) {
 	return &color, nil
 }
 
+func (colorRepository *colorRepository) FindByIDs(ids []uint) ([]Color, error) {
+	var colors []Color
+	if err := colorRepository.DB.Where("id IN ?", ids).Find(&colors).Error; err != nil {
+		return nil, err
+	}
+	return colors, nil
+}
+
 func (colorRepository *colorRepository) FindByHexCode(hexCode string) (*Color, error) {
 	var color Color
 	if err := colorRepository.DB.Where("hex_code = ?", hexCode).First(&color).Error; err != nil {
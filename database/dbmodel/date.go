@@ -1,24 +1,46 @@
 package dbmodel
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrOccurrence is returned for writes to a single occurrence of a
+// recurring date: occurrences are regenerated from their recurring date,
+// which is the one to change.
+var ErrOccurrence = errors.New("date is an occurrence of a recurring date")
+
 type Date struct {
 	gorm.Model
-	Title        string    `gorm:"not null" json:"title"`
-	Body         string    `gorm:"not null" json:"body"`
-	UserID       uint      `json:"user_id"`
-	User         *User     `gorm:"not null;constraint:OnDelete:CASCADE;"`
-	BeginTime    time.Time `json:"begin_time"`
-	EndTime      time.Time `json:"end_time"`
-	Private      bool      `json:"private"`
-	RecurrenceID uint      `json:"recurrence_id"`
-	Recurrence   *Date     `gorm:"constraint:OnDelete:SET NULL;"`
-	ColorID      uint      `json:"color_id"`
-	Color        *Color    `gorm:"null;constraint:OnDelete:SET NULL;"`
+	Title          string    `gorm:"not null" json:"title"`
+	Body           string    `gorm:"not null" json:"body"`
+	UserID         uint      `json:"user_id"`
+	User           *User     `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	BeginTime      time.Time `json:"begin_time"`
+	EndTime        time.Time `json:"end_time"`
+	Private        bool      `json:"private"`
+	RecurrenceID   uint      `json:"recurrence_id"`
+	Recurrence     *Date     `gorm:"constraint:OnDelete:SET NULL;"`
+	ColorID        uint      `json:"color_id"`
+	Color          *Color    `gorm:"null;constraint:OnDelete:SET NULL;"`
+	UID            string    `gorm:"index" json:"uid"`
+	RRule          string    `json:"rrule"`
+	ExDate         string    `json:"exdate"`
+	SubscriptionID uint      `gorm:"index" json:"subscription_id"`
+}
+
+// HasSameContent reports whether two dates describe the same event, ignoring
+// database bookkeeping such as IDs and timestamps.
+func (date *Date) HasSameContent(other *Date) bool {
+	return date.Title == other.Title &&
+		date.Body == other.Body &&
+		date.BeginTime.Equal(other.BeginTime) &&
+		date.EndTime.Equal(other.EndTime) &&
+		date.Private == other.Private &&
+		date.RRule == other.RRule &&
+		date.ExDate == other.ExDate
 }
 
 type DateRepository interface {
@@ -27,9 +49,18 @@ type DateRepository interface {
 	FindByID(id uint) (*Date, error)
 	FindByUserID(userID uint) ([]Date, error)
 	FindByRecurrenceID(recurrenceID uint) (*Date, error)
+	FindAllByRecurrenceID(recurrenceID uint) ([]Date, error)
+	FindByUIDAndUserID(uid string, userID uint) (*Date, error)
+	FindBySubscriptionID(subscriptionID uint) ([]Date, error)
 	FindByDayRange(begin time.Time, end time.Time, userID uint) ([]Date, error)
-	UpdateByID(id uint, date *Date) error
-	DeleteByID(id uint) error
+	FindByUserIDsInRange(userIDs []uint, begin time.Time, end time.Time) ([]Date, error)
+	FindByIDWithDeleted(id uint) (*Date, error)
+	FindOccurrencesChangedSince(userIDs []uint, since time.Time) ([]Date, error)
+	ReplaceOccurrences(master *Date, occurrences []time.Time) error
+	CreateWithOccurrences(master *Date, occurrences []time.Time) (*Date, error)
+	SaveWithOccurrences(master *Date, occurrences []time.Time) error
+	DeleteWithOccurrences(date *Date) error
+	DeleteBySubscriptionID(subscriptionID uint) error
 }
 
 type dateRepository struct {
@@ -65,7 +96,7 @@ func (dateRepository *dateRepository) FindByID(id uint) (*Date, error) {
 
 func (dateRepository *dateRepository) FindByUserID(userID uint) ([]Date, error) {
 	var dates []Date
-	if err := dateRepository.DB.Preload("User").Find(&dates, userID).Error; err != nil {
+	if err := dateRepository.DB.Preload("User").Where("user_id = ?", userID).Find(&dates).Error; err != nil {
 		return nil, err
 	}
 	return dates, nil
@@ -79,6 +110,32 @@ func (dateRepository *dateRepository) FindByRecurrenceID(recurrenceID uint) (*Da
 	return &date, nil
 }
 
+func (dateRepository *dateRepository) FindAllByRecurrenceID(recurrenceID uint) ([]Date, error) {
+	var dates []Date
+	if err := dateRepository.DB.Where("recurrence_id = ?", recurrenceID).Order("begin_time").Find(&dates).Error; err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// FindByUIDAndUserID prefers the user's own date to a subscription mirror
+// with the same UID.
+func (dateRepository *dateRepository) FindByUIDAndUserID(uid string, userID uint) (*Date, error) {
+	var date Date
+	if err := dateRepository.DB.Where("uid = ? AND user_id = ?", uid, userID).Order("subscription_id").First(&date).Error; err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func (dateRepository *dateRepository) FindBySubscriptionID(subscriptionID uint) ([]Date, error) {
+	var dates []Date
+	if err := dateRepository.DB.Where("subscription_id = ?", subscriptionID).Find(&dates).Error; err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
 func (dateRepository *dateRepository) FindByDayRange(begin time.Time, end time.Time, userID uint) ([]Date, error) {
 	var dates []Date
 	if err := dateRepository.DB.Preload("User").Where("begin_time >= ? AND end_time <= ? AND user_id = ?", begin, end, userID).Find(&dates).Error; err != nil {
@@ -87,15 +144,166 @@ func (dateRepository *dateRepository) FindByDayRange(begin time.Time, end time.T
 	return dates, nil
 }
 
-func (dateRepository *dateRepository) UpdateByID(id uint, date *Date) error {
-	if err := dateRepository.DB.Model(&Date{}).Where("id = ?", id).Updates(date).Error; err != nil {
+// FindByUserIDsInRange returns the dates of several users overlapping the
+// range. A zero begin or end leaves that side of the range open.
+func (dateRepository *dateRepository) FindByUserIDsInRange(userIDs []uint, begin time.Time, end time.Time) ([]Date, error) {
+	var dates []Date
+	query := dateRepository.DB.Where("user_id IN ?", userIDs)
+	if !begin.IsZero() {
+		query = query.Where("end_time > ?", begin)
+	}
+	if !end.IsZero() {
+		query = query.Where("begin_time < ?", end)
+	}
+	if err := query.Order("begin_time").Find(&dates).Error; err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// FindByIDWithDeleted also returns soft-deleted dates, for callers that
+// need to tell a deleted date from one that never existed.
+func (dateRepository *dateRepository) FindByIDWithDeleted(id uint) (*Date, error) {
+	var date Date
+	if err := dateRepository.DB.Unscoped().First(&date, id).Error; err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// FindOccurrencesChangedSince returns the materialised occurrences of the
+// given users' recurring dates created, updated or deleted after since,
+// soft-deleted ones included.
+func (dateRepository *dateRepository) FindOccurrencesChangedSince(userIDs []uint, since time.Time) ([]Date, error) {
+	var dates []Date
+	if err := dateRepository.DB.Unscoped().Where("recurrence_id <> 0 AND user_id IN ? AND (updated_at > ? OR deleted_at > ?)", userIDs, since, since).Find(&dates).Error; err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// ReplaceOccurrences swaps the materialised instances of a recurring date
+// for new ones. The master keeps its own begin time; every other occurrence
+// becomes a copy of it pointing back through RecurrenceID.
+func (dateRepository *dateRepository) ReplaceOccurrences(master *Date, occurrences []time.Time) error {
+	return dateRepository.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceOccurrences(tx, master, occurrences)
+	})
+}
+
+// CreateWithOccurrences creates a date and, if it is recurring, its
+// instances, or nothing if any of them fails.
+func (dateRepository *dateRepository) CreateWithOccurrences(master *Date, occurrences []time.Time) (*Date, error) {
+	err := dateRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkNotOccurrence(tx, master); err != nil {
+			return err
+		}
+		if err := tx.Create(master).Error; err != nil {
+			return err
+		}
+		if master.RRule == "" {
+			return nil
+		}
+		return replaceOccurrences(tx, master, occurrences)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return master, nil
+}
+
+// SaveWithOccurrences saves a date and replaces the instances it has or had
+// as a recurring date, or leaves both as they were if any of it fails. The
+// date must still be at the version (UpdatedAt) it was read at, otherwise
+// ErrModified is returned.
+func (dateRepository *dateRepository) SaveWithOccurrences(master *Date, occurrences []time.Time) error {
+	return dateRepository.DB.Transaction(func(tx *gorm.DB) error {
+		var previous Date
+		if err := tx.First(&previous, master.ID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrModified
+		} else if err != nil {
+			return err
+		}
+		if err := checkNotOccurrence(tx, &previous); err != nil {
+			return err
+		}
+		if err := checkNotOccurrence(tx, master); err != nil {
+			return err
+		}
+		if err := updateVersion(tx, master, master.UpdatedAt); err != nil {
+			return err
+		}
+		if master.RRule == "" && previous.RRule == "" {
+			return nil
+		}
+		return replaceOccurrences(tx, master, occurrences)
+	})
+}
+
+// DeleteWithOccurrences deletes a date and, if it is recurring, its
+// instances, or nothing if any of it fails. The date must still be at the
+// version it was read at, otherwise ErrModified is returned.
+func (dateRepository *dateRepository) DeleteWithOccurrences(date *Date) error {
+	return dateRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkNotOccurrence(tx, date); err != nil {
+			return err
+		}
+		if err := deleteVersion(tx, &Date{Model: gorm.Model{ID: date.ID}}, date.UpdatedAt); err != nil {
+			return err
+		}
+		if date.RRule == "" {
+			return nil
+		}
+		return tx.Where("recurrence_id = ?", date.ID).Delete(&Date{}).Error
+	})
+}
+
+// checkNotOccurrence returns ErrOccurrence if date points to a recurring
+// date. Dates may also point to a date without an RRULE, which links them
+// without generating them.
+func checkNotOccurrence(tx *gorm.DB, date *Date) error {
+	if date.RecurrenceID == 0 {
+		return nil
+	}
+	var recurring int64
+	if err := tx.Model(&Date{}).Where("id = ? AND r_rule <> ''", date.RecurrenceID).Count(&recurring).Error; err != nil {
 		return err
 	}
+	if recurring != 0 {
+		return ErrOccurrence
+	}
+	return nil
+}
+
+func replaceOccurrences(tx *gorm.DB, master *Date, occurrences []time.Time) error {
+	if err := tx.Where("recurrence_id = ?", master.ID).Delete(&Date{}).Error; err != nil {
+		return err
+	}
+	duration := master.EndTime.Sub(master.BeginTime)
+	for _, occurrence := range occurrences {
+		if occurrence.Equal(master.BeginTime) {
+			continue
+		}
+		instance := &Date{
+			Title:          master.Title,
+			Body:           master.Body,
+			UserID:         master.UserID,
+			BeginTime:      occurrence,
+			EndTime:        occurrence.Add(duration),
+			Private:        master.Private,
+			RecurrenceID:   master.ID,
+			ColorID:        master.ColorID,
+			SubscriptionID: master.SubscriptionID,
+		}
+		if err := tx.Create(instance).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (dateRepository *dateRepository) DeleteByID(id uint) error {
-	if err := dateRepository.DB.Delete(&Date{}, id).Error; err != nil {
+func (dateRepository *dateRepository) DeleteBySubscriptionID(subscriptionID uint) error {
+	if err := dateRepository.DB.Where("subscription_id = ?", subscriptionID).Delete(&Date{}).Error; err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,173 @@
+package dbmodel_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+)
+
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func createTestUser(t *testing.T, cfg *config.Config, username string) *dbmodel.User {
+	t.Helper()
+	user, err := cfg.UserRepository.Create(&dbmodel.User{Username: username, Email: username + "@example.com", Password: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// createSeries creates a daily date with three occurrences.
+func createSeries(t *testing.T, cfg *config.Config, userID uint) *dbmodel.Date {
+	t.Helper()
+	begin := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	master := &dbmodel.Date{Title: "Standup", UserID: userID, BeginTime: begin, EndTime: begin.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=3"}
+	master, err := cfg.DateRepository.CreateWithOccurrences(master, []time.Time{begin, begin.AddDate(0, 0, 1), begin.AddDate(0, 0, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return master
+}
+
+func occurrencesOf(t *testing.T, cfg *config.Config, master *dbmodel.Date) []dbmodel.Date {
+	t.Helper()
+	occurrences, err := cfg.DateRepository.FindAllByRecurrenceID(master.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return occurrences
+}
+
+func TestSaveWithOccurrencesRegeneratesSeries(t *testing.T) {
+	cfg := newTestConfig(t)
+	user := createTestUser(t, cfg, "alice")
+	master := createSeries(t, cfg, user.ID)
+	if occurrences := occurrencesOf(t, cfg, master); len(occurrences) != 2 {
+		t.Fatalf("got %d occurrences, want 2", len(occurrences))
+	}
+
+	master.Title = "Daily"
+	begin := master.BeginTime.Add(time.Hour)
+	master.BeginTime, master.EndTime = begin, begin.Add(time.Hour)
+	if err := cfg.DateRepository.SaveWithOccurrences(master, []time.Time{begin, begin.AddDate(0, 0, 1)}); err != nil {
+		t.Fatal(err)
+	}
+	occurrences := occurrencesOf(t, cfg, master)
+	if len(occurrences) != 1 || occurrences[0].Title != "Daily" || !occurrences[0].BeginTime.Equal(begin.AddDate(0, 0, 1)) {
+		t.Errorf("got occurrences %+v", occurrences)
+	}
+
+	master.RRule = ""
+	if err := cfg.DateRepository.SaveWithOccurrences(master, []time.Time{begin}); err != nil {
+		t.Fatal(err)
+	}
+	if occurrences := occurrencesOf(t, cfg, master); len(occurrences) != 0 {
+		t.Errorf("got %d occurrences after the RRULE was removed, want 0", len(occurrences))
+	}
+}
+
+func TestDeleteWithOccurrences(t *testing.T) {
+	cfg := newTestConfig(t)
+	user := createTestUser(t, cfg, "alice")
+	master := createSeries(t, cfg, user.ID)
+
+	if err := cfg.DateRepository.DeleteWithOccurrences(master); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.DateRepository.FindByID(master.ID); err == nil {
+		t.Error("recurring date still exists")
+	}
+	if occurrences := occurrencesOf(t, cfg, master); len(occurrences) != 0 {
+		t.Errorf("got %d orphaned occurrences, want 0", len(occurrences))
+	}
+}
+
+func TestLinkedDatesAreNotOccurrences(t *testing.T) {
+	cfg := newTestConfig(t)
+	user := createTestUser(t, cfg, "alice")
+	begin := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	first, err := cfg.DateRepository.CreateWithOccurrences(&dbmodel.Date{Title: "First", UserID: user.ID, BeginTime: begin, EndTime: begin.Add(time.Hour)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linked, err := cfg.DateRepository.CreateWithOccurrences(&dbmodel.Date{Title: "Second", UserID: user.ID, BeginTime: begin.AddDate(0, 0, 7), EndTime: begin.AddDate(0, 0, 7).Add(time.Hour), RecurrenceID: first.ID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.Title = "First session"
+	if err := cfg.DateRepository.SaveWithOccurrences(first, []time.Time{first.BeginTime}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DateRepository.DeleteWithOccurrences(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.DateRepository.FindByID(linked.ID); err != nil {
+		t.Errorf("linked date was deleted with the date it points to: %v", err)
+	}
+}
+
+func TestOccurrencesCannotBeWrittenAlone(t *testing.T) {
+	cfg := newTestConfig(t)
+	user := createTestUser(t, cfg, "alice")
+	master := createSeries(t, cfg, user.ID)
+	occurrence := occurrencesOf(t, cfg, master)[0]
+
+	occurrence.Title = "Moved"
+	if err := cfg.DateRepository.SaveWithOccurrences(&occurrence, nil); !errors.Is(err, dbmodel.ErrOccurrence) {
+		t.Errorf("save = %v, want ErrOccurrence", err)
+	}
+	if err := cfg.DateRepository.DeleteWithOccurrences(&occurrence); !errors.Is(err, dbmodel.ErrOccurrence) {
+		t.Errorf("delete = %v, want ErrOccurrence", err)
+	}
+	extra := &dbmodel.Date{Title: "Extra", UserID: user.ID, BeginTime: master.BeginTime, EndTime: master.EndTime, RecurrenceID: master.ID}
+	if _, err := cfg.DateRepository.CreateWithOccurrences(extra, nil); !errors.Is(err, dbmodel.ErrOccurrence) {
+		t.Errorf("create = %v, want ErrOccurrence", err)
+	}
+	if occurrences := occurrencesOf(t, cfg, master); len(occurrences) != 2 {
+		t.Errorf("got %d occurrences, want 2", len(occurrences))
+	}
+}
+
+func TestStaleWritesAreRefused(t *testing.T) {
+	cfg := newTestConfig(t)
+	user := createTestUser(t, cfg, "alice")
+	master := createSeries(t, cfg, user.ID)
+	first, err := cfg.DateRepository.FindByID(master.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := *first
+
+	// Both writers read the same version before either wrote it.
+	time.Sleep(time.Millisecond)
+	first.Title = "First"
+	if err := cfg.DateRepository.SaveWithOccurrences(first, []time.Time{first.BeginTime}); err != nil {
+		t.Fatal(err)
+	}
+	second.Title = "Second"
+	if err := cfg.DateRepository.SaveWithOccurrences(&second, []time.Time{second.BeginTime}); !errors.Is(err, dbmodel.ErrModified) {
+		t.Errorf("stale save = %v, want ErrModified", err)
+	}
+	if err := cfg.DateRepository.DeleteWithOccurrences(&second); !errors.Is(err, dbmodel.ErrModified) {
+		t.Errorf("stale delete = %v, want ErrModified", err)
+	}
+	current, err := cfg.DateRepository.FindByID(master.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Title != "First" {
+		t.Errorf("got title %q, want %q", current.Title, "First")
+	}
+}
@@ -0,0 +1,80 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FeedToken struct {
+	gorm.Model
+	UserID         uint       `json:"user_id"`
+	User           *User      `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	GroupID        uint       `json:"group_id"`
+	Name           string     `gorm:"not null" json:"name"`
+	TokenHash      string     `gorm:"uniqueIndex;not null" json:"-"`
+	IncludePrivate bool       `json:"include_private"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+}
+
+type FeedTokenRepository interface {
+	Create(feedToken *FeedToken) (*FeedToken, error)
+	FindByID(id uint) (*FeedToken, error)
+	FindByUserID(userID uint) ([]FeedToken, error)
+	FindByTokenHash(tokenHash string) (*FeedToken, error)
+	UpdateLastUsedAt(id uint, lastUsedAt time.Time) error
+	DeleteByID(id uint) error
+}
+
+type feedTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewFeedTokenRepository(db *gorm.DB) FeedTokenRepository {
+	return &feedTokenRepository{DB: db}
+}
+
+func (feedTokenRepository *feedTokenRepository) Create(feedToken *FeedToken) (*FeedToken, error) {
+	if err := feedTokenRepository.DB.Create(feedToken).Error; err != nil {
+		return nil, err
+	}
+	return feedToken, nil
+}
+
+func (feedTokenRepository *feedTokenRepository) FindByID(id uint) (*FeedToken, error) {
+	var feedToken FeedToken
+	if err := feedTokenRepository.DB.First(&feedToken, id).Error; err != nil {
+		return nil, err
+	}
+	return &feedToken, nil
+}
+
+func (feedTokenRepository *feedTokenRepository) FindByUserID(userID uint) ([]FeedToken, error) {
+	var feedTokens []FeedToken
+	if err := feedTokenRepository.DB.Where("user_id = ?", userID).Find(&feedTokens).Error; err != nil {
+		return nil, err
+	}
+	return feedTokens, nil
+}
+
+func (feedTokenRepository *feedTokenRepository) FindByTokenHash(tokenHash string) (*FeedToken, error) {
+	var feedToken FeedToken
+	if err := feedTokenRepository.DB.Where("token_hash = ?", tokenHash).First(&feedToken).Error; err != nil {
+		return nil, err
+	}
+	return &feedToken, nil
+}
+
+func (feedTokenRepository *feedTokenRepository) UpdateLastUsedAt(id uint, lastUsedAt time.Time) error {
+	if err := feedTokenRepository.DB.Model(&FeedToken{}).Where("id = ?", id).Update("last_used_at", lastUsedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (feedTokenRepository *feedTokenRepository) DeleteByID(id uint) error {
+	if err := feedTokenRepository.DB.Delete(&FeedToken{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
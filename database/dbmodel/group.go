@@ -1,6 +1,14 @@
 package dbmodel
 
-import "gorm.io/gorm"
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrGroupCycle is returned by SetParentByID when the new parent is the
+// group itself or one of its descendants.
+var ErrGroupCycle = errors.New("group cannot be nested in itself or its descendants")
 
 type Group struct {
 	gorm.Model
@@ -9,6 +17,14 @@ type Group struct {
 	Creator   *User   `gorm:"not null;constraint:OnDelete:CASCADE;"`
 	Users     []User  `gorm:"many2many:user_group;" json:"users"`
 	Colors    []Color `gorm:"many2many:user_group;" json:"colors"`
+	// Discoverable groups are listed to everyone, who may ask to join them.
+	Discoverable bool `json:"discoverable"`
+	// AutoApproveDomains is a comma-separated list of email domains whose
+	// users' join requests are approved at once.
+	AutoApproveDomains string `json:"auto_approve_domains"`
+	// ParentID nests the group in another one. The members of a group are
+	// implicitly members of all its ancestors.
+	ParentID *uint `gorm:"index" json:"parent_id"`
 }
 
 type GroupRepository interface {
@@ -16,6 +32,18 @@ type GroupRepository interface {
 	FindAll() ([]Group, error)
 	FindByID(id uint) (*Group, error)
 	FindByCreatorID(creatorID uint) (*Group, error)
+	FindByIDs(ids []uint) ([]Group, error)
+	FindByMemberID(userID uint) ([]Group, error)
+	FindByCreatorIDs(creatorIDs []uint) ([]Group, error)
+	FindMemberIDs(id uint) ([]uint, error)
+	IsMember(id uint, userID uint) (bool, error)
+	FindPeerIDs(userID uint) ([]uint, error)
+	FindByIDWithDeleted(id uint) (*Group, error)
+	FindDiscoverable(name string) ([]Group, error)
+	FindAncestorIDs(id uint) ([]uint, error)
+	FindSubtree(id uint) ([]Group, error)
+	SetParentByID(id uint, parentID *uint) error
+	UpdateSettingsByID(id uint, discoverable bool, autoApproveDomains string) error
 	UpdateByID(id uint, group *Group) (*Group, error)
 	DeleteByID(id uint) error
 }
@@ -28,8 +56,16 @@ func NewGroupRepository(db *gorm.DB) GroupRepository {
 	return &groupRepository{DB: db}
 }
 
+// Create stores a group along with the membership that makes its creator
+// the owner.
 func (groupRepository *groupRepository) Create(group *Group) (*Group, error) {
-	if err := groupRepository.DB.Create(group).Error; err != nil {
+	err := groupRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		return tx.Create(&UserGroup{UserID: group.CreatorID, GroupID: group.ID, Role: RoleOwner}).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 	return group, nil
@@ -59,6 +95,186 @@ func (groupRepository *groupRepository) FindByCreatorID(creatorID uint) (*Group,
 	return &group, nil
 }
 
+func (groupRepository *groupRepository) FindByIDs(ids []uint) ([]Group, error) {
+	var groups []Group
+	if err := groupRepository.DB.Where("id IN ?", ids).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (groupRepository *groupRepository) FindByCreatorIDs(creatorIDs []uint) ([]Group, error) {
+	var groups []Group
+	if err := groupRepository.DB.Where("creator_id IN ?", creatorIDs).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// FindByMemberID returns the groups a user created or was added to, along
+// with their ancestors.
+func (groupRepository *groupRepository) FindByMemberID(userID uint) ([]Group, error) {
+	var groupIDs []uint
+	members := groupRepository.DB.Model(&UserGroup{}).Select("group_id").Where("user_id = ?", userID)
+	if err := groupRepository.DB.Model(&Group{}).Where("creator_id = ? OR id IN (?)", userID, members).Pluck("id", &groupIDs).Error; err != nil {
+		return nil, err
+	}
+	for _, groupID := range groupIDs {
+		ancestorIDs, err := ancestorIDs(groupRepository.DB, groupID)
+		if err != nil {
+			return nil, err
+		}
+		groupIDs = append(groupIDs, ancestorIDs...)
+	}
+	var groups []Group
+	if err := groupRepository.DB.Where("id IN ?", groupIDs).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// FindMemberIDs returns the creator of a group followed by its other
+// members, those of its descendants included.
+func (groupRepository *groupRepository) FindMemberIDs(id uint) ([]uint, error) {
+	group, err := groupRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs, err := descendantIDs(groupRepository.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []uint
+	if err := groupRepository.DB.Model(&UserGroup{}).Distinct("user_id").Where("group_id IN ? AND user_id <> ?", append(groupIDs, id), group.CreatorID).Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return append([]uint{group.CreatorID}, userIDs...), nil
+}
+
+// IsMember reports whether userID belongs to a group or to one of its
+// descendants.
+func (groupRepository *groupRepository) IsMember(id uint, userID uint) (bool, error) {
+	groupIDs, err := descendantIDs(groupRepository.DB, id)
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	members := groupRepository.DB.Model(&UserGroup{}).Select("group_id").Where("user_id = ?", userID)
+	if err := groupRepository.DB.Model(&Group{}).Where("id IN ? AND (creator_id = ? OR id IN (?))", append(groupIDs, id), userID, members).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindPeerIDs returns the users who share a group with userID, leaving out
+// the groups where either of them is only a guest. Members of nested groups
+// share all the groups of their tree.
+func (groupRepository *groupRepository) FindPeerIDs(userID uint) ([]uint, error) {
+	var joinedIDs []uint
+	groups := groupRepository.DB.Model(&Group{}).Select("id")
+	if err := groupRepository.DB.Model(&UserGroup{}).Where("user_id = ? AND role <> ? AND group_id IN (?)", userID, RoleGuest, groups).Pluck("group_id", &joinedIDs).Error; err != nil {
+		return nil, err
+	}
+	var treeIDs []uint
+	for _, groupID := range joinedIDs {
+		rootID := groupID
+		ancestorIDs, err := ancestorIDs(groupRepository.DB, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if len(ancestorIDs) > 0 {
+			rootID = ancestorIDs[len(ancestorIDs)-1]
+		}
+		subtreeIDs, err := descendantIDs(groupRepository.DB, rootID)
+		if err != nil {
+			return nil, err
+		}
+		treeIDs = append(append(treeIDs, rootID), subtreeIDs...)
+	}
+	var peerIDs []uint
+	if err := groupRepository.DB.Model(&UserGroup{}).Distinct("user_id").Where("group_id IN ? AND role <> ? AND user_id <> ?", treeIDs, RoleGuest, userID).Pluck("user_id", &peerIDs).Error; err != nil {
+		return nil, err
+	}
+	return peerIDs, nil
+}
+
+func (groupRepository *groupRepository) FindByIDWithDeleted(id uint) (*Group, error) {
+	var group Group
+	if err := groupRepository.DB.Unscoped().First(&group, id).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// FindDiscoverable returns the discoverable groups whose name contains
+// name, or all of them when it is empty.
+func (groupRepository *groupRepository) FindDiscoverable(name string) ([]Group, error) {
+	var groups []Group
+	query := groupRepository.DB.Where("discoverable = ?", true)
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	if err := query.Order("name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// FindAncestorIDs returns the parent of a group, then its grandparent, up
+// to the root of its tree.
+func (groupRepository *groupRepository) FindAncestorIDs(id uint) ([]uint, error) {
+	return ancestorIDs(groupRepository.DB, id)
+}
+
+// FindSubtree returns a group followed by all its descendants.
+func (groupRepository *groupRepository) FindSubtree(id uint) ([]Group, error) {
+	group, err := groupRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs, err := descendantIDs(groupRepository.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	var descendants []Group
+	if err := groupRepository.DB.Where("id IN ?", groupIDs).Order("name").Find(&descendants).Error; err != nil {
+		return nil, err
+	}
+	return append([]Group{*group}, descendants...), nil
+}
+
+// SetParentByID nests a group in parentID, or makes it a root when parentID
+// is nil, refusing to create a cycle.
+func (groupRepository *groupRepository) SetParentByID(id uint, parentID *uint) error {
+	return groupRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if parentID != nil {
+			if *parentID == id {
+				return ErrGroupCycle
+			}
+			groupIDs, err := descendantIDs(tx, id)
+			if err != nil {
+				return err
+			}
+			for _, groupID := range groupIDs {
+				if groupID == *parentID {
+					return ErrGroupCycle
+				}
+			}
+		}
+		return tx.Model(&Group{}).Where("id = ?", id).Update("parent_id", parentID).Error
+	})
+}
+
+func (groupRepository *groupRepository) UpdateSettingsByID(id uint, discoverable bool, autoApproveDomains string) error {
+	if err := groupRepository.DB.Model(&Group{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"discoverable":         discoverable,
+		"auto_approve_domains": autoApproveDomains,
+	}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func (groupRepository *groupRepository) UpdateByID(id uint, group *Group) (*Group, error) {
 	if err := groupRepository.DB.Model(&Group{}).Where("id = ?", id).Updates(group).Error; err != nil {
 		return nil, err
@@ -66,9 +282,59 @@ func (groupRepository *groupRepository) UpdateByID(id uint, group *Group) (*Grou
 	return group, nil
 }
 
+// DeleteByID deletes a group and moves its children up to its parent.
 func (groupRepository *groupRepository) DeleteByID(id uint) error {
-	if err := groupRepository.DB.Delete(&Group{}, id).Error; err != nil {
+	group, err := groupRepository.FindByID(id)
+	if err != nil {
 		return err
 	}
-	return nil
+	return groupRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Group{}).Where("parent_id = ?", id).Update("parent_id", group.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Group{}, id).Error
+	})
+}
+
+// ancestorIDs walks up the parents of a group, nearest first.
+func ancestorIDs(db *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	seen := map[uint]bool{id: true}
+	for {
+		var group Group
+		if err := db.Select("id", "parent_id").First(&group, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ids, nil
+			}
+			return nil, err
+		}
+		if group.ParentID == nil || seen[*group.ParentID] {
+			return ids, nil
+		}
+		id = *group.ParentID
+		seen[id] = true
+		ids = append(ids, id)
+	}
+}
+
+// descendantIDs returns the groups nested in a group, at any depth.
+func descendantIDs(db *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	seen := map[uint]bool{id: true}
+	frontier := []uint{id}
+	for len(frontier) > 0 {
+		var childIDs []uint
+		if err := db.Model(&Group{}).Where("parent_id IN ?", frontier).Pluck("id", &childIDs).Error; err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, childID := range childIDs {
+			if !seen[childID] {
+				seen[childID] = true
+				ids = append(ids, childID)
+				frontier = append(frontier, childID)
+			}
+		}
+	}
+	return ids, nil
 }
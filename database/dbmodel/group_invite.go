@@ -0,0 +1,99 @@
+package dbmodel
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInviteUsedUp is returned by Redeem once an invite reached its MaxUses.
+var ErrInviteUsedUp = errors.New("invite used up")
+
+// GroupInvite lets anyone holding its Code join a group with Role. MaxUses
+// 0 means unlimited; revoked invites are soft-deleted.
+type GroupInvite struct {
+	gorm.Model
+	GroupID   uint       `gorm:"index;not null" json:"group_id"`
+	CreatorID uint       `json:"creator_id"`
+	Code      string     `gorm:"uniqueIndex;not null" json:"code"`
+	Role      string     `gorm:"not null;default:member" json:"role"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type GroupInviteRepository interface {
+	Create(invite *GroupInvite) (*GroupInvite, error)
+	FindByID(id uint) (*GroupInvite, error)
+	FindByGroupID(groupID uint) ([]GroupInvite, error)
+	FindByCode(code string) (*GroupInvite, error)
+	Redeem(invite *GroupInvite, userID uint) (*UserGroup, error)
+	DeleteByID(id uint) error
+}
+
+type groupInviteRepository struct {
+	DB *gorm.DB
+}
+
+func NewGroupInviteRepository(db *gorm.DB) GroupInviteRepository {
+	return &groupInviteRepository{DB: db}
+}
+
+func (groupInviteRepository *groupInviteRepository) Create(invite *GroupInvite) (*GroupInvite, error) {
+	if err := groupInviteRepository.DB.Create(invite).Error; err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+func (groupInviteRepository *groupInviteRepository) FindByID(id uint) (*GroupInvite, error) {
+	var invite GroupInvite
+	if err := groupInviteRepository.DB.First(&invite, id).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (groupInviteRepository *groupInviteRepository) FindByGroupID(groupID uint) ([]GroupInvite, error) {
+	var invites []GroupInvite
+	if err := groupInviteRepository.DB.Where("group_id = ?", groupID).Order("id").Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (groupInviteRepository *groupInviteRepository) FindByCode(code string) (*GroupInvite, error) {
+	var invite GroupInvite
+	if err := groupInviteRepository.DB.Where("code = ?", code).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// Redeem counts one use of an invite and adds userID to its group, unless
+// the invite is used up in the meantime.
+func (groupInviteRepository *groupInviteRepository) Redeem(invite *GroupInvite, userID uint) (*UserGroup, error) {
+	userGroup := &UserGroup{UserID: userID, GroupID: invite.GroupID, Role: invite.Role, InviteID: invite.ID}
+	err := groupInviteRepository.DB.Transaction(func(tx *gorm.DB) error {
+		used := tx.Model(&GroupInvite{}).Where("id = ? AND (max_uses = 0 OR uses < max_uses)", invite.ID).Update("uses", gorm.Expr("uses + 1"))
+		if err := used.Error; err != nil {
+			return err
+		}
+		if used.RowsAffected == 0 {
+			return ErrInviteUsedUp
+		}
+		return tx.Create(userGroup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userGroup, nil
+}
+
+func (groupInviteRepository *groupInviteRepository) DeleteByID(id uint) error {
+	if err := groupInviteRepository.DB.Delete(&GroupInvite{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
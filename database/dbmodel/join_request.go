@@ -0,0 +1,119 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// JoinRequest is a user's request to join a discoverable group. DecidedBy
+// is 0 for requests approved automatically.
+type JoinRequest struct {
+	gorm.Model
+	GroupID   uint       `gorm:"index;not null" json:"group_id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	Message   string     `json:"message"`
+	Status    string     `gorm:"not null;default:pending" json:"status"`
+	DecidedBy uint       `json:"decided_by"`
+	DecidedAt *time.Time `json:"decided_at"`
+}
+
+type JoinRequestRepository interface {
+	Create(joinRequest *JoinRequest) (*JoinRequest, error)
+	FindByID(id uint) (*JoinRequest, error)
+	FindByUserID(userID uint) ([]JoinRequest, error)
+	FindPendingByGroupID(groupID uint) ([]JoinRequest, error)
+	FindPendingByUserIDAndGroupID(userID uint, groupID uint) (*JoinRequest, error)
+	Approve(joinRequest *JoinRequest, deciderID uint) (*UserGroup, error)
+	Reject(joinRequest *JoinRequest, deciderID uint) error
+}
+
+type joinRequestRepository struct {
+	DB *gorm.DB
+}
+
+func NewJoinRequestRepository(db *gorm.DB) JoinRequestRepository {
+	return &joinRequestRepository{DB: db}
+}
+
+func (joinRequestRepository *joinRequestRepository) Create(joinRequest *JoinRequest) (*JoinRequest, error) {
+	if err := joinRequestRepository.DB.Create(joinRequest).Error; err != nil {
+		return nil, err
+	}
+	return joinRequest, nil
+}
+
+func (joinRequestRepository *joinRequestRepository) FindByID(id uint) (*JoinRequest, error) {
+	var joinRequest JoinRequest
+	if err := joinRequestRepository.DB.First(&joinRequest, id).Error; err != nil {
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+func (joinRequestRepository *joinRequestRepository) FindByUserID(userID uint) ([]JoinRequest, error) {
+	var joinRequests []JoinRequest
+	if err := joinRequestRepository.DB.Where("user_id = ?", userID).Order("id").Find(&joinRequests).Error; err != nil {
+		return nil, err
+	}
+	return joinRequests, nil
+}
+
+func (joinRequestRepository *joinRequestRepository) FindPendingByGroupID(groupID uint) ([]JoinRequest, error) {
+	var joinRequests []JoinRequest
+	if err := joinRequestRepository.DB.Where("group_id = ? AND status = ?", groupID, JoinRequestPending).Order("id").Find(&joinRequests).Error; err != nil {
+		return nil, err
+	}
+	return joinRequests, nil
+}
+
+func (joinRequestRepository *joinRequestRepository) FindPendingByUserIDAndGroupID(userID uint, groupID uint) (*JoinRequest, error) {
+	var joinRequest JoinRequest
+	if err := joinRequestRepository.DB.Where("user_id = ? AND group_id = ? AND status = ?", userID, groupID, JoinRequestPending).First(&joinRequest).Error; err != nil {
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+// Approve marks a request approved and adds its user to the group as a
+// member, both or neither.
+func (joinRequestRepository *joinRequestRepository) Approve(joinRequest *JoinRequest, deciderID uint) (*UserGroup, error) {
+	userGroup := &UserGroup{UserID: joinRequest.UserID, GroupID: joinRequest.GroupID, Role: RoleMember}
+	err := joinRequestRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := joinRequestRepository.decide(tx, joinRequest, JoinRequestApproved, deciderID); err != nil {
+			return err
+		}
+		return tx.Create(userGroup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userGroup, nil
+}
+
+func (joinRequestRepository *joinRequestRepository) Reject(joinRequest *JoinRequest, deciderID uint) error {
+	return joinRequestRepository.decide(joinRequestRepository.DB, joinRequest, JoinRequestRejected, deciderID)
+}
+
+func (joinRequestRepository *joinRequestRepository) decide(tx *gorm.DB, joinRequest *JoinRequest, status string, deciderID uint) error {
+	now := time.Now()
+	decided := tx.Model(&JoinRequest{}).Where("id = ? AND status = ?", joinRequest.ID, JoinRequestPending).Updates(map[string]interface{}{
+		"status":     status,
+		"decided_by": deciderID,
+		"decided_at": now,
+	})
+	if err := decided.Error; err != nil {
+		return err
+	}
+	if decided.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	joinRequest.Status, joinRequest.DecidedBy, joinRequest.DecidedAt = status, deciderID, &now
+	return nil
+}
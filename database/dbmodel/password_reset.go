@@ -0,0 +1,82 @@
+package dbmodel
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrPasswordResetUsed is returned by Use when the token was already used.
+var ErrPasswordResetUsed = errors.New("password reset token already used")
+
+// PasswordReset is a single-use token sent by email to let a user choose a
+// new password. Only its hash is stored.
+type PasswordReset struct {
+	gorm.Model
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	User      *User      `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+type PasswordResetRepository interface {
+	Create(passwordReset *PasswordReset) (*PasswordReset, error)
+	FindByTokenHash(tokenHash string) (*PasswordReset, error)
+	Use(passwordReset *PasswordReset, passwordHash string) error
+}
+
+type passwordResetRepository struct {
+	DB *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{DB: db}
+}
+
+// Create stores a reset token and discards the unused ones sent earlier to
+// the same user, so that only the latest email works.
+func (passwordResetRepository *passwordResetRepository) Create(passwordReset *PasswordReset) (*PasswordReset, error) {
+	err := passwordResetRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", passwordReset.UserID).Delete(&PasswordReset{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(passwordReset).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return passwordReset, nil
+}
+
+func (passwordResetRepository *passwordResetRepository) FindByTokenHash(tokenHash string) (*PasswordReset, error) {
+	var passwordReset PasswordReset
+	if err := passwordResetRepository.DB.Where("token_hash = ?", tokenHash).First(&passwordReset).Error; err != nil {
+		return nil, err
+	}
+	return &passwordReset, nil
+}
+
+// Use marks a reset token as used, unless it was used in the meantime, and
+// at once sets the user's new password, discards their other reset tokens
+// and revokes their sessions.
+func (passwordResetRepository *passwordResetRepository) Use(passwordReset *PasswordReset, passwordHash string) error {
+	return passwordResetRepository.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		used := tx.Model(&PasswordReset{}).Where("id = ? AND used_at IS NULL", passwordReset.ID).Update("used_at", now)
+		if err := used.Error; err != nil {
+			return err
+		}
+		if used.RowsAffected == 0 {
+			return ErrPasswordResetUsed
+		}
+		if err := tx.Model(&User{}).Where("id = ?", passwordReset.UserID).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND id <> ?", passwordReset.UserID, passwordReset.ID).Delete(&PasswordReset{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Session{}).Where("user_id = ? AND revoked_at IS NULL", passwordReset.UserID).Update("revoked_at", now).Error
+	})
+}
@@ -0,0 +1,69 @@
+package dbmodel
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrRefreshTokenReused is returned by Rotate when the token was already
+// exchanged, which means someone else holds a copy of it.
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+// RefreshToken is one link of the chain of refresh tokens of a session.
+// Each use replaces the token with a new one; revoking the session stops
+// them all.
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	User      *User      `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	SessionID uint       `gorm:"index;not null" json:"session_id"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+type RefreshTokenRepository interface {
+	Create(refreshToken *RefreshToken) (*RefreshToken, error)
+	FindByTokenHash(tokenHash string) (*RefreshToken, error)
+	Rotate(used *RefreshToken, next *RefreshToken) error
+}
+
+type refreshTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{DB: db}
+}
+
+func (refreshTokenRepository *refreshTokenRepository) Create(refreshToken *RefreshToken) (*RefreshToken, error) {
+	if err := refreshTokenRepository.DB.Create(refreshToken).Error; err != nil {
+		return nil, err
+	}
+	return refreshToken, nil
+}
+
+func (refreshTokenRepository *refreshTokenRepository) FindByTokenHash(tokenHash string) (*RefreshToken, error) {
+	var refreshToken RefreshToken
+	if err := refreshTokenRepository.DB.Where("token_hash = ?", tokenHash).First(&refreshToken).Error; err != nil {
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+// Rotate marks used as exchanged and stores next in its place, unless used
+// was exchanged in the meantime.
+func (refreshTokenRepository *refreshTokenRepository) Rotate(used *RefreshToken, next *RefreshToken) error {
+	return refreshTokenRepository.DB.Transaction(func(tx *gorm.DB) error {
+		exchanged := tx.Model(&RefreshToken{}).Where("id = ? AND used_at IS NULL", used.ID).Update("used_at", time.Now())
+		if err := exchanged.Error; err != nil {
+			return err
+		}
+		if exchanged.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+		return tx.Create(next).Error
+	})
+}
@@ -0,0 +1,80 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is one sign-in of a user on a device. Its refresh tokens and
+// access tokens stop working once it is revoked.
+type Session struct {
+	gorm.Model
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	User       *User      `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type SessionRepository interface {
+	Create(session *Session) (*Session, error)
+	FindByID(id uint) (*Session, error)
+	FindActiveByUserID(userID uint) ([]Session, error)
+	UpdateLastUsedAt(id uint, lastUsedAt time.Time) error
+	RevokeByID(id uint) error
+	RevokeByUserID(userID uint) error
+}
+
+type sessionRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{DB: db}
+}
+
+func (sessionRepository *sessionRepository) Create(session *Session) (*Session, error) {
+	if err := sessionRepository.DB.Create(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (sessionRepository *sessionRepository) FindByID(id uint) (*Session, error) {
+	var session Session
+	if err := sessionRepository.DB.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (sessionRepository *sessionRepository) FindActiveByUserID(userID uint) ([]Session, error) {
+	var sessions []Session
+	if err := sessionRepository.DB.Where("user_id = ? AND revoked_at IS NULL", userID).Order("last_used_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (sessionRepository *sessionRepository) UpdateLastUsedAt(id uint, lastUsedAt time.Time) error {
+	if err := sessionRepository.DB.Model(&Session{}).Where("id = ?", id).Update("last_used_at", lastUsedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sessionRepository *sessionRepository) RevokeByID(id uint) error {
+	if err := sessionRepository.DB.Model(&Session{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sessionRepository *sessionRepository) RevokeByUserID(userID uint) error {
+	if err := sessionRepository.DB.Model(&Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package dbmodel
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
@@ -10,18 +14,28 @@ type User struct {
 	Name     string  `json:"name"`
 	Surname  string  `json:"surname"`
 	ColorID  uint    `json:"color_id"`
+	IsAdmin  bool    `gorm:"not null;default:false" json:"is_admin"`
 	Color    *Color  `gorm:"null;constraint:OnDelete:SET NULL;"`
 	Groups   []Group `gorm:"many2many:user_group;" json:"groups"`
 	Colors   []Color `gorm:"many2many:user_group;" json:"colors"`
+	// Verified is set once the user followed a link sent to Email.
+	Verified   bool       `gorm:"not null;default:false" json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	// PendingEmail is the address the user asked to change to. It replaces
+	// Email once verified.
+	PendingEmail string `json:"pending_email"`
 }
 
 type UserRepository interface {
 	Create(user *User) (*User, error)
 	FindAll() ([]User, error)
 	FindByID(id uint) (*User, error)
+	FindByIDs(ids []uint) ([]User, error)
 	FindByEmail(email string) (*User, error)
 	FindByUsername(username string) (*User, error)
 	UpdateByID(id uint, user *User) (*User, error)
+	SetPendingEmailByID(id uint, email string) error
+	VerifyEmailByID(id uint, email string) error
 	DeleteByID(id uint) error
 }
 
@@ -56,6 +70,14 @@ func (userRepository *userRepository) FindByID(id uint) (*User, error) {
 	return &user, nil
 }
 
+func (userRepository *userRepository) FindByIDs(ids []uint) ([]User, error) {
+	var users []User
+	if err := userRepository.DB.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (userRepository *userRepository) FindByEmail(email string) (*User, error) {
 	var user User
 	if err := userRepository.DB.Where("email = ?", email).First(&user).Error; err != nil {
@@ -79,6 +101,28 @@ func (userRepository *userRepository) UpdateByID(id uint, user *User) (*User, er
 	return user, nil
 }
 
+func (userRepository *userRepository) SetPendingEmailByID(id uint, email string) error {
+	if err := userRepository.DB.Model(&User{}).Where("id = ?", id).Update("pending_email", email).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifyEmailByID marks email as the verified address of the user, in place
+// of the previous one if it was pending.
+func (userRepository *userRepository) VerifyEmailByID(id uint, email string) error {
+	err := userRepository.DB.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"pending_email": gorm.Expr("CASE WHEN pending_email = ? THEN '' ELSE pending_email END", email),
+		"verified":      true,
+		"verified_at":   time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (userRepository *userRepository) DeleteByID(id uint) error {
 	if err := userRepository.DB.Delete(&User{}, id).Error; err != nil {
 		return err
@@ -2,10 +2,22 @@ package dbmodel
 
 import "gorm.io/gorm"
 
+// Roles of a group member, from the most to the least privileged. The
+// owner is also the group's CreatorID; there is exactly one per group.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleGuest  = "guest"
+)
+
 type UserGroup struct {
-	UserID  uint `json:"user_id"`
-	GroupID uint `json:"group_id"`
-	ColorID uint `gorm:"null" json:"color_id"`
+	UserID  uint   `json:"user_id"`
+	GroupID uint   `json:"group_id"`
+	ColorID uint   `gorm:"null" json:"color_id"`
+	Role    string `gorm:"not null;default:member" json:"role"`
+	// InviteID is the invite the member joined through, if any.
+	InviteID uint `gorm:"null" json:"invite_id"`
 }
 
 type UserGroupRepository interface {
@@ -13,8 +25,13 @@ type UserGroupRepository interface {
 	FindAll() ([]UserGroup, error)
 	FindByUserID(userID uint) ([]UserGroup, error)
 	FindByGroupID(groupID uint) ([]UserGroup, error)
+	FindByUserIDs(userIDs []uint) ([]UserGroup, error)
+	FindByGroupIDs(groupIDs []uint) ([]UserGroup, error)
 	FindByUserIDAndGroupID(userID uint, groupID uint) (*UserGroup, error)
+	FindInheritedByUserIDAndGroupID(userID uint, groupID uint) ([]UserGroup, error)
 	UpdateColorByUserIDAndGroupID(userID uint, groupID uint, colorID uint) error
+	UpdateRoleByUserIDAndGroupID(userID uint, groupID uint, role string) error
+	TransferOwnership(groupID uint, fromID uint, toID uint) error
 	DeleteByUserIDAndGroupID(userID uint, groupID uint) error
 	DeleteByGroupID(groupID uint) error
 }
@@ -44,7 +61,7 @@ func (userGroupRepository *userGroupRepository) FindAll() ([]UserGroup, error) {
 
 func (userGroupRepository *userGroupRepository) FindByUserID(userID uint) ([]UserGroup, error) {
 	var userGroups []UserGroup
-	if err := userGroupRepository.DB.Preload("User").Where("user_id = ?", userID).Find(&userGroups).Error; err != nil {
+	if err := userGroupRepository.DB.Where("user_id = ?", userID).Find(&userGroups).Error; err != nil {
 		return nil, err
 	}
 	return userGroups, nil
@@ -52,7 +69,23 @@ func (userGroupRepository *userGroupRepository) FindByUserID(userID uint) ([]Use
 
 func (userGroupRepository *userGroupRepository) FindByGroupID(groupID uint) ([]UserGroup, error) {
 	var userGroups []UserGroup
-	if err := userGroupRepository.DB.Preload("Group").Where("group_id = ?", groupID).Find(&userGroups).Error; err != nil {
+	if err := userGroupRepository.DB.Where("group_id = ?", groupID).Find(&userGroups).Error; err != nil {
+		return nil, err
+	}
+	return userGroups, nil
+}
+
+func (userGroupRepository *userGroupRepository) FindByUserIDs(userIDs []uint) ([]UserGroup, error) {
+	var userGroups []UserGroup
+	if err := userGroupRepository.DB.Where("user_id IN ?", userIDs).Find(&userGroups).Error; err != nil {
+		return nil, err
+	}
+	return userGroups, nil
+}
+
+func (userGroupRepository *userGroupRepository) FindByGroupIDs(groupIDs []uint) ([]UserGroup, error) {
+	var userGroups []UserGroup
+	if err := userGroupRepository.DB.Where("group_id IN ?", groupIDs).Find(&userGroups).Error; err != nil {
 		return nil, err
 	}
 	return userGroups, nil
@@ -60,12 +93,26 @@ func (userGroupRepository *userGroupRepository) FindByGroupID(groupID uint) ([]U
 
 func (userGroupRepository *userGroupRepository) FindByUserIDAndGroupID(userID uint, groupID uint) (*UserGroup, error) {
 	var userGroup UserGroup
-	if err := userGroupRepository.DB.Preload("User").Preload("Group").Where("user_id = ? AND group_id = ?", userID, groupID).First(&userGroup).Error; err != nil {
+	if err := userGroupRepository.DB.Where("user_id = ? AND group_id = ?", userID, groupID).First(&userGroup).Error; err != nil {
 		return nil, err
 	}
 	return &userGroup, nil
 }
 
+// FindInheritedByUserIDAndGroupID returns the memberships of a user in the
+// descendants of a group, which make them an implicit member of it.
+func (userGroupRepository *userGroupRepository) FindInheritedByUserIDAndGroupID(userID uint, groupID uint) ([]UserGroup, error) {
+	groupIDs, err := descendantIDs(userGroupRepository.DB, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var userGroups []UserGroup
+	if err := userGroupRepository.DB.Where("user_id = ? AND group_id IN ?", userID, groupIDs).Find(&userGroups).Error; err != nil {
+		return nil, err
+	}
+	return userGroups, nil
+}
+
 func (userGroupRepository *userGroupRepository) UpdateColorByUserIDAndGroupID(userID uint, groupID uint, colorID uint) error {
 	if err := userGroupRepository.DB.Model(&UserGroup{}).Where("user_id = ? AND group_id = ?", userID, groupID).Update("color_id", colorID).Error; err != nil {
 		return err
@@ -73,6 +120,34 @@ func (userGroupRepository *userGroupRepository) UpdateColorByUserIDAndGroupID(us
 	return nil
 }
 
+func (userGroupRepository *userGroupRepository) UpdateRoleByUserIDAndGroupID(userID uint, groupID uint, role string) error {
+	if err := userGroupRepository.DB.Model(&UserGroup{}).Where("user_id = ? AND group_id = ?", userID, groupID).Update("role", role).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// TransferOwnership makes toID the owner and creator of a group and demotes
+// fromID to admin, all at once so that the group always has an owner.
+func (userGroupRepository *userGroupRepository) TransferOwnership(groupID uint, fromID uint, toID uint) error {
+	return userGroupRepository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Group{}).Where("id = ?", groupID).Update("creator_id", toID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&UserGroup{}).Where("user_id = ? AND group_id = ?", toID, groupID).Update("role", RoleOwner).Error; err != nil {
+			return err
+		}
+		demoted := tx.Model(&UserGroup{}).Where("user_id = ? AND group_id = ?", fromID, groupID).Update("role", RoleAdmin)
+		if err := demoted.Error; err != nil {
+			return err
+		}
+		if demoted.RowsAffected == 0 {
+			return tx.Create(&UserGroup{UserID: fromID, GroupID: groupID, Role: RoleAdmin}).Error
+		}
+		return nil
+	})
+}
+
 func (userGroupRepository *userGroupRepository) DeleteByUserIDAndGroupID(userID uint, groupID uint) error {
 	if err := userGroupRepository.DB.Where("user_id = ? AND group_id = ?", userID, groupID).Delete(&UserGroup{}).Error; err != nil {
 		return err
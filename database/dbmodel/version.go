@@ -0,0 +1,37 @@
+package dbmodel
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrModified is returned by the writes that expect a record at the
+// version (UpdatedAt) their caller read it at, when it was modified or
+// deleted since.
+var ErrModified = errors.New("record modified since it was read")
+
+// updateVersion saves record if it is still at version.
+func updateVersion(tx *gorm.DB, record interface{}, version time.Time) error {
+	updated := tx.Model(record).Where("updated_at = ?", version).Select("*").Updates(record)
+	if err := updated.Error; err != nil {
+		return err
+	}
+	if updated.RowsAffected == 0 {
+		return ErrModified
+	}
+	return nil
+}
+
+// deleteVersion deletes record if it is still at version.
+func deleteVersion(tx *gorm.DB, record interface{}, version time.Time) error {
+	deleted := tx.Where("updated_at = ?", version).Delete(record)
+	if err := deleted.Error; err != nil {
+		return err
+	}
+	if deleted.RowsAffected == 0 {
+		return ErrModified
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package dbmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a URL that receives signed event payloads. A webhook with a
+// GroupID belongs to that group and is managed by its admins; otherwise it
+// belongs to the user who registered it.
+type Webhook struct {
+	gorm.Model
+	UserID  uint   `json:"user_id"`
+	User    *User  `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	GroupID uint   `gorm:"index" json:"group_id"`
+	URL     string `gorm:"not null" json:"url"`
+	Secret  string `gorm:"not null" json:"-"`
+	Events  string `json:"events"`
+	Active  bool   `json:"active"`
+}
+
+type WebhookDelivery struct {
+	gorm.Model
+	WebhookID        uint       `gorm:"index" json:"webhook_id"`
+	Webhook          *Webhook   `gorm:"not null;constraint:OnDelete:CASCADE;"`
+	EventID          string     `gorm:"index" json:"event_id"`
+	EventType        string     `json:"event_type"`
+	Payload          string     `gorm:"not null" json:"payload"`
+	Status           string     `gorm:"index;not null" json:"status"`
+	Attempts         int        `json:"attempts"`
+	NextAttemptAt    time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastResponseCode int        `json:"last_response_code"`
+	LastError        string     `json:"last_error"`
+	DeliveredAt      *time.Time `json:"delivered_at"`
+}
+
+type WebhookRepository interface {
+	Create(webhook *Webhook) (*Webhook, error)
+	FindActive() ([]Webhook, error)
+	FindByID(id uint) (*Webhook, error)
+	FindByUserID(userID uint) ([]Webhook, error)
+	FindByGroupID(groupID uint) ([]Webhook, error)
+	UpdateByID(id uint, webhook *Webhook) error
+	DeleteByID(id uint) error
+}
+
+type webhookRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{DB: db}
+}
+
+func (webhookRepository *webhookRepository) Create(webhook *Webhook) (*Webhook, error) {
+	if err := webhookRepository.DB.Create(webhook).Error; err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (webhookRepository *webhookRepository) FindActive() ([]Webhook, error) {
+	var webhooks []Webhook
+	if err := webhookRepository.DB.Where("active = ?", true).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (webhookRepository *webhookRepository) FindByID(id uint) (*Webhook, error) {
+	var webhook Webhook
+	if err := webhookRepository.DB.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (webhookRepository *webhookRepository) FindByUserID(userID uint) ([]Webhook, error) {
+	var webhooks []Webhook
+	if err := webhookRepository.DB.Where("user_id = ? AND group_id = 0", userID).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (webhookRepository *webhookRepository) FindByGroupID(groupID uint) ([]Webhook, error) {
+	var webhooks []Webhook
+	if err := webhookRepository.DB.Where("group_id = ?", groupID).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (webhookRepository *webhookRepository) UpdateByID(id uint, webhook *Webhook) error {
+	updates := map[string]interface{}{
+		"url":    webhook.URL,
+		"events": webhook.Events,
+		"active": webhook.Active,
+	}
+	if err := webhookRepository.DB.Model(&Webhook{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (webhookRepository *webhookRepository) DeleteByID(id uint) error {
+	if err := webhookRepository.DB.Where("webhook_id = ?", id).Delete(&WebhookDelivery{}).Error; err != nil {
+		return err
+	}
+	if err := webhookRepository.DB.Delete(&Webhook{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+type WebhookDeliveryRepository interface {
+	Create(delivery *WebhookDelivery) (*WebhookDelivery, error)
+	FindByID(id uint) (*WebhookDelivery, error)
+	FindByWebhookID(webhookID uint, limit int) ([]WebhookDelivery, error)
+	FindLatestByWebhookID(webhookID uint) (*WebhookDelivery, error)
+	FindDue(now time.Time, limit int) ([]WebhookDelivery, error)
+	Update(delivery *WebhookDelivery) error
+}
+
+type webhookDeliveryRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{DB: db}
+}
+
+func (webhookDeliveryRepository *webhookDeliveryRepository) Create(delivery *WebhookDelivery) (*WebhookDelivery, error) {
+	if err := webhookDeliveryRepository.DB.Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (webhookDeliveryRepository *webhookDeliveryRepository) FindByID(id uint) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := webhookDeliveryRepository.DB.First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (webhookDeliveryRepository *webhookDeliveryRepository) FindByWebhookID(webhookID uint, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	if err := webhookDeliveryRepository.DB.Where("webhook_id = ?", webhookID).Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (webhookDeliveryRepository *webhookDeliveryRepository) FindLatestByWebhookID(webhookID uint) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := webhookDeliveryRepository.DB.Where("webhook_id = ? AND attempts > 0", webhookID).Order("updated_at DESC").First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDue returns pending deliveries whose next attempt is scheduled at or
+// before now, oldest first.
+func (webhookDeliveryRepository *webhookDeliveryRepository) FindDue(now time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	if err := webhookDeliveryRepository.DB.Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).Order("next_attempt_at").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (webhookDeliveryRepository *webhookDeliveryRepository) Update(delivery *WebhookDelivery) error {
+	if err := webhookDeliveryRepository.DB.Save(delivery).Error; err != nil {
+		return err
+	}
+	return nil
+}
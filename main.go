@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"yplanning/config"
 	"yplanning/pkg/authentication"
 	"yplanning/pkg/availability"
+	"yplanning/pkg/caldav"
+	"yplanning/pkg/changes"
 	"yplanning/pkg/color"
 	"yplanning/pkg/date"
+	"yplanning/pkg/feed"
+	"yplanning/pkg/graph"
 	"yplanning/pkg/group"
+	"yplanning/pkg/live"
+	"yplanning/pkg/mail"
+	"yplanning/pkg/openapi"
+	"yplanning/pkg/rpc"
+	"yplanning/pkg/subscription"
 	"yplanning/pkg/user"
+	"yplanning/pkg/webhook"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/joho/godotenv"
@@ -24,19 +36,37 @@ import (
 // @securityDefinitions.apikey	BearerAuth
 // @in				header
 // @name			Authorization
-func Routes(configuration *config.Config) *chi.Mux {
+func Routes(configuration *config.Config, validation openapi.Mode, keys *authentication.Keys) *chi.Mux {
 	router := chi.NewRouter()
-	router.Get("/swagger/*", httpSwagger.WrapHandler)
+	router.Use(openapi.Validator(validation))
+	router.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("/api/openapi.json")))
+	router.Get("/api/openapi.json", openapi.Document)
 
-	router.Mount("/api/auth", authentication.Routes(configuration))
+	router.Mount("/api/auth", authentication.Routes(configuration, keys))
+	router.Mount("/feeds", feed.PublicRoutes(configuration))
+	router.HandleFunc("/.well-known/caldav", caldav.WellKnown)
+	router.Get("/.well-known/jwks.json", keys.JWKS)
+	router.Mount("/dav", caldav.DAVRoutes(configuration))
 
 	router.Group(func(r chi.Router) {
-		r.Use(authentication.AuthMiddleware(os.Getenv("JWT_SECRET")))
+		r.Use(authentication.AuthMiddleware(keys, configuration.UserRepository, configuration.SessionRepository))
 		r.Mount("/api/group", group.Routes(configuration))
 		r.Mount("/api/date", date.Routes(configuration))
 		r.Mount("/api/availability", availability.Routes(configuration))
 		r.Mount("/api/color", color.Routes(configuration))
 		r.Mount("/api/user", user.Routes(configuration))
+		r.Mount("/api/feed", feed.Routes(configuration))
+		r.Mount("/api/subscription", subscription.Routes(configuration))
+		r.Mount("/api/caldav", caldav.Routes(configuration))
+		r.Mount("/api/webhook", webhook.Routes(configuration))
+		r.Mount("/api/sync", changes.Routes(configuration))
+		r.Mount("/api/graphql", graph.Routes(configuration))
+	})
+
+	router.Group(func(r chi.Router) {
+		r.Use(authentication.QueryTokenMiddleware)
+		r.Use(authentication.AuthMiddleware(keys, configuration.UserRepository, configuration.SessionRepository))
+		r.Mount("/api/live", live.Routes(configuration))
 	})
 
 	return router
@@ -49,10 +79,54 @@ func main() {
 		log.Panicln("Configuration error:", err)
 	}
 	godotenv.Load()
+	// Clés de signature des jetons d'accès
+	keys, err := authentication.LoadKeys()
+	if err != nil {
+		log.Panicln("Configuration error:", err)
+	}
+	// Envoi des emails
+	configuration.Mailer, err = mail.FromEnv()
+	if err != nil {
+		log.Panicln("Configuration error:", err)
+	}
+	// Adresse publique utilisée dans les liens envoyés aux utilisateurs
+	if publicURL := os.Getenv("PUBLIC_URL"); publicURL != "" {
+		configuration.PublicURL = strings.TrimSuffix(publicURL, "/")
+	} else if port := os.Getenv("PORT"); port != "" {
+		configuration.PublicURL = "http://localhost:" + port
+	}
+	// Journal des modifications pour la synchronisation incrémentale
+	changes.Record(configuration)
+	// Validation des requêtes selon la spécification OpenAPI
+	validation, err := openapi.ParseMode(os.Getenv("OPENAPI_VALIDATION"))
+	if err != nil {
+		log.Panicln("Configuration error:", err)
+	}
 	// Initialisation des routes
-	router := Routes(configuration)
+	router := Routes(configuration, validation, keys)
+	// Vérification que la spécification OpenAPI suit les routes
+	problems, err := openapi.CheckRoutes(router)
+	if err != nil {
+		log.Panicln("OpenAPI error:", err)
+	}
+	for _, problem := range problems {
+		log.Println("OpenAPI:", problem)
+	}
+	// Synchronisation des calendriers externes
+	go subscription.NewWorker(configuration).Run(context.Background())
+	// Envoi des webhooks
+	go webhook.NewDispatcher(configuration).Run(context.Background())
+	// Serveur gRPC pour les services internes
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = rpc.DefaultPort
+	}
+	go func() {
+		log.Fatal(rpc.ListenAndServe(configuration, keys, ":"+grpcPort))
+	}()
 
 	log.Println("Server running on http://localhost:" + os.Getenv("PORT"))
+	log.Println("gRPC server running on localhost:" + grpcPort)
 	log.Println("Swagger UI available at http://localhost:" + os.Getenv("PORT") + "/swagger/index.html")
 	log.Fatal(http.ListenAndServe(":"+os.Getenv("PORT"), router))
 }
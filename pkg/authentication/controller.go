@@ -1,34 +1,40 @@
 package authentication
 
 import (
+	"errors"
+	"log"
+	"net"
 	"net/http"
-	"os"
+	"strconv"
+	"time"
 	"yplanning/config"
 	"yplanning/database/dbmodel"
 	"yplanning/pkg/models"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthConfig struct {
 	*config.Config
+	Keys *Keys
 }
 
-func New(configuration *config.Config) *AuthConfig {
-	return &AuthConfig{configuration}
+func New(configuration *config.Config, keys *Keys) *AuthConfig {
+	return &AuthConfig{configuration, keys}
 }
 
 // @Summary Register a new user
-// @Description Register a new user with email, username, and password
+// @Description Register a new user with email, username, and password. The username cannot contain "@". A verification link is emailed to the address; until it is followed, UNVERIFIED_RESTRICTIONS may keep the user from joining groups or being added to them.
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Param user body models.UserRequest true "User registration information"
 // @Success 200 {object} models.TokenResponse
-// @Failure 400 {object} http.Error
-// @Failure 409 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
 // @Router /auth/register [post]
 func (config *AuthConfig) Register(w http.ResponseWriter, r *http.Request) {
 	var req models.UserRequest
@@ -36,6 +42,10 @@ func (config *AuthConfig) Register(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := models.ValidateUsername(req.Username); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	_, err := config.UserRepository.FindByEmail(req.Email)
 	if err == nil {
@@ -57,24 +67,18 @@ func (config *AuthConfig) Register(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to create user: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	user := &models.UserResponse{ID: res.ID, Email: res.Email, Username: res.Username}
 
-	accessToken, err := GenerateToken(os.Getenv("JWT_SECRET"), user.Email)
-	if err != nil {
-		http.Error(w, "Failed to generate token: "+err.Error(), http.StatusInternalServerError)
-		return
+	// The account exists by now: a failure is logged and the user can ask
+	// for another email.
+	if err := config.sendVerification(res, res.Email); err != nil {
+		log.Printf("Verification email to user %d: %v", res.ID, err)
 	}
-	refreshToken, err := GenerateRefreshToken(os.Getenv("REFRESH_SECRET"), user.Email)
+
+	tokens, err := config.signIn(r, res)
 	if err != nil {
-		http.Error(w, "Failed to generate refresh token: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to generate token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	tokens := &models.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    "bearer",
-	}
-
 	render.JSON(w, r, tokens)
 }
 
@@ -85,9 +89,9 @@ func (config *AuthConfig) Register(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param user body models.UserRequest true "User login information"
 // @Success 200 {object} models.TokenResponse
-// @Failure 400 {object} http.Error
-// @Failure 401 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
 // @Router /auth/login [post]
 func (config *AuthConfig) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.UserRequest
@@ -108,36 +112,24 @@ func (config *AuthConfig) Login(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
 		return
 	}
-	accessToken, err := GenerateToken(os.Getenv("JWT_SECRET"), user.Email)
+	tokens, err := config.signIn(r, user)
 	if err != nil {
 		http.Error(w, "Failed to generate token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	refreshToken, err := GenerateRefreshToken(os.Getenv("REFRESH_SECRET"), user.Email)
-	if err != nil {
-		http.Error(w, "Failed to generate refresh token: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	tokens := &models.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    "bearer",
-	}
-
 	render.JSON(w, r, tokens)
 }
 
 // @Summary Refresh access token
-// @Description Refresh the access token using a valid refresh token
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once: presenting one again signs its session out.
 // @Tags authentication
 // @Accept json
 // @Produce json
-// @Param token body models.Token
+// @Param token body models.TokenRequest true "Refresh token"
 // @Success 200 {object} models.TokenResponse
-// @Failure 400 {object} http.Error
-// @Failure 401 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
 // @Router /auth/refresh [post]
 func (config *AuthConfig) Refresh(w http.ResponseWriter, r *http.Request) {
 	req := &models.TokenRequest{}
@@ -146,33 +138,197 @@ func (config *AuthConfig) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	email, err := ParseToken(os.Getenv("REFRESH_SECRET"), req.RefreshToken)
+	used, err := config.RefreshTokenRepository.FindByTokenHash(hashToken(req.RefreshToken))
+	if err != nil || !used.ExpiresAt.After(time.Now()) {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	session, err := config.SessionRepository.FindByID(used.SessionID)
+	if err != nil || session.RevokedAt != nil {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	if used.UsedAt != nil {
+		config.revokeReusedSession(w, session)
+		return
+	}
+	user, err := config.UserRepository.FindByID(used.UserID)
 	if err != nil {
 		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
 		return
 	}
 
-	user, err := config.UserRepository.FindByEmail(email)
+	refreshToken, next, err := newRefreshToken(user.ID, session.ID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		http.Error(w, "Failed to generate refresh token: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = config.RefreshTokenRepository.Rotate(used, next)
+	if errors.Is(err, dbmodel.ErrRefreshTokenReused) {
+		config.revokeReusedSession(w, session)
 		return
 	}
-	accessToken, err := GenerateToken(os.Getenv("JWT_SECRET"), user.Email)
+	if err != nil {
+		http.Error(w, "Failed to store refresh token", http.StatusInternalServerError)
+		return
+	}
+	if err := config.SessionRepository.UpdateLastUsedAt(session.ID, time.Now()); err != nil {
+		http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		return
+	}
+	tokens, err := tokenResponse(config.Keys, user, session.ID, refreshToken)
 	if err != nil {
 		http.Error(w, "Failed to generate token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	refreshToken, err := GenerateRefreshToken(os.Getenv("REFRESH_SECRET"), user.Email)
+	render.JSON(w, r, tokens)
+}
+
+// @Summary Log out
+// @Description Sign out the session of a refresh token: its refresh and access tokens stop working.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param token body models.TokenRequest true "Refresh token"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Router /auth/logout [post]
+func (config *AuthConfig) Logout(w http.ResponseWriter, r *http.Request) {
+	req := &models.TokenRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	refreshToken, err := config.RefreshTokenRepository.FindByTokenHash(hashToken(req.RefreshToken))
 	if err != nil {
-		http.Error(w, "Failed to generate refresh token: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	if err := config.SessionRepository.RevokeByID(refreshToken.SessionID); err != nil {
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, "Successfully logged out")
+}
+
+// @Summary Log out everywhere
+// @Description Sign out every session of the caller, this one included.
+// @Tags authentication
+// @Produce json
+// @Success 200 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /auth/logout-all [post]
+func (config *AuthConfig) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	if err := config.SessionRepository.RevokeByUserID(user.ID); err != nil {
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
 		return
 	}
+	render.JSON(w, r, "Successfully logged out")
+}
 
-	tokens := &models.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    "bearer",
+// @Summary List my sessions
+// @Description List the caller's active sessions, most recently used first. current marks the one the request was made with.
+// @Tags authentication
+// @Produce json
+// @Success 200 {array} models.SessionResponse
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /auth/sessions [get]
+func (config *AuthConfig) GetSessions(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	sessions, err := config.SessionRepository.FindActiveByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve sessions", http.StatusInternalServerError)
+		return
 	}
+	currentID := GetSessionFromContext(r.Context())
+	sessionResponse := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		sessionResponse = append(sessionResponse, models.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    session.ID == currentID,
+		})
+	}
+	render.JSON(w, r, sessionResponse)
+}
 
-	render.JSON(w, r, tokens)
+// @Summary Revoke a session
+// @Description Sign out one of the caller's sessions: its refresh and access tokens stop working.
+// @Tags authentication
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /auth/sessions/{id} [delete]
+func (config *AuthConfig) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+	user := UserFromContext(r.Context())
+	session, err := config.SessionRepository.FindByID(uint(id))
+	if err != nil || session.UserID != user.ID || session.RevokedAt != nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if err := config.SessionRepository.RevokeByID(session.ID); err != nil {
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, "Succefully deleted entry")
+}
+
+// signIn opens a new session for user on the device r comes from.
+func (config *AuthConfig) signIn(r *http.Request, user *dbmodel.User) (*models.TokenResponse, error) {
+	session, err := config.SessionRepository.Create(&dbmodel.Session{
+		UserID:     user.ID,
+		UserAgent:  r.UserAgent(),
+		IP:         clientIP(r),
+		LastUsedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, record, err := newRefreshToken(user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.RefreshTokenRepository.Create(record); err != nil {
+		return nil, err
+	}
+	return tokenResponse(config.Keys, user, session.ID, refreshToken)
+}
+
+// revokeReusedSession answers the second use of a refresh token: either
+// the legitimate client or a thief holds a copy, so the session goes.
+func (config *AuthConfig) revokeReusedSession(w http.ResponseWriter, session *dbmodel.Session) {
+	if err := config.SessionRepository.RevokeByID(session.ID); err != nil {
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+	http.Error(w, "Refresh token already used, sign in again", http.StatusUnauthorized)
+}
+
+// clientIP is the address the request came from, without its port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
@@ -1,42 +1,71 @@
 package authentication
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 	"time"
 
+	"yplanning/database/dbmodel"
+
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateToken(secret, id string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":  id,
-		"exp": time.Now().Add(time.Hour * 3).Unix(),
-	})
-
-	return token.SignedString([]byte(secret))
+// AccessToken is what an access token says about its bearer.
+type AccessToken struct {
+	// UserID is the "sub" claim, the ID of the user.
+	UserID uint
+	// Email is set instead of UserID by tokens issued before they named
+	// users by ID, in the "id" claim.
+	Email string
+	// SessionID is the "sid" claim, 0 for tokens issued before sessions
+	// existed.
+	SessionID uint
 }
 
-func GenerateRefreshToken(secret, id string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":  id,
-		"exp": time.Now().Add(time.Hour * 3).Unix(),
+// GenerateToken issues an access token for userID within sessionID.
+func GenerateToken(keys *Keys, userID uint, sessionID uint) (string, error) {
+	now := time.Now()
+	return keys.Sign(jwt.MapClaims{
+		"sub": strconv.FormatUint(uint64(userID), 10),
+		"sid": sessionID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour * 3).Unix(),
 	})
-
-	return token.SignedString([]byte(secret))
 }
 
-func ParseToken(secret, tokenString string) (string, error) {
-	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
-
+// ParseAccessToken checks an access token and returns its bearer.
+func ParseAccessToken(keys *Keys, tokenString string) (*AccessToken, error) {
+	claims, err := keys.Parse(strings.TrimPrefix(tokenString, "Bearer "))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	if _, ok := claims["purpose"]; ok {
+		return nil, errors.New("not an access token")
+	}
+	accessToken := &AccessToken{}
+	if sessionID, ok := claims["sid"].(float64); ok {
+		accessToken.SessionID = uint(sessionID)
+	}
+	if subject, ok := claims["sub"].(string); ok {
+		userID, err := strconv.ParseUint(subject, 10, 0)
+		if err != nil || userID == 0 {
+			return nil, errors.New("invalid subject")
+		}
+		accessToken.UserID = uint(userID)
+		return accessToken, nil
+	}
+	if email, ok := claims["id"].(string); ok {
+		accessToken.Email = email
+		return accessToken, nil
+	}
+	return nil, errors.New("token without subject")
+}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims["id"].(string), nil
+// FindUser loads the bearer of an access token.
+func (accessToken *AccessToken) FindUser(users dbmodel.UserRepository) (*dbmodel.User, error) {
+	if accessToken.UserID == 0 {
+		return users.FindByEmail(accessToken.Email)
 	}
-	return "", err
+	return users.FindByID(accessToken.UserID)
 }
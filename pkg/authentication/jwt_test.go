@@ -0,0 +1,181 @@
+package authentication
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// writeEd25519Key writes a new private key and its public half as PEM
+// files and returns their paths.
+func writeEd25519Key(t *testing.T, name string) (string, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath := filepath.Join(t.TempDir(), name+".pem")
+	publicPath := filepath.Join(t.TempDir(), name+".pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func loadTestKeys(t *testing.T, secret string, signingKey string, verificationKeys string) *Keys {
+	t.Helper()
+	t.Setenv("JWT_SECRET", secret)
+	t.Setenv("JWT_SIGNING_KEY", signingKey)
+	t.Setenv("JWT_VERIFICATION_KEYS", verificationKeys)
+	keys, err := LoadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func signHS256(t *testing.T, secret string, header map[string]interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	for name, value := range header {
+		token.Header[name] = value
+	}
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestParseAccessToken(t *testing.T) {
+	oldKey, oldPublic := writeEd25519Key(t, "old")
+	newKey, _ := writeEd25519Key(t, "new")
+	secretKeys := loadTestKeys(t, "secret", "", "")
+	oldKeys := loadTestKeys(t, "secret", oldKey, "")
+	rotatedKeys := loadTestKeys(t, "secret", newKey, oldPublic)
+	otherKeys := loadTestKeys(t, "", newKey, "")
+	expiry := time.Now().Add(time.Hour).Unix()
+
+	sign := func(keys *Keys, userID uint, sessionID uint) func(t *testing.T) string {
+		return func(t *testing.T) string {
+			token, err := GenerateToken(keys, userID, sessionID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return token
+		}
+	}
+	tests := []struct {
+		name  string
+		keys  *Keys
+		token func(t *testing.T) string
+		want  *AccessToken
+	}{
+		{name: "hmac", keys: secretKeys, token: sign(secretKeys, 7, 3), want: &AccessToken{UserID: 7, SessionID: 3}},
+		{name: "bearer prefix", keys: secretKeys, token: func(t *testing.T) string { return "Bearer " + sign(secretKeys, 7, 3)(t) }, want: &AccessToken{UserID: 7, SessionID: 3}},
+		{name: "ed25519 with kid", keys: oldKeys, token: sign(oldKeys, 7, 3), want: &AccessToken{UserID: 7, SessionID: 3}},
+		{name: "previous signing key", keys: rotatedKeys, token: sign(oldKeys, 7, 3), want: &AccessToken{UserID: 7, SessionID: 3}},
+		{name: "hmac after a signing key was added", keys: rotatedKeys, token: sign(secretKeys, 7, 3), want: &AccessToken{UserID: 7, SessionID: 3}},
+		{
+			name: "legacy email subject without issuer",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"id": "alice@example.com", "exp": expiry})
+			},
+			want: &AccessToken{Email: "alice@example.com"},
+		},
+		{name: "unknown kid", keys: otherKeys, token: sign(oldKeys, 7, 3)},
+		{name: "hmac without a secret", keys: otherKeys, token: sign(secretKeys, 7, 3)},
+		{
+			name: "hmac naming the kid of a public key",
+			keys: oldKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", map[string]interface{}{"kid": oldKeys.signing.id}, jwt.MapClaims{"sub": "7", "exp": expiry, "iss": DefaultIssuer, "aud": DefaultIssuer})
+			},
+		},
+		{
+			name: "wrong secret",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "other", nil, jwt.MapClaims{"sub": "7", "exp": expiry})
+			},
+		},
+		{
+			name: "other audience",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"sub": "7", "exp": expiry, "iss": DefaultIssuer, "aud": "other"})
+			},
+		},
+		{
+			name: "expired",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"sub": "7", "exp": time.Now().Add(-time.Minute).Unix()})
+			},
+		},
+		{
+			name: "token with a purpose",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"sub": "7", "exp": expiry, "purpose": "verify"})
+			},
+		},
+		{
+			name: "invalid subject",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"sub": "alice", "exp": expiry})
+			},
+		},
+		{
+			name: "zero subject",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"sub": "0", "exp": expiry})
+			},
+		},
+		{
+			name: "no subject",
+			keys: secretKeys,
+			token: func(t *testing.T) string {
+				return signHS256(t, "secret", nil, jwt.MapClaims{"exp": expiry})
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseAccessToken(test.keys, test.token(t))
+			if test.want == nil {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package authentication
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/golang-jwt/jwt"
+)
+
+// DefaultIssuer is the iss and aud claim of tokens when JWT_ISSUER and
+// JWT_AUDIENCE are not set.
+const DefaultIssuer = "yplanning"
+
+// key is one signing or verification key. Asymmetric keys are identified
+// by a kid derived from their public half; the HMAC secret has none.
+type key struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// Keys signs the access tokens and checks them. Tokens are signed with one
+// key and verified with any key of the set whose kid and algorithm they
+// name, so that a new key can be introduced while the tokens signed with
+// the previous one are still in use.
+type Keys struct {
+	Issuer   string
+	Audience string
+	signing  *key
+	verify   map[string]*key
+}
+
+// LoadKeys builds the key set from the environment:
+//
+//   - JWT_SIGNING_KEY, the path of a PEM private key (RSA for RS256 or
+//     Ed25519 for EdDSA) that signs new tokens;
+//   - JWT_VERIFICATION_KEYS, comma-separated paths of PEM public keys that
+//     are still accepted, typically the previous signing keys;
+//   - JWT_SECRET, an HMAC secret that signs tokens with HS256 when there is
+//     no signing key, and otherwise only verifies the tokens it signed;
+//   - JWT_ISSUER and JWT_AUDIENCE, the iss and aud claims.
+func LoadKeys() (*Keys, error) {
+	keys := &Keys{
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		verify:   make(map[string]*key),
+	}
+	if keys.Issuer == "" {
+		keys.Issuer = DefaultIssuer
+	}
+	if keys.Audience == "" {
+		keys.Audience = DefaultIssuer
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		keys.signing = &key{method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+		keys.verify[""] = keys.signing
+	}
+	if path := os.Getenv("JWT_SIGNING_KEY"); path != "" {
+		signing, err := readPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY: %w", err)
+		}
+		keys.signing = signing
+		keys.verify[signing.id] = signing
+	}
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEYS"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		verification, err := readPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEYS: %w", err)
+		}
+		keys.verify[verification.id] = verification
+	}
+	if keys.signing == nil {
+		return nil, errors.New("set JWT_SIGNING_KEY or JWT_SECRET")
+	}
+	return keys, nil
+}
+
+// Sign signs claims with the signing key, adding the issuer and audience.
+func (keys *Keys) Sign(claims jwt.MapClaims) (string, error) {
+	claims["iss"], claims["aud"] = keys.Issuer, keys.Audience
+	token := jwt.NewWithClaims(keys.signing.method, claims)
+	if keys.signing.id != "" {
+		token.Header["kid"] = keys.signing.id
+	}
+	return token.SignedString(keys.signing.private)
+}
+
+// Parse checks the signature, algorithm, expiry, issuer and audience of a
+// token and returns its claims. HMAC tokens issued before the issuer and
+// audience were added are accepted without them until they expire.
+func (keys *Keys) Parse(tokenString string) (jwt.MapClaims, error) {
+	var verifiedWith *key
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		verification, ok := keys.verify[kid]
+		if !ok {
+			return nil, errors.New("unknown key")
+		}
+		if token.Method.Alg() != verification.method.Alg() {
+			return nil, errors.New("unexpected signing method " + token.Method.Alg())
+		}
+		verifiedWith = verification
+		return verification.public, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if _, issued := claims["iss"]; !issued && verifiedWith.id == "" {
+		return claims, nil
+	}
+	if !claims.VerifyIssuer(keys.Issuer, true) || !claims.VerifyAudience(keys.Audience, true) {
+		return nil, errors.New("token issued for another service")
+	}
+	return claims, nil
+}
+
+// jwk is a public key in the JSON Web Key format (RFC 7517).
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS serves the public keys that verify access tokens, so that other
+// services can check them without sharing a secret. The HMAC secret is
+// never published.
+func (keys *Keys) JWKS(w http.ResponseWriter, r *http.Request) {
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{Keys: []jwk{}}
+	for _, verification := range keys.verify {
+		switch public := verification.public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, jwk{
+				KeyType:   "RSA",
+				KeyID:     verification.id,
+				Use:       "sig",
+				Algorithm: verification.method.Alg(),
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, jwk{
+				KeyType:   "OKP",
+				KeyID:     verification.id,
+				Use:       "sig",
+				Algorithm: verification.method.Alg(),
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	render.JSON(w, r, set)
+}
+
+func readPrivateKey(path string) (*key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	var private interface{}
+	if private, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		if private, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported key type", path)
+	}
+	signing, err := newKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	signing.private = private
+	return signing, nil
+}
+
+func readPublicKey(path string) (*key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	verification, err := newKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return verification, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", path)
+	}
+	return block, nil
+}
+
+// newKey pins the algorithm of a public key and derives its kid from it.
+func newKey(public crypto.PublicKey) (*key, error) {
+	var method jwt.SigningMethod
+	switch public.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	return &key{id: base64.RawURLEncoding.EncodeToString(sum[:12]), method: method, public: public}, nil
+}
@@ -2,10 +2,27 @@ package authentication
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
+
+	"yplanning/database/dbmodel"
 )
 
-func AuthMiddleware(secret string) func(http.Handler) http.Handler {
+// ErrSessionRevoked is returned by CheckSession for tokens of a session
+// that was signed out.
+var ErrSessionRevoked = errors.New("session revoked")
+
+// sessionTouchInterval limits how often a session's last use is written.
+const sessionTouchInterval = time.Minute
+
+type userKey struct{}
+
+type sessionKey struct{}
+
+// AuthMiddleware checks the access token of the request and loads its
+// bearer into the context, for UserFromContext.
+func AuthMiddleware(keys *Keys, users dbmodel.UserRepository, sessions dbmodel.SessionRepository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -15,21 +32,72 @@ func AuthMiddleware(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
-			id, err := ParseToken(secret, authHeader)
+			accessToken, err := ParseAccessToken(keys, authHeader)
 			if err != nil {
 				http.Error(w, "Invalid token",
 					http.StatusUnauthorized)
 				return
 			}
+			if err := CheckSession(sessions, accessToken.SessionID); err != nil {
+				http.Error(w, "Session expired, sign in again",
+					http.StatusUnauthorized)
+				return
+			}
+			user, err := accessToken.FindUser(users)
+			if err != nil {
+				http.Error(w, "User not found",
+					http.StatusUnauthorized)
+				return
+			}
 
-			ctx := context.WithValue(r.Context(), "id", id)
+			ctx := context.WithValue(r.Context(), userKey{}, user)
+			ctx = context.WithValue(ctx, sessionKey{}, accessToken.SessionID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-func GetUserFromContext(ctx context.Context) string {
-	id, _ := ctx.Value("id").(string)
+// CheckSession fails if the session of an access token was revoked, and
+// records that it was used. Tokens issued before sessions existed, with
+// sessionID 0, pass until they expire.
+func CheckSession(sessions dbmodel.SessionRepository, sessionID uint) error {
+	if sessionID == 0 {
+		return nil
+	}
+	session, err := sessions.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+	if now := time.Now(); now.Sub(session.LastUsedAt) > sessionTouchInterval {
+		return sessions.UpdateLastUsedAt(session.ID, now)
+	}
+	return nil
+}
+
+// QueryTokenMiddleware accepts the access token as an access_token query
+// parameter for clients that cannot set headers, such as EventSource and
+// browser WebSockets. It must run before AuthMiddleware.
+func QueryTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserFromContext returns the user loaded by AuthMiddleware.
+func UserFromContext(ctx context.Context) *dbmodel.User {
+	user, _ := ctx.Value(userKey{}).(*dbmodel.User)
+	return user
+}
 
-	return id
+// GetSessionFromContext returns the session of the access token, or 0 for
+// tokens issued before sessions existed.
+func GetSessionFromContext(ctx context.Context) uint {
+	sessionID, _ := ctx.Value(sessionKey{}).(uint)
+	return sessionID
 }
@@ -0,0 +1,71 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/models"
+)
+
+const (
+	// DefaultRefreshTokenTTL is how long a refresh token stays valid when
+	// REFRESH_TOKEN_TTL is not set. Each use restarts it.
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+	tokenBytes             = 32
+)
+
+// RefreshTokenTTL reads the lifetime of refresh tokens from
+// REFRESH_TOKEN_TTL, a Go duration such as "720h".
+func RefreshTokenTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return DefaultRefreshTokenTTL
+}
+
+// newRefreshToken returns a refresh token for userID in sessionID, and the
+// record that stores its hash.
+func newRefreshToken(userID uint, sessionID uint) (string, *dbmodel.RefreshToken, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &dbmodel.RefreshToken{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(RefreshTokenTTL()),
+	}, nil
+}
+
+// tokenResponse pairs a new access token for user in sessionID with
+// refreshToken.
+func tokenResponse(keys *Keys, user *dbmodel.User, sessionID uint, refreshToken string) (*models.TokenResponse, error) {
+	accessToken, err := GenerateToken(keys, user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "bearer",
+	}, nil
+}
+
+func generateToken() (string, error) {
+	buffer := make([]byte, tokenBytes)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
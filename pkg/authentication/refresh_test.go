@@ -0,0 +1,186 @@
+package authentication
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/models"
+)
+
+// signInTestUser opens a session for a new user and returns its tokens.
+func signInTestUser(t *testing.T, config *AuthConfig, email string) (*dbmodel.User, *models.TokenResponse) {
+	t.Helper()
+	user := createTestUser(t, config, email, "password")
+	tokens, err := config.signIn(httptest.NewRequest(http.MethodPost, "/", nil), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user, tokens
+}
+
+func refresh(config *AuthConfig, refreshToken string) *httptest.ResponseRecorder {
+	return post(config.Refresh, `{"refresh_token":"`+refreshToken+`"}`)
+}
+
+func newTestRefreshConfig(t *testing.T) *AuthConfig {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+	config, _ := newTestAuthConfig(t)
+	keys, err := LoadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Keys = keys
+	return config
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	config := newTestRefreshConfig(t)
+	_, tokens := signInTestUser(t, config, "alice@example.com")
+
+	for i := 0; i < 3; i++ {
+		response := refresh(config, tokens.RefreshToken)
+		if response.Code != http.StatusOK {
+			t.Fatalf("refresh %d = %d %s", i, response.Code, response.Body)
+		}
+		next := &models.TokenResponse{}
+		if err := json.Unmarshal(response.Body.Bytes(), next); err != nil {
+			t.Fatal(err)
+		}
+		if next.RefreshToken == tokens.RefreshToken {
+			t.Fatal("refresh returned the same refresh token")
+		}
+		if _, err := ParseAccessToken(config.Keys, next.AccessToken); err != nil {
+			t.Fatalf("new access token: %v", err)
+		}
+		tokens = next
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	config := newTestRefreshConfig(t)
+	_, tokens := signInTestUser(t, config, "alice@example.com")
+
+	response := refresh(config, tokens.RefreshToken)
+	if response.Code != http.StatusOK {
+		t.Fatalf("refresh = %d %s", response.Code, response.Body)
+	}
+	next := &models.TokenResponse{}
+	if err := json.Unmarshal(response.Body.Bytes(), next); err != nil {
+		t.Fatal(err)
+	}
+
+	if response := refresh(config, tokens.RefreshToken); response.Code != http.StatusUnauthorized {
+		t.Fatalf("reused refresh token = %d, want 401", response.Code)
+	}
+	accessToken, err := ParseAccessToken(config.Keys, next.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := config.SessionRepository.FindByID(accessToken.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.RevokedAt == nil {
+		t.Error("session still active after a refresh token was reused")
+	}
+	if response := refresh(config, next.RefreshToken); response.Code != http.StatusUnauthorized {
+		t.Errorf("refresh token issued before the reuse = %d, want 401", response.Code)
+	}
+}
+
+func TestRefreshRejectedTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, config *AuthConfig, tokens *models.TokenResponse) string
+	}{
+		{
+			name: "unknown",
+			prepare: func(t *testing.T, config *AuthConfig, tokens *models.TokenResponse) string {
+				return "not-a-refresh-token"
+			},
+		},
+		{
+			name: "expired",
+			prepare: func(t *testing.T, config *AuthConfig, tokens *models.TokenResponse) string {
+				accessToken, err := ParseAccessToken(config.Keys, tokens.AccessToken)
+				if err != nil {
+					t.Fatal(err)
+				}
+				refreshToken, record, err := newRefreshToken(accessToken.UserID, accessToken.SessionID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				record.ExpiresAt = time.Now().Add(-time.Minute)
+				if _, err := config.RefreshTokenRepository.Create(record); err != nil {
+					t.Fatal(err)
+				}
+				return refreshToken
+			},
+		},
+		{
+			name: "logged out",
+			prepare: func(t *testing.T, config *AuthConfig, tokens *models.TokenResponse) string {
+				if response := post(config.Logout, `{"refresh_token":"`+tokens.RefreshToken+`"}`); response.Code != http.StatusOK {
+					t.Fatalf("logout = %d %s", response.Code, response.Body)
+				}
+				return tokens.RefreshToken
+			},
+		},
+		{
+			name: "user signed out everywhere",
+			prepare: func(t *testing.T, config *AuthConfig, tokens *models.TokenResponse) string {
+				accessToken, err := ParseAccessToken(config.Keys, tokens.AccessToken)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := config.SessionRepository.RevokeByUserID(accessToken.UserID); err != nil {
+					t.Fatal(err)
+				}
+				return tokens.RefreshToken
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTestRefreshConfig(t)
+			_, tokens := signInTestUser(t, config, "alice@example.com")
+			if response := refresh(config, test.prepare(t, config, tokens)); response.Code != http.StatusUnauthorized {
+				t.Errorf("refresh = %d %s, want 401", response.Code, response.Body)
+			}
+		})
+	}
+}
+
+func TestRotateRefusesExchangedToken(t *testing.T) {
+	config := newTestRefreshConfig(t)
+	user, tokens := signInTestUser(t, config, "alice@example.com")
+	used, err := config.RefreshTokenRepository.FindByTokenHash(hashToken(tokens.RefreshToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both requests read the token before either exchanged it.
+	_, first, err := newRefreshToken(user.ID, used.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, second, err := newRefreshToken(user.ID, used.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.RefreshTokenRepository.Rotate(used, first); err != nil {
+		t.Fatalf("first rotation: %v", err)
+	}
+	if err := config.RefreshTokenRepository.Rotate(used, second); !errors.Is(err, dbmodel.ErrRefreshTokenReused) {
+		t.Fatalf("second rotation = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := config.RefreshTokenRepository.FindByTokenHash(second.TokenHash); err == nil {
+		t.Error("the refused rotation stored its token")
+	}
+}
@@ -0,0 +1,119 @@
+package authentication
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/mail"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/render"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordResetTTL is how long a password reset email stays valid.
+const PasswordResetTTL = time.Hour
+
+// @Summary Request a password reset
+// @Description Email a single-use password reset token to the account with this email, valid for an hour. The answer is the same whether or not the account exists. When PASSWORD_RESET_URL is set, the email links to it with the token as a token query parameter.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Account email"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Router /auth/forgot-password [post]
+func (config *AuthConfig) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	req := &models.ForgotPasswordRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Looking the account up, storing the token and sending happen in the
+	// background, so that the answer takes as long for existing accounts as
+	// for unknown ones.
+	go config.sendPasswordReset(req.Email)
+	render.JSON(w, r, "If an account uses this email, a reset email was sent to it")
+}
+
+// sendPasswordReset emails a new reset token to the account with this
+// email, if there is one. Failures are only logged, as nobody waits for
+// them.
+func (config *AuthConfig) sendPasswordReset(email string) {
+	user, err := config.UserRepository.FindByEmail(email)
+	if err != nil {
+		return
+	}
+	token, err := generateToken()
+	if err != nil {
+		log.Printf("Password reset for user %d: failed to generate token: %v", user.ID, err)
+		return
+	}
+	_, err = config.PasswordResetRepository.Create(&dbmodel.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(PasswordResetTTL),
+	})
+	if err != nil {
+		log.Printf("Password reset for user %d: failed to store token: %v", user.ID, err)
+		return
+	}
+	if err := config.Mailer.Send(passwordResetMessage(user, token)); err != nil {
+		log.Printf("Password reset email to user %d: %v", user.ID, err)
+	}
+}
+
+// @Summary Reset a password
+// @Description Choose a new password with a token from a reset email. The token works once: it and any other reset token of the account stop working, and every session of the account is signed out.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /auth/reset-password [post]
+func (config *AuthConfig) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	req := &models.ResetPasswordRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	passwordReset, err := config.PasswordResetRepository.FindByTokenHash(hashToken(req.Token))
+	if err != nil || passwordReset.UsedAt != nil || !passwordReset.ExpiresAt.After(time.Now()) {
+		http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+		return
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+		return
+	}
+	err = config.PasswordResetRepository.Use(passwordReset, string(hashedPassword))
+	if errors.Is(err, dbmodel.ErrPasswordResetUsed) {
+		http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, "Password successfully reset")
+}
+
+func passwordResetMessage(user *dbmodel.User, token string) mail.Message {
+	body := "Hello " + user.Username + ",\n\n" +
+		"Someone asked to reset the password of your yplanning account. "
+	if resetURL := os.Getenv("PASSWORD_RESET_URL"); resetURL != "" {
+		body += "Choose a new one at:\n\n" + resetURL + "?token=" + url.QueryEscape(token) + "\n\n"
+	} else {
+		body += "Choose a new one with this reset token:\n\n" + token + "\n\n"
+	}
+	body += "It expires in an hour and works once. If you did not ask for it, ignore this email."
+	return mail.Message{To: user.Email, Subject: "Reset your yplanning password", Body: body}
+}
@@ -0,0 +1,211 @@
+package authentication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/mail"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recordingSender keeps the messages sent, in order.
+type recordingSender struct {
+	messages chan mail.Message
+}
+
+func (sender *recordingSender) Send(message mail.Message) error {
+	sender.messages <- message
+	return nil
+}
+
+func newTestAuthConfig(t *testing.T) (*AuthConfig, *recordingSender) {
+	t.Helper()
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{messages: make(chan mail.Message, 10)}
+	cfg.Mailer = sender
+	return New(cfg, nil), sender
+}
+
+func createTestUser(t *testing.T, config *AuthConfig, email string, password string) *dbmodel.User {
+	t.Helper()
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := config.UserRepository.Create(&dbmodel.User{Username: strings.Split(email, "@")[0], Email: email, Password: string(hashedPassword)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func post(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	handler(recorder, request)
+	return recorder
+}
+
+var resetLink = regexp.MustCompile(`token=(\S+)`)
+
+// requestReset asks for a reset email and returns the token it contains.
+func requestReset(t *testing.T, config *AuthConfig, sender *recordingSender, email string) string {
+	t.Helper()
+	if response := post(config.ForgotPassword, `{"email":"`+email+`"}`); response.Code != http.StatusOK {
+		t.Fatalf("forgot password: %d %s", response.Code, response.Body)
+	}
+	select {
+	case message := <-sender.messages:
+		match := resetLink.FindStringSubmatch(message.Body)
+		if match == nil {
+			t.Fatalf("no reset link in %q", message.Body)
+		}
+		token, err := url.QueryUnescape(match[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reset email sent")
+		return ""
+	}
+}
+
+func reset(config *AuthConfig, token string, password string) int {
+	return post(config.ResetPassword, `{"token":"`+token+`","password":"`+password+`"}`).Code
+}
+
+func passwordIs(t *testing.T, config *AuthConfig, userID uint, password string) bool {
+	t.Helper()
+	user, err := config.UserRepository.FindByID(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+}
+
+func TestPasswordResetLifecycle(t *testing.T) {
+	t.Setenv("PASSWORD_RESET_URL", "https://app.example.com/reset")
+	config, sender := newTestAuthConfig(t)
+	user := createTestUser(t, config, "alice@example.com", "old")
+	session, err := config.SessionRepository.Create(&dbmodel.Session{UserID: user.ID, LastUsedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := requestReset(t, config, sender, user.Email)
+	if code := reset(config, token, "new"); code != http.StatusOK {
+		t.Fatalf("reset = %d, want %d", code, http.StatusOK)
+	}
+	if !passwordIs(t, config, user.ID, "new") {
+		t.Fatal("password not changed")
+	}
+	if revoked, err := config.SessionRepository.FindByID(session.ID); err != nil || revoked.RevokedAt == nil {
+		t.Fatalf("session not revoked: %+v, %v", revoked, err)
+	}
+
+	// The token works once.
+	if code := reset(config, token, "again"); code != http.StatusBadRequest {
+		t.Fatalf("second reset = %d, want %d", code, http.StatusBadRequest)
+	}
+	if !passwordIs(t, config, user.ID, "new") {
+		t.Fatal("password changed by a used token")
+	}
+}
+
+func TestPasswordResetRejectedTokens(t *testing.T) {
+	t.Setenv("PASSWORD_RESET_URL", "https://app.example.com/reset")
+	tests := []struct {
+		name  string
+		token func(t *testing.T, config *AuthConfig, sender *recordingSender, user *dbmodel.User) string
+	}{
+		{
+			name: "unknown",
+			token: func(t *testing.T, config *AuthConfig, sender *recordingSender, user *dbmodel.User) string {
+				return "not-a-token"
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T, config *AuthConfig, sender *recordingSender, user *dbmodel.User) string {
+				token := requestReset(t, config, sender, user.Email)
+				if err := config.Database.Model(&dbmodel.PasswordReset{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+		},
+		{
+			name: "superseded by a later email",
+			token: func(t *testing.T, config *AuthConfig, sender *recordingSender, user *dbmodel.User) string {
+				token := requestReset(t, config, sender, user.Email)
+				requestReset(t, config, sender, user.Email)
+				return token
+			},
+		},
+		{
+			name: "discarded by another reset",
+			token: func(t *testing.T, config *AuthConfig, sender *recordingSender, user *dbmodel.User) string {
+				token := requestReset(t, config, sender, user.Email)
+				// A second pending token, as if it was created concurrently.
+				other, err := generateToken()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := config.Database.Create(&dbmodel.PasswordReset{UserID: user.ID, TokenHash: hashToken(other), ExpiresAt: time.Now().Add(time.Hour)}).Error; err != nil {
+					t.Fatal(err)
+				}
+				if code := reset(config, token, "first"); code != http.StatusOK {
+					t.Fatalf("reset = %d, want %d", code, http.StatusOK)
+				}
+				return other
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, sender := newTestAuthConfig(t)
+			user := createTestUser(t, config, "bob@example.com", "old")
+			token := test.token(t, config, sender, user)
+			if code := reset(config, token, "stolen"); code != http.StatusBadRequest {
+				t.Fatalf("reset = %d, want %d", code, http.StatusBadRequest)
+			}
+			if passwordIs(t, config, user.ID, "stolen") {
+				t.Fatal("password changed by a rejected token")
+			}
+		})
+	}
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	config, sender := newTestAuthConfig(t)
+	createTestUser(t, config, "carol@example.com", "old")
+	unknown := post(config.ForgotPassword, `{"email":"nobody@example.com"}`)
+	if unknown.Code != http.StatusOK {
+		t.Fatalf("forgot password = %d, want %d", unknown.Code, http.StatusOK)
+	}
+	select {
+	case message := <-sender.messages:
+		t.Fatalf("email sent for an unknown account: %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	known := post(config.ForgotPassword, `{"email":"carol@example.com"}`)
+	if known.Code != unknown.Code || known.Body.String() != unknown.Body.String() {
+		t.Errorf("known account answered %d %q, unknown %d %q", known.Code, known.Body, unknown.Code, unknown.Body)
+	}
+	<-sender.messages
+}
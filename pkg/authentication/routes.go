@@ -11,13 +11,36 @@ auth routes:
 POST /auth/login
 POST /auth/refresh
 POST /auth/register
+POST /auth/logout
+POST /auth/forgot-password
+POST /auth/reset-password
+GET /auth/verify-email
+POST /auth/logout-all (authenticated)
+GET /auth/sessions (authenticated)
+DELETE /auth/sessions/{id} (authenticated)
+GET /auth/email (authenticated)
+PUT /auth/email (authenticated)
+POST /auth/verify-email/resend (authenticated)
 */
 
-func Routes(configuration *config.Config) chi.Router {
-	UserConfig := New(configuration)
+func Routes(configuration *config.Config, keys *Keys) chi.Router {
+	UserConfig := New(configuration, keys)
 	router := chi.NewRouter()
 	router.Post("/login", UserConfig.Login)
 	router.Post("/refresh", UserConfig.Refresh)
 	router.Post("/register", UserConfig.Register)
+	router.Post("/logout", UserConfig.Logout)
+	router.Post("/forgot-password", UserConfig.ForgotPassword)
+	router.Post("/reset-password", UserConfig.ResetPassword)
+	router.Get("/verify-email", UserConfig.VerifyEmail)
+	router.Group(func(r chi.Router) {
+		r.Use(AuthMiddleware(keys, configuration.UserRepository, configuration.SessionRepository))
+		r.Post("/logout-all", UserConfig.LogoutAll)
+		r.Get("/sessions", UserConfig.GetSessions)
+		r.Delete("/sessions/{id}", UserConfig.RevokeSession)
+		r.Get("/email", UserConfig.GetEmail)
+		r.Put("/email", UserConfig.ChangeEmail)
+		r.Post("/verify-email/resend", UserConfig.ResendVerification)
+	})
 	return router
 }
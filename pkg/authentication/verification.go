@@ -0,0 +1,214 @@
+package authentication
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/mail"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/render"
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// EmailVerificationTTL is how long a verification link stays valid.
+	EmailVerificationTTL = 48 * time.Hour
+	// verificationPurpose marks verification tokens, which are signed
+	// with the same keys as access tokens but never accepted as such.
+	verificationPurpose = "verify_email"
+)
+
+// @Summary Verify an email address
+// @Description Follow the link of a verification email. It marks the address as verified, or makes it the account's email if the user asked to change to it. Links stop working once another address was asked for.
+// @Tags authentication
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} models.EmailResponse
+// @Failure 400 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
+// @Router /auth/verify-email [get]
+func (config *AuthConfig) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	userID, email, err := config.parseVerificationToken(r.URL.Query().Get("token"))
+	if err != nil {
+		http.Error(w, "Invalid or expired verification link", http.StatusBadRequest)
+		return
+	}
+	user, err := config.UserRepository.FindByID(userID)
+	if err != nil || (email != user.Email && email != user.PendingEmail) {
+		http.Error(w, "Invalid or expired verification link", http.StatusBadRequest)
+		return
+	}
+	if email == user.Email && user.Verified {
+		render.JSON(w, r, toEmailResponse(user))
+		return
+	}
+	if email != user.Email {
+		if _, err := config.UserRepository.FindByEmail(email); err == nil {
+			http.Error(w, "Email already in use", http.StatusConflict)
+			return
+		}
+	}
+	if err := config.UserRepository.VerifyEmailByID(user.ID, email); err != nil {
+		http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+		return
+	}
+	user, err = config.UserRepository.FindByID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve user", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toEmailResponse(user))
+}
+
+// @Summary Get my email
+// @Description Get the caller's email, whether it is verified, and the address waiting for verification to replace it, if any.
+// @Tags authentication
+// @Produce json
+// @Success 200 {object} models.EmailResponse
+// @Failure 401 {string} string
+// @Security BearerAuth
+// @Router /auth/email [get]
+func (config *AuthConfig) GetEmail(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, toEmailResponse(UserFromContext(r.Context())))
+}
+
+// @Summary Change my email
+// @Description Ask to change the caller's email. A verification link is sent to the new address, which replaces the current one once followed; until then the current email stays in use. Asking for the current email cancels a pending change.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.EmailRequest true "New email"
+// @Success 200 {object} models.EmailResponse
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /auth/email [put]
+func (config *AuthConfig) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	req := &models.EmailRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Email == user.Email {
+		req.Email = ""
+	} else if _, err := config.UserRepository.FindByEmail(req.Email); err == nil {
+		http.Error(w, "Email already in use", http.StatusConflict)
+		return
+	}
+	if err := config.UserRepository.SetPendingEmailByID(user.ID, req.Email); err != nil {
+		http.Error(w, "Failed to update email", http.StatusInternalServerError)
+		return
+	}
+	user.PendingEmail = req.Email
+	if req.Email != "" {
+		if err := config.sendVerification(user, req.Email); err != nil {
+			http.Error(w, "Failed to generate verification link: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	render.JSON(w, r, toEmailResponse(user))
+}
+
+// @Summary Resend the verification email
+// @Description Send a new verification link to the address waiting for verification, or to the caller's email if it is not verified yet.
+// @Tags authentication
+// @Produce json
+// @Success 200 {object} models.EmailResponse
+// @Failure 401 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /auth/verify-email/resend [post]
+func (config *AuthConfig) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	email := user.PendingEmail
+	if email == "" {
+		email = user.Email
+		if user.Verified {
+			http.Error(w, "Your email is already verified", http.StatusConflict)
+			return
+		}
+	}
+	if err := config.sendVerification(user, email); err != nil {
+		http.Error(w, "Failed to generate verification link: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toEmailResponse(user))
+}
+
+// sendVerification emails user a link verifying email. The link is signed,
+// so that nothing needs to be stored, and names the address it verifies,
+// so that it stops working once the user asks for another one.
+func (config *AuthConfig) sendVerification(user *dbmodel.User, email string) error {
+	now := time.Now()
+	token, err := config.Keys.Sign(jwt.MapClaims{
+		"sub":     strconv.FormatUint(uint64(user.ID), 10),
+		"email":   email,
+		"purpose": verificationPurpose,
+		"iat":     now.Unix(),
+		"exp":     now.Add(EmailVerificationTTL).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	message := verificationMessage(user, email, verificationURL(config.PublicURL, token))
+	go func() {
+		if err := config.Mailer.Send(message); err != nil {
+			log.Printf("Verification email to user %d: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// parseVerificationToken returns the user and the address a verification
+// token was issued for.
+func (config *AuthConfig) parseVerificationToken(token string) (uint, string, error) {
+	claims, err := config.Keys.Parse(token)
+	if err != nil {
+		return 0, "", err
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != verificationPurpose {
+		return 0, "", errors.New("not a verification token")
+	}
+	subject, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(subject, 10, 0)
+	if err != nil {
+		return 0, "", err
+	}
+	email, _ := claims["email"].(string)
+	if email == "" {
+		return 0, "", errors.New("verification token without email")
+	}
+	return uint(userID), email, nil
+}
+
+func verificationURL(publicURL string, token string) string {
+	return publicURL + "/api/auth/verify-email?token=" + url.QueryEscape(token)
+}
+
+func verificationMessage(user *dbmodel.User, email string, link string) mail.Message {
+	body := "Hello " + user.Username + ",\n\n" +
+		"Confirm that " + email + " is the address of your yplanning account by following this link:\n\n" +
+		link + "\n\n" +
+		"It expires in two days. If you did not ask for it, ignore this email."
+	return mail.Message{To: email, Subject: "Verify your yplanning email", Body: body}
+}
+
+func toEmailResponse(user *dbmodel.User) *models.EmailResponse {
+	return &models.EmailResponse{
+		Email:        user.Email,
+		Verified:     user.Verified,
+		VerifiedAt:   user.VerifiedAt,
+		PendingEmail: user.PendingEmail,
+	}
+}
@@ -0,0 +1,58 @@
+package authentication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterSendsVerificationLinkToPublicURL(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	config, sender := newTestAuthConfig(t)
+	keys, err := LoadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Keys = keys
+	config.PublicURL = "https://plan.example.com"
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "http://attacker.example.net/api/auth/register", strings.NewReader(`{"email":"dave@example.com","username":"dave","password":"p"}`))
+	config.Register(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("register = %d %s", recorder.Code, recorder.Body)
+	}
+
+	var link *url.URL
+	select {
+	case message := <-sender.messages:
+		for _, line := range strings.Split(message.Body, "\n") {
+			if strings.HasPrefix(line, "http") {
+				if link, err = url.Parse(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no verification email sent")
+	}
+	if link == nil || link.Scheme+"://"+link.Host != config.PublicURL || link.Path != "/api/auth/verify-email" {
+		t.Fatalf("verification link %v, want one under %s", link, config.PublicURL)
+	}
+
+	recorder = httptest.NewRecorder()
+	config.VerifyEmail(recorder, httptest.NewRequest(http.MethodGet, link.String(), nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("verify = %d %s", recorder.Code, recorder.Body)
+	}
+	user, err := config.UserRepository.FindByEmail("dave@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.Verified {
+		t.Fatal("user not verified by the link")
+	}
+}
@@ -0,0 +1,211 @@
+// Package authorization decides what the authenticated user may read and
+// change. Users own their dates, availabilities and account, what members
+// may do in a group depends on their role there, and administrators may act
+// on anything.
+//
+// Handlers answer 404 for records the caller may not see, so that their
+// existence does not leak, and 403 for changes to records they may see but
+// not change.
+package authorization
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+
+	"gorm.io/gorm"
+)
+
+// UserFromContext returns the authenticated user, loaded by
+// authentication.AuthMiddleware.
+func UserFromContext(ctx context.Context) *dbmodel.User {
+	return authentication.UserFromContext(ctx)
+}
+
+// CanChangeUser reports whether viewer may change the account of userID or
+// the records it owns.
+func CanChangeUser(viewer *dbmodel.User, userID uint) bool {
+	return viewer.ID == userID || viewer.IsAdmin
+}
+
+// CanSeeUser reports whether viewer may see userID and its calendar: itself,
+// anyone it shares a group with as more than a guest, or anyone for an
+// administrator.
+func CanSeeUser(groups dbmodel.GroupRepository, viewer *dbmodel.User, userID uint) (bool, error) {
+	if CanChangeUser(viewer, userID) {
+		return true, nil
+	}
+	return SharesGroup(groups, viewer.ID, userID)
+}
+
+// SharesGroup reports whether two users belong to a common group in which
+// neither is a guest.
+func SharesGroup(groups dbmodel.GroupRepository, userID uint, otherID uint) (bool, error) {
+	peerIDs, err := groups.FindPeerIDs(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, peerID := range peerIDs {
+		if peerID == otherID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// VisibleUserIDs keeps the users of userIDs that viewer may see, by the
+// rule of CanSeeUser, looking up the peers of viewer once.
+func VisibleUserIDs(groups dbmodel.GroupRepository, viewer *dbmodel.User, userIDs []uint) ([]uint, error) {
+	if viewer.IsAdmin {
+		return userIDs, nil
+	}
+	peerIDs, err := groups.FindPeerIDs(viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+	visible := map[uint]bool{viewer.ID: true}
+	for _, peerID := range peerIDs {
+		visible[peerID] = true
+	}
+	visibleIDs := make([]uint, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if visible[userID] {
+			visibleIDs = append(visibleIDs, userID)
+		}
+	}
+	return visibleIDs, nil
+}
+
+// CanUseColor reports whether viewer may give colorID to a date: no color,
+// a shared color or one of its own, or any color for an administrator.
+func CanUseColor(colors dbmodel.ColorRepository, viewer *dbmodel.User, colorID uint) (bool, error) {
+	if colorID == 0 {
+		return true, nil
+	}
+	color, err := colors.FindByID(colorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return color.UserID == 0 || CanChangeUser(viewer, color.UserID), nil
+}
+
+// CanSeeGroup reports whether viewer may see a group and its free/busy
+// time: its members, guests included, and administrators.
+func CanSeeGroup(groups dbmodel.GroupRepository, viewer *dbmodel.User, groupID uint) (bool, error) {
+	if viewer.IsAdmin {
+		return true, nil
+	}
+	return groups.IsMember(groupID, viewer.ID)
+}
+
+// GroupPermission is something a member may do in a group.
+type GroupPermission int
+
+const (
+	// SeeFreeBusy lets a member see when the group is free.
+	SeeFreeBusy GroupPermission = iota
+	// SeeMembers lets a member list the others and follow their calendars.
+	SeeMembers
+	// CreateGroupEvents lets a member add dates visible to the group.
+	CreateGroupEvents
+	// ManageMembers lets a member add, remove and change the role of the
+	// members below them, and administer the group's webhooks.
+	ManageMembers
+	// RenameGroup lets a member change the group's name.
+	RenameGroup
+	// DeleteGroup lets a member delete the group or give it away.
+	DeleteGroup
+)
+
+var minimumRoles = map[GroupPermission]string{
+	SeeFreeBusy:       dbmodel.RoleGuest,
+	SeeMembers:        dbmodel.RoleMember,
+	CreateGroupEvents: dbmodel.RoleMember,
+	ManageMembers:     dbmodel.RoleAdmin,
+	RenameGroup:       dbmodel.RoleAdmin,
+	DeleteGroup:       dbmodel.RoleOwner,
+}
+
+var roleRanks = map[string]int{
+	dbmodel.RoleGuest:  1,
+	dbmodel.RoleMember: 2,
+	dbmodel.RoleAdmin:  3,
+	dbmodel.RoleOwner:  4,
+}
+
+// ValidRole reports whether role is one of the group roles.
+func ValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// RoleAbove reports whether role is strictly more privileged than other.
+func RoleAbove(role string, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
+
+// GroupRole returns the role of viewer in a group, or "" if it is not a
+// member. Administrators act as owners of every group, and members of a
+// nested group are implicit members of its ancestors.
+func GroupRole(userGroups dbmodel.UserGroupRepository, viewer *dbmodel.User, group *dbmodel.Group) (string, error) {
+	if viewer.IsAdmin || group.CreatorID == viewer.ID {
+		return dbmodel.RoleOwner, nil
+	}
+	role := ""
+	userGroup, err := userGroups.FindByUserIDAndGroupID(viewer.ID, group.ID)
+	if err == nil {
+		role = userGroup.Role
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	inherited, err := userGroups.FindInheritedByUserIDAndGroupID(viewer.ID, group.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, userGroup := range inherited {
+		if inheritedRole := InheritedRole(userGroup.Role); RoleAbove(inheritedRole, role) {
+			role = inheritedRole
+		}
+	}
+	return role, nil
+}
+
+// InheritedRole is the role a member of a nested group holds in its
+// ancestors: their own, but never above member.
+func InheritedRole(role string) string {
+	if RoleAbove(role, dbmodel.RoleMember) {
+		return dbmodel.RoleMember
+	}
+	return role
+}
+
+// RoleCan reports whether a member with the given role holds permission.
+func RoleCan(role string, permission GroupPermission) bool {
+	return ValidRole(role) && roleRanks[role] >= roleRanks[minimumRoles[permission]]
+}
+
+// CanInGroup reports whether viewer holds permission in a group.
+func CanInGroup(userGroups dbmodel.UserGroupRepository, viewer *dbmodel.User, group *dbmodel.Group, permission GroupPermission) (bool, error) {
+	role, err := GroupRole(userGroups, viewer, group)
+	if err != nil {
+		return false, err
+	}
+	return RoleCan(role, permission), nil
+}
+
+// RequireAdmin restricts a route to administrators. It must run after
+// authentication.AuthMiddleware.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := UserFromContext(r.Context()); user == nil || !user.IsAdmin {
+			http.Error(w, "Administrators only", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,57 @@
+package authorization
+
+import (
+	"path/filepath"
+	"testing"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+)
+
+func TestCanUseColor(t *testing.T) {
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &dbmodel.User{Username: "alice"}
+	alice.ID = 1
+	bob := &dbmodel.User{Username: "bob"}
+	bob.ID = 2
+	admin := &dbmodel.User{Username: "admin", IsAdmin: true}
+	admin.ID = 3
+	color := func(hexCode string, userID uint) uint {
+		created, err := cfg.ColorRepository.Create(&dbmodel.Color{HexCode: hexCode, Name: hexCode, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created.ID
+	}
+	shared := color("#000000", 0)
+	alicesRed := color("#ff0000", alice.ID)
+	bobsRed := color("#ff0000", bob.ID)
+
+	tests := []struct {
+		name    string
+		viewer  *dbmodel.User
+		colorID uint
+		want    bool
+	}{
+		{name: "no color", viewer: alice, colorID: 0, want: true},
+		{name: "shared color", viewer: alice, colorID: shared, want: true},
+		{name: "own color", viewer: alice, colorID: alicesRed, want: true},
+		{name: "other user's color", viewer: alice, colorID: bobsRed, want: false},
+		{name: "unknown color", viewer: alice, colorID: 999, want: false},
+		{name: "administrator", viewer: admin, colorID: bobsRed, want: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := CanUseColor(cfg.ColorRepository, test.viewer, test.colorID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("CanUseColor = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package authorization
+
+import (
+	"os"
+	"strings"
+
+	"yplanning/database/dbmodel"
+)
+
+// What users who have not verified their email may be kept from doing,
+// as listed in UNVERIFIED_RESTRICTIONS.
+const (
+	// JoinGroups covers joining groups with an invite code or a join
+	// request.
+	JoinGroups = "join"
+	// BeInvited covers being added to groups or having a join request
+	// approved by a group admin.
+	BeInvited = "invite"
+	// CreateGroups covers creating groups.
+	CreateGroups = "create_group"
+)
+
+// DefaultUnverifiedRestrictions applies when UNVERIFIED_RESTRICTIONS is not
+// set. "none" lifts every restriction.
+const DefaultUnverifiedRestrictions = JoinGroups + "," + BeInvited
+
+// UnverifiedRestricted reports whether UNVERIFIED_RESTRICTIONS, a
+// comma-separated list such as "join,invite", keeps users who have not
+// verified their email from action.
+func UnverifiedRestricted(action string) bool {
+	restrictions := os.Getenv("UNVERIFIED_RESTRICTIONS")
+	if restrictions == "" {
+		restrictions = DefaultUnverifiedRestrictions
+	}
+	for _, restriction := range strings.Split(restrictions, ",") {
+		if strings.TrimSpace(restriction) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// VerificationAllows reports whether user may do action given whether it
+// verified its email.
+func VerificationAllows(user *dbmodel.User, action string) bool {
+	return user.Verified || !UnverifiedRestricted(action)
+}
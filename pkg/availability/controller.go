@@ -6,6 +6,8 @@ import (
 
 	"yplanning/config"
 	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
 	"yplanning/pkg/models"
 
 	"github.com/go-chi/chi/v5"
@@ -27,8 +29,10 @@ func NewAvailibilityConfig(afg *config.Config) *AvailabilityConfig {
 // @Produce json
 // @Param availability body models.AvailabilityRequest true "Availability information"
 // @Success 200 {object} models.AvailabilityResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /availability/ [post]
 func (config *AvailabilityConfig) CreateAvailability(w http.ResponseWriter, r *http.Request) {
 	var availabilityRequest models.AvailabilityRequest
@@ -36,6 +40,10 @@ func (config *AvailabilityConfig) CreateAvailability(w http.ResponseWriter, r *h
 		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if !authorization.CanChangeUser(authorization.UserFromContext(r.Context()), availabilityRequest.UserID) {
+		http.Error(w, "You can only create your own availabilities", http.StatusForbidden)
+		return
+	}
 	availability := &dbmodel.Availability{
 		BeginTime: availabilityRequest.DateBegin,
 		EndTime:   availabilityRequest.DateEnd,
@@ -46,6 +54,7 @@ func (config *AvailabilityConfig) CreateAvailability(w http.ResponseWriter, r *h
 		http.Error(w, "Failed to create availability: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	config.Events.Publish(events.AvailabilityEvent(events.ActionCreated, createdAvailability))
 	availabilityResponse := &models.AvailabilityResponse{
 		ID:        createdAvailability.ID,
 		DateBegin: createdAvailability.BeginTime,
@@ -56,12 +65,14 @@ func (config *AvailabilityConfig) CreateAvailability(w http.ResponseWriter, r *h
 }
 
 // @Summary Get all availabilities
-// @Description Retrieve a list of all availabilities
+// @Description Retrieve a list of all availabilities (administrators only)
 // @Tags availabilities
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.AvailabilityResponse
-// @Failure 500 {object} http.Error
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /availability/availabilities [get]
 func (config *AvailabilityConfig) GetAllAvailability(w http.ResponseWriter, r *http.Request) {
 	availabilities, err := config.AvailabilityRepository.FindAll()
@@ -88,8 +99,10 @@ func (config *AvailabilityConfig) GetAllAvailability(w http.ResponseWriter, r *h
 // @Produce json
 // @Param id path int true "Availability ID"
 // @Success 200 {object} models.AvailabilityResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /availability/{id} [get]
 func (config *AvailabilityConfig) GetAvailabilityByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -106,6 +119,10 @@ func (config *AvailabilityConfig) GetAvailabilityByID(w http.ResponseWriter, r *
 		http.Error(w, "Failed to retrieve availability: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, authorization.UserFromContext(r.Context()), availability.UserID); err != nil || !visible {
+		http.Error(w, "Availability not found", http.StatusNotFound)
+		return
+	}
 	availabilityResponse := &models.AvailabilityResponse{
 		ID:        availability.ID,
 		DateBegin: availability.BeginTime,
@@ -122,8 +139,10 @@ func (config *AvailabilityConfig) GetAvailabilityByID(w http.ResponseWriter, r *
 // @Produce json
 // @Param userID path int true "User ID"
 // @Success 200 {array} models.AvailabilityResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /availability/user/{userID} [get]
 func (config *AvailabilityConfig) GetAvailabilitiesByUserID(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
@@ -135,6 +154,10 @@ func (config *AvailabilityConfig) GetAvailabilitiesByUserID(w http.ResponseWrite
 		http.Error(w, "user_id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, authorization.UserFromContext(r.Context()), uint(userID)); err != nil || !visible {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 	availabilities, err := config.AvailabilityRepository.FindByUserID(uint(userID))
 	if err != nil {
 		http.Error(w, "Failed to retrieve availabilities: "+err.Error(), http.StatusInternalServerError)
@@ -160,8 +183,11 @@ func (config *AvailabilityConfig) GetAvailabilitiesByUserID(w http.ResponseWrite
 // @Param id path int true "Availability ID"
 // @Param availability body models.AvailabilityRequest true "Availability information"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /availability/{id} [put]
 func (config *AvailabilityConfig) UpdateAvailability(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -173,11 +199,19 @@ func (config *AvailabilityConfig) UpdateAvailability(w http.ResponseWriter, r *h
 		http.Error(w, "id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	if _, ok := config.ownAvailability(w, viewer, uint(id)); !ok {
+		return
+	}
 	var dateRequest models.AvailabilityRequest
 	if err := render.Bind(r, &dateRequest); err != nil {
 		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if !authorization.CanChangeUser(viewer, dateRequest.UserID) {
+		http.Error(w, "You cannot give an availability to another user", http.StatusForbidden)
+		return
+	}
 	availability := &dbmodel.Availability{
 		BeginTime: dateRequest.DateBegin,
 		EndTime:   dateRequest.DateEnd,
@@ -188,6 +222,9 @@ func (config *AvailabilityConfig) UpdateAvailability(w http.ResponseWriter, r *h
 		http.Error(w, "Failed to update availability: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if updated, err := config.AvailabilityRepository.FindByID(uint(id)); err == nil {
+		config.Events.Publish(events.AvailabilityEvent(events.ActionUpdated, updated))
+	}
 	render.JSON(w, r, map[string]string{"message": "Availability updated successfully"})
 }
 
@@ -198,22 +235,50 @@ func (config *AvailabilityConfig) UpdateAvailability(w http.ResponseWriter, r *h
 // @Produce json
 // @Param id path int true "Availability ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /availability/{id} [delete]
 func (config *AvailabilityConfig) DeleteAvailability(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "Error during id convertion: "+err.Error(), http.StatusBadRequest)
+		return
 	}
 	if id < 1 {
 		http.Error(w, "id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	availability, ok := config.ownAvailability(w, authorization.UserFromContext(r.Context()), uint(id))
+	if !ok {
+		return
+	}
 	err = config.AvailabilityRepository.DeleteByID(uint(id))
 	if err != nil {
 		http.Error(w, "Failed to delete availability: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	config.Events.Publish(events.AvailabilityEvent(events.ActionDeleted, availability))
 	render.JSON(w, r, map[string]string{"message": "Availability deleted successfully"})
 }
+
+// ownAvailability returns an availability that viewer may change, or writes
+// why it may not.
+func (config *AvailabilityConfig) ownAvailability(w http.ResponseWriter, viewer *dbmodel.User, id uint) (*dbmodel.Availability, bool) {
+	availability, err := config.AvailabilityRepository.FindByID(id)
+	if err != nil {
+		http.Error(w, "Availability not found", http.StatusNotFound)
+		return nil, false
+	}
+	if !authorization.CanChangeUser(viewer, availability.UserID) {
+		if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, availability.UserID); err != nil || !visible {
+			http.Error(w, "Availability not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "You can only change your own availabilities", http.StatusForbidden)
+		}
+		return nil, false
+	}
+	return availability, true
+}
@@ -2,6 +2,7 @@ package availability
 
 import (
 	"yplanning/config"
+	"yplanning/pkg/authorization"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -20,7 +21,7 @@ func Routes(config *config.Config) chi.Router {
 	AvailabilityConfig := NewAvailibilityConfig(config)
 	router := chi.NewRouter()
 	router.Post("/", AvailabilityConfig.CreateAvailability)
-	router.Get("/availabilities", AvailabilityConfig.GetAllAvailability) // FOR TESTING PURPOSES ONLY
+	router.With(authorization.RequireAdmin).Get("/availabilities", AvailabilityConfig.GetAllAvailability)
 	router.Get("/{id}", AvailabilityConfig.GetAvailabilityByID)
 	router.Get("/user/{userID}", AvailabilityConfig.GetAvailabilitiesByUserID)
 	router.Put("/{id}", AvailabilityConfig.UpdateAvailability)
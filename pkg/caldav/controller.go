@@ -0,0 +1,158 @@
+package caldav
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	appPasswordBytes        = 15
+	appPasswordPrefixLength = 8
+)
+
+type CalDAVConfig struct {
+	*config.Config
+}
+
+func NewCalDAVConfig(cfg *config.Config) *CalDAVConfig {
+	return &CalDAVConfig{Config: cfg}
+}
+
+// @Summary		List app passwords
+// @Description	List the app passwords the current user created for CalDAV clients
+// @Tags		caldav
+// @Produce		json
+// @Success		200	{array}		models.AppPasswordResponse
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/caldav/app-passwords [get]
+func (config *CalDAVConfig) GetAppPasswords(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	appPasswords, err := config.AppPasswordRepository.FindByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve app passwords", http.StatusInternalServerError)
+		return
+	}
+	appPasswordResponse := make([]models.AppPasswordResponse, 0)
+	for _, appPassword := range appPasswords {
+		appPasswordResponse = append(appPasswordResponse, models.AppPasswordResponse{
+			ID:         appPassword.ID,
+			Name:       appPassword.Name,
+			CreatedAt:  appPassword.CreatedAt,
+			LastUsedAt: appPassword.LastUsedAt,
+		})
+	}
+	render.JSON(w, r, appPasswordResponse)
+}
+
+// @Summary		Create an app password
+// @Description	Create a password for a CalDAV client (HTTP Basic with the username, or the email). The password is only returned once.
+// @Tags		caldav
+// @Accept		json
+// @Produce		json
+// @Param		request	body	models.AppPasswordRequest	true	"App password data"
+// @Success		200	{object}	models.AppPasswordResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/caldav/app-passwords [post]
+func (config *CalDAVConfig) CreateAppPassword(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	req := &models.AppPasswordRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	password, err := generateAppPassword()
+	if err != nil {
+		http.Error(w, "Failed to generate app password", http.StatusInternalServerError)
+		return
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Failed to generate app password", http.StatusInternalServerError)
+		return
+	}
+	appPassword := &dbmodel.AppPassword{UserID: user.ID, Name: req.Name, PasswordHash: string(hashedPassword), Prefix: appPasswordPrefix(password)}
+	created, err := config.AppPasswordRepository.Create(appPassword)
+	if err != nil {
+		http.Error(w, "Failed to create app password", http.StatusInternalServerError)
+		return
+	}
+
+	appPasswordResponse := &models.AppPasswordResponse{
+		ID:        created.ID,
+		Name:      created.Name,
+		CreatedAt: created.CreatedAt,
+		Username:  user.Username,
+		Password:  password,
+		ServerURL: config.PublicURL + davRoot,
+	}
+	render.JSON(w, r, appPasswordResponse)
+}
+
+// @Summary		Revoke an app password
+// @Description	Revoke one of the current user's app passwords
+// @Tags		caldav
+// @Produce		json
+// @Param		id	path	int	true	"App password ID"
+// @Success		200	{object}	map[string]string
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/caldav/app-passwords/{id} [delete]
+func (config *CalDAVConfig) RevokeAppPassword(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid app password ID", http.StatusBadRequest)
+		return
+	}
+	if id < 1 {
+		http.Error(w, "id must be >= 1", http.StatusBadRequest)
+		return
+	}
+	user := authentication.UserFromContext(r.Context())
+	appPassword, err := config.AppPasswordRepository.FindByID(uint(id))
+	if err != nil || appPassword.UserID != user.ID {
+		http.Error(w, "App password not found", http.StatusNotFound)
+		return
+	}
+	if err := config.AppPasswordRepository.DeleteByID(appPassword.ID); err != nil {
+		http.Error(w, "Failed to revoke app password", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, map[string]string{"message": "App password revoked successfully"})
+}
+
+// appPasswordPrefix is the part of an app password stored in clear.
+func appPasswordPrefix(password string) string {
+	if len(password) < appPasswordPrefixLength {
+		return password
+	}
+	return password[:appPasswordPrefixLength]
+}
+
+func generateAppPassword() (string, error) {
+	buffer := make([]byte, appPasswordBytes)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(buffer)), nil
+}
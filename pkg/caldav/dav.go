@@ -0,0 +1,554 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+	"yplanning/pkg/ical"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	davRoot          = "/dav/"
+	calendarName     = "default"
+	maxEventSize     = 1 << 20
+	expansionHorizon = 2
+	realm            = `Basic realm="yplanning", charset="UTF-8"`
+	// lastUsedInterval limits how often an app password's last use is
+	// written.
+	lastUsedInterval = time.Minute
+)
+
+// occurrenceReadOnly answers writes to one occurrence of a recurring date,
+// which is regenerated from the recurring date.
+const occurrenceReadOnly = "Occurrences of a recurring event are read-only: change the recurring event instead"
+
+type contextKey string
+
+const userKey contextKey = "caldav-user"
+
+type DAVConfig struct {
+	*config.Config
+}
+
+func NewDAVConfig(cfg *config.Config) *DAVConfig {
+	return &DAVConfig{Config: cfg}
+}
+
+// WellKnown points CalDAV clients doing service discovery at the DAV root.
+func WellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, davRoot, http.StatusMovedPermanently)
+}
+
+// BasicAuth authenticates DAV requests with the username, or the email when
+// the login contains "@", and one of the user's app passwords.
+func (config *DAVConfig) BasicAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		login, password, ok := r.BasicAuth()
+		if !ok {
+			config.unauthorized(w)
+			return
+		}
+		var user *dbmodel.User
+		var err error
+		if strings.Contains(login, "@") {
+			user, err = config.UserRepository.FindByEmail(login)
+		} else {
+			user, err = config.UserRepository.FindByUsername(login)
+		}
+		if err != nil {
+			config.unauthorized(w)
+			return
+		}
+		appPasswords, err := config.AppPasswordRepository.FindByUserIDAndPrefix(user.ID, appPasswordPrefix(password))
+		if err != nil {
+			http.Error(w, "Failed to retrieve app passwords", http.StatusInternalServerError)
+			return
+		}
+		for _, appPassword := range appPasswords {
+			if bcrypt.CompareHashAndPassword([]byte(appPassword.PasswordHash), []byte(password)) == nil {
+				if now := time.Now(); appPassword.LastUsedAt == nil || now.Sub(*appPassword.LastUsedAt) > lastUsedInterval {
+					if err := config.AppPasswordRepository.UpdateLastUsedAt(appPassword.ID, now); err != nil {
+						http.Error(w, "Failed to update app password", http.StatusInternalServerError)
+						return
+					}
+				}
+				ctx := context.WithValue(r.Context(), userKey, user)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+		config.unauthorized(w)
+	})
+}
+
+func (config *DAVConfig) unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", realm)
+	http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+}
+
+func currentUser(r *http.Request) *dbmodel.User {
+	user, _ := r.Context().Value(userKey).(*dbmodel.User)
+	return user
+}
+
+// ownUser makes sure the {username} in the URL is the authenticated user:
+// each user only sees their own calendar over CalDAV.
+func ownUser(w http.ResponseWriter, r *http.Request) (*dbmodel.User, bool) {
+	user := currentUser(r)
+	username, _ := url.PathUnescape(chi.URLParam(r, "username"))
+	if user == nil || username != user.Username {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+func (config *DAVConfig) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (config *DAVConfig) PropfindRoot(w http.ResponseWriter, r *http.Request) {
+	request, ok := readDAVRequest(w, r)
+	if !ok {
+		return
+	}
+	user := currentUser(r)
+	resource := newResource(davRoot)
+	resource.set(nsDAV, "resourcetype", "<d:collection/>")
+	resource.set(nsDAV, "current-user-principal", hrefElement(principalHref(user)))
+	status := newMultiStatus()
+	status.add(resource, request, false)
+	status.write(w)
+}
+
+func (config *DAVConfig) PropfindPrincipal(w http.ResponseWriter, r *http.Request) {
+	user, ok := ownUser(w, r)
+	if !ok {
+		return
+	}
+	request, ok := readDAVRequest(w, r)
+	if !ok {
+		return
+	}
+	resource := newResource(principalHref(user))
+	resource.set(nsDAV, "resourcetype", "<d:principal/>")
+	resource.set(nsDAV, "displayname", escape(user.Username))
+	resource.set(nsDAV, "current-user-principal", hrefElement(principalHref(user)))
+	resource.set(nsDAV, "principal-URL", hrefElement(principalHref(user)))
+	resource.set(nsCalDAV, "calendar-home-set", hrefElement(homeHref(user)))
+	resource.set(nsCalDAV, "calendar-user-address-set", hrefElement("mailto:"+user.Email))
+	status := newMultiStatus()
+	status.add(resource, request, false)
+	status.write(w)
+}
+
+func (config *DAVConfig) PropfindHome(w http.ResponseWriter, r *http.Request) {
+	user, ok := ownUser(w, r)
+	if !ok {
+		return
+	}
+	request, ok := readDAVRequest(w, r)
+	if !ok {
+		return
+	}
+	home := newResource(homeHref(user))
+	home.set(nsDAV, "resourcetype", "<d:collection/>")
+	home.set(nsDAV, "current-user-principal", hrefElement(principalHref(user)))
+	status := newMultiStatus()
+	status.add(home, request, false)
+	if r.Header.Get("Depth") != "0" {
+		dates, err := config.calendarDates(user)
+		if err != nil {
+			http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+			return
+		}
+		status.add(calendarResource(user, dates), request, false)
+	}
+	status.write(w)
+}
+
+func (config *DAVConfig) PropfindCalendar(w http.ResponseWriter, r *http.Request) {
+	user, ok := ownUser(w, r)
+	if !ok {
+		return
+	}
+	request, ok := readDAVRequest(w, r)
+	if !ok {
+		return
+	}
+	dates, err := config.calendarDates(user)
+	if err != nil {
+		http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+		return
+	}
+	status := newMultiStatus()
+	status.add(calendarResource(user, dates), request, false)
+	if r.Header.Get("Depth") != "0" {
+		for i := range dates {
+			status.add(eventResource(user, &dates[i]), request, false)
+		}
+	}
+	status.write(w)
+}
+
+func (config *DAVConfig) Report(w http.ResponseWriter, r *http.Request) {
+	user, ok := ownUser(w, r)
+	if !ok {
+		return
+	}
+	request, ok := readDAVRequest(w, r)
+	if !ok {
+		return
+	}
+	dates, err := config.calendarDates(user)
+	if err != nil {
+		http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+		return
+	}
+
+	status := newMultiStatus()
+	switch request.Report {
+	case "calendar-query":
+		for i := range dates {
+			if inRange(&dates[i], request.RangeFrom, request.RangeTo) {
+				status.add(eventResource(user, &dates[i]), request, true)
+			}
+		}
+	case "calendar-multiget":
+		byHref := make(map[string]*dbmodel.Date, len(dates))
+		for i := range dates {
+			href, _ := url.PathUnescape(eventHref(user, &dates[i]))
+			byHref[href] = &dates[i]
+		}
+		for _, href := range request.Hrefs {
+			path := href
+			if parsed, err := url.Parse(href); err == nil {
+				path = parsed.Path
+			}
+			if date, found := byHref[path]; found {
+				status.add(eventResource(user, date), request, true)
+			} else {
+				status.addStatus(href, http.StatusNotFound)
+			}
+		}
+	default:
+		http.Error(w, "Unsupported report "+request.Report, http.StatusNotImplemented)
+		return
+	}
+	status.write(w)
+}
+
+func (config *DAVConfig) PropfindEvent(w http.ResponseWriter, r *http.Request) {
+	user, date, ok := config.eventFromRequest(w, r, true)
+	if !ok {
+		return
+	}
+	request, ok := readDAVRequest(w, r)
+	if !ok {
+		return
+	}
+	status := newMultiStatus()
+	status.add(eventResource(user, date), request, false)
+	status.write(w)
+}
+
+func (config *DAVConfig) GetEvent(w http.ResponseWriter, r *http.Request) {
+	_, date, ok := config.eventFromRequest(w, r, true)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", etag(date))
+	w.Write([]byte(calendarData(date)))
+}
+
+func (config *DAVConfig) PutEvent(w http.ResponseWriter, r *http.Request) {
+	user, existing, ok := config.eventFromRequest(w, r, false)
+	if !ok {
+		return
+	}
+	if !checkPreconditions(w, r, existing) {
+		return
+	}
+	calendar, err := ical.Parse(io.LimitReader(r.Body, maxEventSize))
+	if err != nil {
+		http.Error(w, "Invalid iCalendar data: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var event *ical.Event
+	for i := range calendar.Events {
+		if calendar.Events[i].RecurrenceID.IsZero() {
+			event = &calendar.Events[i]
+			break
+		}
+	}
+	if event == nil {
+		http.Error(w, "The resource must contain one VEVENT", http.StatusBadRequest)
+		return
+	}
+	if uid := resourceUID(chi.URLParam(r, "name")); event.UID == "" {
+		event.UID = uid
+	} else if event.UID != uid {
+		http.Error(w, "The UID of the event must match the resource name", http.StatusBadRequest)
+		return
+	}
+	occurrences, err := event.Occurrences(event.Start.AddDate(expansionHorizon, 0, 0))
+	if err != nil {
+		http.Error(w, "Invalid recurrence: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	date := ical.ToDate(*event, user.ID)
+	code := http.StatusCreated
+	if existing != nil {
+		code = http.StatusNoContent
+		date.ID = existing.ID
+		date.CreatedAt = existing.CreatedAt
+		date.UpdatedAt = existing.UpdatedAt
+		date.ColorID = existing.ColorID
+		date.RecurrenceID = existing.RecurrenceID
+		if existing.UID == "" {
+			date.UID = ""
+		}
+		err = config.DateRepository.SaveWithOccurrences(date, occurrences)
+	} else {
+		date, err = config.DateRepository.CreateWithOccurrences(date, occurrences)
+	}
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		http.Error(w, occurrenceReadOnly, http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, dbmodel.ErrModified) {
+		http.Error(w, "The event was changed by another request", http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to save date", http.StatusInternalServerError)
+		return
+	}
+	saved, err := config.DateRepository.FindByID(date.ID)
+	if err == nil {
+		w.Header().Set("ETag", etag(saved))
+		action := events.ActionCreated
+		if existing != nil {
+			action = events.ActionUpdated
+		}
+		config.Events.Publish(events.DateEvent(action, saved))
+	}
+	w.WriteHeader(code)
+}
+
+func (config *DAVConfig) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	_, date, ok := config.eventFromRequest(w, r, true)
+	if !ok {
+		return
+	}
+	if !checkPreconditions(w, r, date) {
+		return
+	}
+	err := config.DateRepository.DeleteWithOccurrences(date)
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		http.Error(w, occurrenceReadOnly, http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, dbmodel.ErrModified) {
+		http.Error(w, "The event was changed by another request", http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete date", http.StatusInternalServerError)
+		return
+	}
+	config.Events.Publish(events.DateEvent(events.ActionDeleted, date))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// eventFromRequest resolves the {name}.ics resource of the URL. When
+// mustExist is false a missing resource is not an error and date is nil.
+func (config *DAVConfig) eventFromRequest(w http.ResponseWriter, r *http.Request, mustExist bool) (*dbmodel.User, *dbmodel.Date, bool) {
+	user, ok := ownUser(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	name := chi.URLParam(r, "name")
+	if !strings.HasSuffix(name, ".ics") {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	date := config.findDate(user, resourceUID(name))
+	if date == nil && mustExist {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	if date != nil && date.SubscriptionID != 0 {
+		http.Error(w, "Events of calendar subscriptions are read-only", http.StatusForbidden)
+		return nil, nil, false
+	}
+	return user, date, true
+}
+
+// findDate also finds subscription mirrors, so that a PUT cannot create a
+// second date with the UID of one.
+func (config *DAVConfig) findDate(user *dbmodel.User, uid string) *dbmodel.Date {
+	if date, err := config.DateRepository.FindByUIDAndUserID(uid, user.ID); err == nil {
+		return date
+	}
+	var id uint
+	if _, err := fmt.Sscanf(uid, "date-%d@yplanning", &id); err != nil {
+		return nil
+	}
+	date, err := config.DateRepository.FindByID(id)
+	if err != nil || date.UserID != user.ID || date.UID != "" || date.SubscriptionID != 0 || ical.DateUID(date) != uid {
+		return nil
+	}
+	return date
+}
+
+// calendarDates lists the dates exposed as CalDAV resources: the user's own
+// dates, without subscription mirrors nor instances generated from an RRULE.
+func (config *DAVConfig) calendarDates(user *dbmodel.User) ([]dbmodel.Date, error) {
+	dates, err := config.DateRepository.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	recurring := make(map[uint]bool)
+	for _, date := range dates {
+		if date.RRule != "" {
+			recurring[date.ID] = true
+		}
+	}
+	resources := make([]dbmodel.Date, 0, len(dates))
+	for _, date := range dates {
+		if date.SubscriptionID != 0 || (date.RecurrenceID != 0 && recurring[date.RecurrenceID]) {
+			continue
+		}
+		resources = append(resources, date)
+	}
+	return resources, nil
+}
+
+func readDAVRequest(w http.ResponseWriter, r *http.Request) (*davRequest, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return &davRequest{AllProp: true}, true
+	}
+	request, err := parseDAVRequest(bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, "Invalid XML body: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
+
+func checkPreconditions(w http.ResponseWriter, r *http.Request, date *dbmodel.Date) bool {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if date == nil || (ifMatch != "*" && ifMatch != etag(date)) {
+			http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+			return false
+		}
+	}
+	if r.Header.Get("If-None-Match") == "*" && date != nil {
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+func calendarResource(user *dbmodel.User, dates []dbmodel.Date) *davResource {
+	resource := newResource(calendarHref(user))
+	resource.set(nsDAV, "resourcetype", "<d:collection/><c:calendar/>")
+	resource.set(nsDAV, "displayname", escape(user.Username))
+	resource.set(nsDAV, "current-user-principal", hrefElement(principalHref(user)))
+	resource.set(nsDAV, "owner", hrefElement(principalHref(user)))
+	resource.set(nsDAV, "current-user-privilege-set", "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege><d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>")
+	resource.set(nsDAV, "supported-report-set", "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report><d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>")
+	resource.set(nsCalDAV, "supported-calendar-component-set", `<c:comp name="VEVENT"/>`)
+	resource.set(nsCalServer, "getctag", escape(ctag(dates)))
+	return resource
+}
+
+func eventResource(user *dbmodel.User, date *dbmodel.Date) *davResource {
+	resource := newResource(eventHref(user, date))
+	resource.set(nsDAV, "resourcetype", "")
+	resource.set(nsDAV, "getetag", escape(etag(date)))
+	resource.set(nsDAV, "getcontenttype", "text/calendar; charset=utf-8; component=VEVENT")
+	resource.set(nsDAV, "getlastmodified", date.UpdatedAt.UTC().Format(http.TimeFormat))
+	resource.set(nsCalDAV, "calendar-data", escape(calendarData(date)))
+	return resource
+}
+
+func calendarData(date *dbmodel.Date) string {
+	var buffer bytes.Buffer
+	ical.Write(&buffer, &ical.Calendar{Events: []ical.Event{ical.FromDate(date)}})
+	return buffer.String()
+}
+
+func inRange(date *dbmodel.Date, from time.Time, to time.Time) bool {
+	if !to.IsZero() && !date.BeginTime.Before(to) {
+		return false
+	}
+	if date.RRule != "" {
+		return true
+	}
+	return from.IsZero() || date.EndTime.After(from)
+}
+
+func etag(date *dbmodel.Date) string {
+	return `"` + strconv.FormatUint(uint64(date.ID), 10) + "-" + strconv.FormatInt(date.UpdatedAt.UnixNano(), 36) + `"`
+}
+
+// ctag changes whenever a resource of the calendar is added, changed or
+// removed, which lets clients skip listing unchanged calendars.
+func ctag(dates []dbmodel.Date) string {
+	hash := sha256.New()
+	for i := range dates {
+		io.WriteString(hash, etag(&dates[i]))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func resourceUID(name string) string {
+	name = strings.TrimSuffix(name, ".ics")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		return unescaped
+	}
+	return name
+}
+
+func principalHref(user *dbmodel.User) string {
+	return davRoot + "principals/" + url.PathEscape(user.Username) + "/"
+}
+
+func homeHref(user *dbmodel.User) string {
+	return davRoot + "calendars/" + url.PathEscape(user.Username) + "/"
+}
+
+func calendarHref(user *dbmodel.User) string {
+	return homeHref(user) + calendarName + "/"
+}
+
+func eventHref(user *dbmodel.User, date *dbmodel.Date) string {
+	return calendarHref(user) + url.PathEscape(ical.DateUID(date)) + ".ics"
+}
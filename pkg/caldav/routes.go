@@ -0,0 +1,55 @@
+package caldav
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+/*
+caldav routes:
+GET /caldav/app-passwords - List the current user's app passwords
+POST /caldav/app-passwords - Create an app password
+DELETE /caldav/app-passwords/{id} - Revoke an app password
+
+dav routes (HTTP Basic with an app password):
+PROPFIND /dav/ - Discover the current user principal
+PROPFIND /dav/principals/{username}/ - Principal with its calendar home
+PROPFIND /dav/calendars/{username}/ - Calendar home
+PROPFIND /dav/calendars/{username}/default/ - Calendar and its resources
+REPORT /dav/calendars/{username}/default/ - calendar-query and calendar-multiget
+PROPFIND|GET|PUT|DELETE /dav/calendars/{username}/default/{uid}.ics - One date
+*/
+
+func init() {
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+}
+
+func Routes(config *config.Config) chi.Router {
+	CalDAVConfig := NewCalDAVConfig(config)
+	router := chi.NewRouter()
+	router.Get("/app-passwords", CalDAVConfig.GetAppPasswords)
+	router.Post("/app-passwords", CalDAVConfig.CreateAppPassword)
+	router.Delete("/app-passwords/{id}", CalDAVConfig.RevokeAppPassword)
+	return router
+}
+
+func DAVRoutes(config *config.Config) chi.Router {
+	DAVConfig := NewDAVConfig(config)
+	router := chi.NewRouter()
+	router.Use(middleware.StripSlashes)
+	router.Use(DAVConfig.BasicAuth)
+	router.Options("/*", DAVConfig.Options)
+	router.MethodFunc("PROPFIND", "/", DAVConfig.PropfindRoot)
+	router.MethodFunc("PROPFIND", "/principals/{username}", DAVConfig.PropfindPrincipal)
+	router.MethodFunc("PROPFIND", "/calendars/{username}", DAVConfig.PropfindHome)
+	router.MethodFunc("PROPFIND", "/calendars/{username}/"+calendarName, DAVConfig.PropfindCalendar)
+	router.MethodFunc("REPORT", "/calendars/{username}/"+calendarName, DAVConfig.Report)
+	router.MethodFunc("PROPFIND", "/calendars/{username}/"+calendarName+"/{name}", DAVConfig.PropfindEvent)
+	router.Get("/calendars/{username}/"+calendarName+"/{name}", DAVConfig.GetEvent)
+	router.Put("/calendars/{username}/"+calendarName+"/{name}", DAVConfig.PutEvent)
+	router.Delete("/calendars/{username}/"+calendarName+"/{name}", DAVConfig.DeleteEvent)
+	return router
+}
@@ -0,0 +1,183 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	nsDAV         = "DAV:"
+	nsCalDAV      = "urn:ietf:params:xml:ns:caldav"
+	nsCalServer   = "http://calendarserver.org/ns/"
+	timeRangeForm = "20060102T150405Z"
+)
+
+var prefixes = map[string]string{
+	nsDAV:       "d",
+	nsCalDAV:    "c",
+	nsCalServer: "cs",
+}
+
+// davRequest is the subset of PROPFIND and REPORT bodies the server
+// understands: which properties are wanted and, for reports, which
+// resources.
+type davRequest struct {
+	Report    string
+	AllProp   bool
+	Props     []xml.Name
+	Hrefs     []string
+	RangeFrom time.Time
+	RangeTo   time.Time
+}
+
+func parseDAVRequest(body io.Reader) (*davRequest, error) {
+	request := &davRequest{}
+	decoder := xml.NewDecoder(body)
+	var path []xml.Name
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if len(path) == 0 {
+				request.Report = element.Name.Local
+			}
+			parent := xml.Name{}
+			if len(path) > 0 {
+				parent = path[len(path)-1]
+			}
+			switch {
+			case parent.Space == nsDAV && parent.Local == "prop":
+				request.Props = append(request.Props, element.Name)
+			case element.Name.Space == nsDAV && element.Name.Local == "allprop":
+				request.AllProp = true
+			case element.Name.Space == nsCalDAV && element.Name.Local == "time-range":
+				for _, attr := range element.Attr {
+					value, err := time.Parse(timeRangeForm, attr.Value)
+					if err != nil {
+						continue
+					}
+					if attr.Name.Local == "start" {
+						request.RangeFrom = value
+					} else if attr.Name.Local == "end" {
+						request.RangeTo = value
+					}
+				}
+			case element.Name.Space == nsDAV && element.Name.Local == "href":
+				var href string
+				if err := decoder.DecodeElement(&href, &element); err != nil {
+					return nil, err
+				}
+				request.Hrefs = append(request.Hrefs, strings.TrimSpace(href))
+				continue
+			}
+			path = append(path, element.Name)
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+	if request.Report == "" || (len(request.Props) == 0 && request.Report == "propfind") {
+		request.AllProp = true
+	}
+	return request, nil
+}
+
+// davResource holds every property the server can report for one href,
+// as raw inner XML keyed by namespace and local name.
+type davResource struct {
+	href  string
+	props map[xml.Name]string
+}
+
+func newResource(href string) *davResource {
+	return &davResource{href: href, props: make(map[xml.Name]string)}
+}
+
+func (resource *davResource) set(space string, local string, innerXML string) {
+	resource.props[xml.Name{Space: space, Local: local}] = innerXML
+}
+
+type multiStatus struct {
+	builder strings.Builder
+}
+
+func newMultiStatus() *multiStatus {
+	status := &multiStatus{}
+	status.builder.WriteString(xml.Header)
+	status.builder.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	return status
+}
+
+func (status *multiStatus) add(resource *davResource, request *davRequest, withCalendarData bool) {
+	status.builder.WriteString("<d:response><d:href>" + escape(resource.href) + "</d:href>")
+	var found, missing strings.Builder
+	if request.AllProp {
+		for name, value := range resource.props {
+			if name.Space == nsCalDAV && name.Local == "calendar-data" && !withCalendarData {
+				continue
+			}
+			found.WriteString(element(name, value))
+		}
+	} else {
+		for _, name := range request.Props {
+			if value, ok := resource.props[name]; ok {
+				found.WriteString(element(name, value))
+			} else {
+				missing.WriteString(element(name, ""))
+			}
+		}
+	}
+	if found.Len() > 0 {
+		status.builder.WriteString("<d:propstat><d:prop>" + found.String() + "</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>")
+	}
+	if missing.Len() > 0 {
+		status.builder.WriteString("<d:propstat><d:prop>" + missing.String() + "</d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>")
+	}
+	status.builder.WriteString("</d:response>")
+}
+
+func (status *multiStatus) addStatus(href string, code int) {
+	status.builder.WriteString(fmt.Sprintf("<d:response><d:href>%s</d:href><d:status>HTTP/1.1 %d %s</d:status></d:response>",
+		escape(href), code, http.StatusText(code)))
+}
+
+func (status *multiStatus) write(w http.ResponseWriter) {
+	status.builder.WriteString("</d:multistatus>")
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, status.builder.String())
+}
+
+func element(name xml.Name, innerXML string) string {
+	prefix, known := prefixes[name.Space]
+	open := prefix + ":" + name.Local
+	if !known {
+		open = "x:" + name.Local + ` xmlns:x="` + escape(name.Space) + `"`
+		prefix = "x"
+	}
+	if innerXML == "" {
+		return "<" + open + "/>"
+	}
+	return "<" + open + ">" + innerXML + "</" + prefix + ":" + name.Local + ">"
+}
+
+func escape(value string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(value))
+	return builder.String()
+}
+
+func hrefElement(href string) string {
+	return "<d:href>" + escape(href) + "</d:href>"
+}
@@ -0,0 +1,346 @@
+package changes
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	pageSize     = 500
+	tokenVersion = "v1:"
+)
+
+var errInvalidToken = errors.New("invalid sync token")
+
+type ChangesConfig struct {
+	*config.Config
+}
+
+func NewChangesConfig(cfg *config.Config) *ChangesConfig {
+	return &ChangesConfig{Config: cfg}
+}
+
+// visibility is what a caller may see: its own resources, the groups it
+// belongs to and the resources of their members.
+type visibility struct {
+	userID  uint
+	users   map[uint]bool
+	userIDs []uint
+	groups  []dbmodel.Group
+}
+
+func (visible *visibility) hasGroup(groupID uint) bool {
+	for _, group := range visible.groups {
+		if group.ID == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// @Summary		Get changes since a sync token
+// @Description	Without since, returns a full snapshot of the dates, availabilities and groups visible to the caller. With since, returns only what was created, updated or deleted after that token. Every response carries the token to send next time; when has_more is true, call again right away. A response with full=true replaces the local copy; it is also sent after membership changes. 410 means the token is no longer valid and the client must sync again without since.
+// @Tags		sync
+// @Produce		json
+// @Param		since	query	string	false	"Sync token from a previous response"
+// @Success		200	{object}	models.SyncResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	410 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/sync/ [get]
+func (config *ChangesConfig) GetChanges(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	visible, err := config.visibleTo(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		config.renderSnapshot(w, r, visible)
+		return
+	}
+	sequence, err := decodeToken(since)
+	if err != nil {
+		http.Error(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// The time of the change a token points at bounds the occurrences of
+	// recurring dates, which are not in the change log themselves.
+	var sinceTime time.Time
+	if sequence > 0 {
+		sinceChange, err := config.ChangeRepository.FindByID(sequence)
+		if err != nil {
+			http.Error(w, "Sync token expired, sync again without since", http.StatusGone)
+			return
+		}
+		sinceTime = sinceChange.CreatedAt
+	}
+
+	changes, err := config.ChangeRepository.FindAfter(sequence, pageSize+1)
+	if err != nil {
+		http.Error(w, "Failed to retrieve changes", http.StatusInternalServerError)
+		return
+	}
+	syncResponse := newSyncResponse()
+	if len(changes) > pageSize {
+		changes = changes[:pageSize]
+		syncResponse.HasMore = true
+	}
+	for _, change := range changes {
+		// Joining or leaving a group changes which resources are visible
+		// at all, which a list of changes cannot express.
+		if change.Resource == events.ResourceMembership && (change.UserID == user.ID || visible.hasGroup(change.GroupID)) {
+			config.renderSnapshot(w, r, visible)
+			return
+		}
+	}
+	config.collectChanges(syncResponse, visible, changes)
+	if err := config.collectOccurrences(syncResponse, visible, sinceTime); err != nil {
+		http.Error(w, "Failed to retrieve changes", http.StatusInternalServerError)
+		return
+	}
+	if len(changes) > 0 {
+		sequence = changes[len(changes)-1].ID
+	}
+	syncResponse.Token = encodeToken(sequence)
+	render.JSON(w, r, syncResponse)
+}
+
+func (config *ChangesConfig) renderSnapshot(w http.ResponseWriter, r *http.Request, visible *visibility) {
+	// The token is read first: a change made while the snapshot is built
+	// is then sent again next time rather than lost.
+	latest, err := config.ChangeRepository.FindLatestID()
+	if err != nil {
+		http.Error(w, "Failed to retrieve changes", http.StatusInternalServerError)
+		return
+	}
+	syncResponse := newSyncResponse()
+	syncResponse.Full = true
+	syncResponse.Token = encodeToken(latest)
+	for _, userID := range visible.userIDs {
+		dates, err := config.DateRepository.FindByUserID(userID)
+		if err != nil {
+			http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+			return
+		}
+		for _, date := range dates {
+			syncResponse.Dates.Created = append(syncResponse.Dates.Created, toSyncDate(&date, visible.userID))
+		}
+		availabilities, err := config.AvailabilityRepository.FindByUserID(userID)
+		if err != nil {
+			http.Error(w, "Failed to retrieve availabilities", http.StatusInternalServerError)
+			return
+		}
+		for _, availability := range availabilities {
+			syncResponse.Availabilities.Created = append(syncResponse.Availabilities.Created, toSyncAvailability(&availability))
+		}
+	}
+	for _, group := range visible.groups {
+		syncResponse.Groups.Created = append(syncResponse.Groups.Created, toSyncGroup(&group))
+	}
+	render.JSON(w, r, syncResponse)
+}
+
+// collectChanges reports the current state of every resource in the
+// changes. A resource created and then updated in the same window is
+// reported once, as created.
+func (config *ChangesConfig) collectChanges(syncResponse *models.SyncResponse, visible *visibility, changes []dbmodel.Change) {
+	type key struct {
+		resource string
+		id       uint
+	}
+	var order []key
+	created := make(map[key]bool)
+	owners := make(map[key]dbmodel.Change)
+	for _, change := range changes {
+		k := key{change.Resource, change.ResourceID}
+		if _, seen := owners[k]; !seen {
+			order = append(order, k)
+		}
+		owners[k] = change
+		if change.Action == events.ActionCreated {
+			created[k] = true
+		}
+	}
+
+	for _, k := range order {
+		change := owners[k]
+		switch k.resource {
+		case events.ResourceDate:
+			date, err := config.DateRepository.FindByIDWithDeleted(k.id)
+			if err != nil || date.DeletedAt.Valid {
+				if visible.users[change.UserID] {
+					syncResponse.Dates.Deleted = append(syncResponse.Dates.Deleted, k.id)
+				}
+				continue
+			}
+			if !visible.users[date.UserID] {
+				continue
+			}
+			if created[k] {
+				syncResponse.Dates.Created = append(syncResponse.Dates.Created, toSyncDate(date, visible.userID))
+			} else {
+				syncResponse.Dates.Updated = append(syncResponse.Dates.Updated, toSyncDate(date, visible.userID))
+			}
+		case events.ResourceAvailability:
+			availability, err := config.AvailabilityRepository.FindByIDWithDeleted(k.id)
+			if err != nil || availability.DeletedAt.Valid {
+				if visible.users[change.UserID] {
+					syncResponse.Availabilities.Deleted = append(syncResponse.Availabilities.Deleted, k.id)
+				}
+				continue
+			}
+			if !visible.users[availability.UserID] {
+				continue
+			}
+			if created[k] {
+				syncResponse.Availabilities.Created = append(syncResponse.Availabilities.Created, toSyncAvailability(availability))
+			} else {
+				syncResponse.Availabilities.Updated = append(syncResponse.Availabilities.Updated, toSyncAvailability(availability))
+			}
+		case events.ResourceGroup:
+			group, err := config.GroupRepository.FindByIDWithDeleted(k.id)
+			if err != nil || group.DeletedAt.Valid {
+				if change.UserID == visible.userID || config.wasMember(visible.userID, k.id) {
+					syncResponse.Groups.Deleted = append(syncResponse.Groups.Deleted, k.id)
+				}
+				continue
+			}
+			if !visible.hasGroup(group.ID) {
+				continue
+			}
+			if created[k] {
+				syncResponse.Groups.Created = append(syncResponse.Groups.Created, toSyncGroup(group))
+			} else {
+				syncResponse.Groups.Updated = append(syncResponse.Groups.Updated, toSyncGroup(group))
+			}
+		}
+	}
+}
+
+// collectOccurrences adds the materialised occurrences of recurring dates
+// that changed since the token, using their UpdatedAt and DeletedAt.
+func (config *ChangesConfig) collectOccurrences(syncResponse *models.SyncResponse, visible *visibility, since time.Time) error {
+	occurrences, err := config.DateRepository.FindOccurrencesChangedSince(visible.userIDs, since)
+	if err != nil {
+		return err
+	}
+	for _, occurrence := range occurrences {
+		switch {
+		case occurrence.DeletedAt.Valid:
+			syncResponse.Dates.Deleted = append(syncResponse.Dates.Deleted, occurrence.ID)
+		case occurrence.CreatedAt.After(since):
+			syncResponse.Dates.Created = append(syncResponse.Dates.Created, toSyncDate(&occurrence, visible.userID))
+		default:
+			syncResponse.Dates.Updated = append(syncResponse.Dates.Updated, toSyncDate(&occurrence, visible.userID))
+		}
+	}
+	return nil
+}
+
+func (config *ChangesConfig) visibleTo(userID uint) (*visibility, error) {
+	groups, err := config.GroupRepository.FindByMemberID(userID)
+	if err != nil {
+		return nil, err
+	}
+	visible := &visibility{userID: userID, users: map[uint]bool{userID: true}, userIDs: []uint{userID}, groups: groups}
+	// Guests only see a group's free/busy time, not its members' dates
+	peerIDs, err := config.GroupRepository.FindPeerIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, peerID := range peerIDs {
+		visible.users[peerID] = true
+		visible.userIDs = append(visible.userIDs, peerID)
+	}
+	return visible, nil
+}
+
+func (config *ChangesConfig) wasMember(userID uint, groupID uint) bool {
+	_, err := config.UserGroupRepository.FindByUserIDAndGroupID(userID, groupID)
+	return err == nil
+}
+
+func newSyncResponse() *models.SyncResponse {
+	return &models.SyncResponse{
+		Dates:          models.DateChanges{Created: []models.SyncDate{}, Updated: []models.SyncDate{}, Deleted: []uint{}},
+		Availabilities: models.AvailabilityChanges{Created: []models.SyncAvailability{}, Updated: []models.SyncAvailability{}, Deleted: []uint{}},
+		Groups:         models.GroupChanges{Created: []models.SyncGroup{}, Updated: []models.SyncGroup{}, Deleted: []uint{}},
+	}
+}
+
+// toSyncDate shows other users' private dates as busy time.
+func toSyncDate(date *dbmodel.Date, viewerID uint) models.SyncDate {
+	syncDate := models.SyncDate{
+		DateResponse: models.DateResponse{
+			ID:             date.ID,
+			Title:          date.Title,
+			Body:           date.Body,
+			DateBegin:      date.BeginTime,
+			DateEnd:        date.EndTime,
+			UserID:         date.UserID,
+			Private:        date.Private,
+			RecurrenceID:   date.RecurrenceID,
+			ColorID:        date.ColorID,
+			SubscriptionID: date.SubscriptionID,
+		},
+		UpdatedAt: date.UpdatedAt,
+	}
+	if date.Private && date.UserID != viewerID {
+		syncDate.Title, syncDate.Body = "Busy", ""
+	}
+	return syncDate
+}
+
+func toSyncAvailability(availability *dbmodel.Availability) models.SyncAvailability {
+	return models.SyncAvailability{
+		AvailabilityResponse: models.AvailabilityResponse{
+			ID:        availability.ID,
+			DateBegin: availability.BeginTime,
+			DateEnd:   availability.EndTime,
+			UserID:    availability.UserID,
+		},
+		UpdatedAt: availability.UpdatedAt,
+	}
+}
+
+func toSyncGroup(group *dbmodel.Group) models.SyncGroup {
+	return models.SyncGroup{
+		GroupResponse: models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID, ParentID: group.ParentID},
+		UpdatedAt:     group.UpdatedAt,
+	}
+}
+
+// Tokens are opaque to clients; the version prefix leaves room to change
+// what they encode.
+func encodeToken(sequence uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(tokenVersion + strconv.FormatUint(uint64(sequence), 10)))
+}
+
+func decodeToken(token string) (uint, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(decoded), tokenVersion) {
+		return 0, errInvalidToken
+	}
+	sequence, err := strconv.ParseUint(strings.TrimPrefix(string(decoded), tokenVersion), 10, 64)
+	if err != nil {
+		return 0, errInvalidToken
+	}
+	return uint(sequence), nil
+}
@@ -0,0 +1,223 @@
+package changes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+)
+
+// Field values are compared in a canonical form: strings, booleans, uints
+// and times as UTC RFC 3339 strings, so that a value sent by a client and
+// the same value read from the database are equal.
+const (
+	kindText = iota
+	kindTime
+	kindFlag
+	kindID
+)
+
+type field struct {
+	name string
+	kind int
+}
+
+var dateFields = []field{
+	{"title", kindText},
+	{"body", kindText},
+	{"date_begin", kindTime},
+	{"date_end", kindTime},
+	{"private", kindFlag},
+	{"color_id", kindID},
+}
+
+var availabilityFields = []field{
+	{"date_begin", kindTime},
+	{"date_end", kindTime},
+}
+
+// record is a date or availability seen through the fields a client may
+// write during replay.
+type record interface {
+	values() map[string]interface{}
+	apply(values map[string]interface{})
+	validate() error
+	id() uint
+	ownerID() uint
+	version() time.Time
+	readOnly() bool
+	event(action string) events.Event
+}
+
+type dateRecord struct {
+	date *dbmodel.Date
+}
+
+func (record *dateRecord) values() map[string]interface{} {
+	return map[string]interface{}{
+		"title":      record.date.Title,
+		"body":       record.date.Body,
+		"date_begin": formatTime(record.date.BeginTime),
+		"date_end":   formatTime(record.date.EndTime),
+		"private":    record.date.Private,
+		"color_id":   record.date.ColorID,
+	}
+}
+
+func (record *dateRecord) apply(values map[string]interface{}) {
+	for name, value := range values {
+		switch name {
+		case "title":
+			record.date.Title = value.(string)
+		case "body":
+			record.date.Body = value.(string)
+		case "date_begin":
+			record.date.BeginTime = parseTime(value)
+		case "date_end":
+			record.date.EndTime = parseTime(value)
+		case "private":
+			record.date.Private = value.(bool)
+		case "color_id":
+			record.date.ColorID = value.(uint)
+		}
+	}
+}
+
+func (record *dateRecord) validate() error {
+	if record.date.Title == "" {
+		return errors.New("title must not be null")
+	}
+	return validateRange(record.date.BeginTime, record.date.EndTime)
+}
+
+func (record *dateRecord) id() uint           { return record.date.ID }
+func (record *dateRecord) ownerID() uint      { return record.date.UserID }
+func (record *dateRecord) version() time.Time { return record.date.UpdatedAt }
+func (record *dateRecord) readOnly() bool     { return record.date.SubscriptionID != 0 }
+
+func (record *dateRecord) event(action string) events.Event {
+	return events.DateEvent(action, record.date)
+}
+
+type availabilityRecord struct {
+	availability *dbmodel.Availability
+}
+
+func (record *availabilityRecord) values() map[string]interface{} {
+	return map[string]interface{}{
+		"date_begin": formatTime(record.availability.BeginTime),
+		"date_end":   formatTime(record.availability.EndTime),
+	}
+}
+
+func (record *availabilityRecord) apply(values map[string]interface{}) {
+	for name, value := range values {
+		switch name {
+		case "date_begin":
+			record.availability.BeginTime = parseTime(value)
+		case "date_end":
+			record.availability.EndTime = parseTime(value)
+		}
+	}
+}
+
+func (record *availabilityRecord) validate() error {
+	return validateRange(record.availability.BeginTime, record.availability.EndTime)
+}
+
+func (record *availabilityRecord) id() uint           { return record.availability.ID }
+func (record *availabilityRecord) ownerID() uint      { return record.availability.UserID }
+func (record *availabilityRecord) version() time.Time { return record.availability.UpdatedAt }
+func (record *availabilityRecord) readOnly() bool     { return false }
+
+func (record *availabilityRecord) event(action string) events.Event {
+	return events.AvailabilityEvent(action, record.availability)
+}
+
+// normalize checks the fields a client sent against the resource and
+// converts them to their canonical form.
+func normalize(fields []field, raw map[string]interface{}) (map[string]interface{}, error) {
+	kinds := make(map[string]int)
+	for _, field := range fields {
+		kinds[field.name] = field.kind
+	}
+	values := make(map[string]interface{})
+	for name, value := range raw {
+		kind, known := kinds[name]
+		if !known {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+		var ok bool
+		switch kind {
+		case kindText:
+			values[name], ok = value.(string)
+		case kindFlag:
+			values[name], ok = value.(bool)
+		case kindTime:
+			var text string
+			if text, ok = value.(string); ok {
+				parsed, err := time.Parse(time.RFC3339Nano, text)
+				ok = err == nil
+				values[name] = formatTime(parsed)
+			}
+		case kindID:
+			var number float64
+			if number, ok = value.(float64); ok {
+				ok = number >= 0 && number == math.Trunc(number)
+				values[name] = uint(number)
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s", name)
+		}
+	}
+	return values, nil
+}
+
+// merge performs a three-way merge of the client's changes onto the server
+// values. It returns the fields to write and the fields both sides changed
+// differently. A field missing from base counts as changed on both sides
+// unless the values already agree.
+func merge(server map[string]interface{}, base map[string]interface{}, client map[string]interface{}) (map[string]interface{}, []string) {
+	merged := make(map[string]interface{})
+	var conflicts []string
+	for _, name := range sortedKeys(client) {
+		value := client[name]
+		if value == server[name] {
+			continue
+		}
+		baseValue, known := base[name]
+		switch {
+		case known && server[name] == baseValue:
+			merged[name] = value
+		case known && value == baseValue:
+			// Only the server changed this field: keep its value.
+		default:
+			conflicts = append(conflicts, name)
+		}
+	}
+	return merged, conflicts
+}
+
+func formatTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(value interface{}) time.Time {
+	parsed, _ := time.Parse(time.RFC3339Nano, value.(string))
+	return parsed
+}
+
+func validateRange(begin time.Time, end time.Time) error {
+	if begin.IsZero() {
+		return errors.New("date_begin must not be null")
+	} else if end.IsZero() {
+		return errors.New("date_end must not be null")
+	} else if end.Before(begin) {
+		return errors.New("date_end must not be before date_begin")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package changes
+
+import (
+	"log"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+)
+
+// Record appends every bus event to the change log read by the sync
+// endpoint. It must be called once, before the server handles requests.
+func Record(cfg *config.Config) {
+	cfg.Events.Subscribe(func(event events.Event) {
+		change := &dbmodel.Change{
+			Resource:   event.Resource,
+			ResourceID: event.ResourceID,
+			Action:     event.Action,
+			UserID:     event.UserID,
+			GroupID:    event.GroupID,
+			CreatedAt:  event.OccurredAt,
+		}
+		if _, err := cfg.ChangeRepository.Create(change); err != nil {
+			log.Printf("Failed to record %s of %s %d: %v", event.Action, event.Resource, event.ResourceID, err)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package changes
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/ical"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/render"
+)
+
+// @Summary		Replay offline writes
+// @Description	Apply a batch of date and availability writes a client queued while offline, in order. Each update or delete carries the version (updated_at) the client last saw. Writes whose version still matches are applied; the others are resolved with the chosen strategy: server-wins reports a conflict, client-wins overwrites the server, merge applies the fields only the client changed (using base) and reports a conflict for fields both sides changed. Mutations are independent: one failing does not undo the others.
+// @Tags		sync
+// @Accept		json
+// @Produce		json
+// @Param		request	body	models.ReplayRequest	true	"Queued mutations"
+// @Success		200	{object}	models.ReplayResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Security 	BearerAuth
+// @Router		/sync/replay [post]
+func (config *ChangesConfig) Replay(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	req := &models.ReplayRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	replayResponse := &models.ReplayResponse{Results: make([]models.MutationResult, 0, len(req.Mutations))}
+	for _, mutation := range req.Mutations {
+		strategy := mutation.Strategy
+		if strategy == "" {
+			strategy = req.Strategy
+		}
+		if strategy == "" {
+			strategy = models.StrategyServerWins
+		}
+		replayResponse.Results = append(replayResponse.Results, config.replayMutation(user, mutation, strategy))
+	}
+	render.JSON(w, r, replayResponse)
+}
+
+func (config *ChangesConfig) replayMutation(user *dbmodel.User, mutation models.Mutation, strategy string) models.MutationResult {
+	result := models.MutationResult{ClientID: mutation.ClientID, ID: mutation.ID}
+	reject := func(message string) models.MutationResult {
+		result.Status, result.Error = models.MutationRejected, message
+		return result
+	}
+	fields := dateFields
+	if mutation.Resource == events.ResourceAvailability {
+		fields = availabilityFields
+	}
+	client, err := normalize(fields, mutation.Data)
+	if err != nil {
+		return reject(err.Error())
+	}
+	base, err := normalize(fields, mutation.Base)
+	if err != nil {
+		return reject("base: " + err.Error())
+	}
+
+	if mutation.Action == models.MutationCreate {
+		current := config.newRecord(mutation.Resource, user.ID)
+		if usable, err := config.usableColor(user, client, nil); err != nil || !usable {
+			return reject("color not found")
+		}
+		current.apply(client)
+		if err := current.validate(); err != nil {
+			return reject(err.Error())
+		}
+		if err := config.createRecord(current); err != nil {
+			return reject("failed to create " + mutation.Resource)
+		}
+		config.Events.Publish(current.event(events.ActionCreated))
+		return applied(result, models.MutationApplied, current)
+	}
+
+	current, deleted, err := config.findRecord(mutation.Resource, mutation.ID)
+	if err != nil || current.ownerID() != user.ID {
+		return reject(mutation.Resource + " not found")
+	}
+	if current.readOnly() {
+		return reject(mutation.Resource + " is read-only: it is mirrored from a calendar subscription")
+	}
+	if deleted {
+		if mutation.Action == models.MutationDelete {
+			result.Status = models.MutationApplied
+			return result
+		}
+		result.Status, result.Client, result.Error = models.MutationConflict, client, "deleted on the server"
+		return result
+	}
+
+	server := current.values()
+	status := models.MutationApplied
+	if !current.version().Equal(*mutation.Version) {
+		status = models.MutationResolved
+		conflict := func(fields []string) models.MutationResult {
+			version := current.version()
+			result.Status, result.Version, result.Fields = models.MutationConflict, &version, fields
+			result.Server, result.Client = server, client
+			return result
+		}
+		merged, conflicts := merge(server, base, client)
+		switch {
+		case strategy == models.StrategyClientWins:
+		case mutation.Action == models.MutationDelete:
+			return conflict(nil)
+		case strategy == models.StrategyMerge && len(conflicts) == 0:
+			client = merged
+		default:
+			return conflict(conflicts)
+		}
+	}
+
+	if mutation.Action == models.MutationDelete {
+		err := config.deleteRecord(current)
+		if errors.Is(err, dbmodel.ErrModified) {
+			return config.raced(result, mutation, client)
+		}
+		if err != nil {
+			return reject(writeError(err, "failed to delete "+mutation.Resource))
+		}
+		config.Events.Publish(current.event(events.ActionDeleted))
+		result.Status = status
+		return result
+	}
+	if usable, err := config.usableColor(user, client, server); err != nil || !usable {
+		return reject("color not found")
+	}
+	current.apply(client)
+	if err := current.validate(); err != nil {
+		return reject(err.Error())
+	}
+	err = config.saveRecord(current)
+	if errors.Is(err, dbmodel.ErrModified) {
+		return config.raced(result, mutation, client)
+	}
+	if err != nil {
+		return reject(writeError(err, "failed to update "+mutation.Resource))
+	}
+	config.Events.Publish(current.event(events.ActionUpdated))
+	return applied(result, status, current)
+}
+
+// raced reports a conflict with a write that changed the record after its
+// version was checked: the record is only written at the version it was
+// read at.
+func (config *ChangesConfig) raced(result models.MutationResult, mutation models.Mutation, client map[string]interface{}) models.MutationResult {
+	current, deleted, err := config.findRecord(mutation.Resource, mutation.ID)
+	if err != nil || deleted {
+		result.Status, result.Client, result.Error = models.MutationConflict, client, "deleted on the server"
+		return result
+	}
+	version := current.version()
+	result.Status, result.Version = models.MutationConflict, &version
+	result.Server, result.Client = current.values(), client
+	return result
+}
+
+// usableColor reports whether the values a client sends may give their
+// color to a date: a shared color or one of the user's own, unless it is
+// the color the date already has on the server.
+func (config *ChangesConfig) usableColor(user *dbmodel.User, values map[string]interface{}, server map[string]interface{}) (bool, error) {
+	colorID, ok := values["color_id"]
+	if !ok || (server != nil && server["color_id"] == colorID) {
+		return true, nil
+	}
+	return authorization.CanUseColor(config.ColorRepository, user, colorID.(uint))
+}
+
+func applied(result models.MutationResult, status string, current record) models.MutationResult {
+	version := current.version()
+	result.Status, result.ID, result.Version = status, current.id(), &version
+	return result
+}
+
+func (config *ChangesConfig) newRecord(resource string, userID uint) record {
+	if resource == events.ResourceAvailability {
+		return &availabilityRecord{availability: &dbmodel.Availability{UserID: userID}}
+	}
+	return &dateRecord{date: &dbmodel.Date{UserID: userID}}
+}
+
+// findRecord also finds soft-deleted resources, reporting them as deleted.
+func (config *ChangesConfig) findRecord(resource string, id uint) (record, bool, error) {
+	if resource == events.ResourceAvailability {
+		availability, err := config.AvailabilityRepository.FindByIDWithDeleted(id)
+		if err != nil {
+			return nil, false, err
+		}
+		return &availabilityRecord{availability: availability}, availability.DeletedAt.Valid, nil
+	}
+	date, err := config.DateRepository.FindByIDWithDeleted(id)
+	if err != nil {
+		return nil, false, err
+	}
+	return &dateRecord{date: date}, date.DeletedAt.Valid, nil
+}
+
+func (config *ChangesConfig) createRecord(current record) error {
+	switch current := current.(type) {
+	case *availabilityRecord:
+		_, err := config.AvailabilityRepository.Create(current.availability)
+		return err
+	case *dateRecord:
+		_, err := config.DateRepository.CreateWithOccurrences(current.date, nil)
+		return err
+	}
+	return nil
+}
+
+func (config *ChangesConfig) saveRecord(current record) error {
+	switch current := current.(type) {
+	case *availabilityRecord:
+		return config.AvailabilityRepository.Save(current.availability)
+	case *dateRecord:
+		occurrences, err := ical.DateOccurrences(current.date)
+		if err != nil {
+			return err
+		}
+		return config.DateRepository.SaveWithOccurrences(current.date, occurrences)
+	}
+	return nil
+}
+
+func (config *ChangesConfig) deleteRecord(current record) error {
+	switch current := current.(type) {
+	case *availabilityRecord:
+		return config.AvailabilityRepository.Delete(current.availability)
+	case *dateRecord:
+		return config.DateRepository.DeleteWithOccurrences(current.date)
+	}
+	return nil
+}
+
+// writeError describes a failed write, telling apart the writes to one
+// occurrence of a recurring date, which are regenerated from it.
+func writeError(err error, message string) string {
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		return "date is an occurrence of a recurring date: change the recurring date instead"
+	}
+	return message
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,21 @@
+package changes
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+sync routes:
+GET /sync/?since={token} - Changes visible to the current user since a sync token
+POST /sync/replay - Replay writes queued by an offline client
+*/
+
+func Routes(config *config.Config) chi.Router {
+	ChangesConfig := NewChangesConfig(config)
+	router := chi.NewRouter()
+	router.Get("/", ChangesConfig.GetChanges)
+	router.Post("/replay", ChangesConfig.Replay)
+	return router
+}
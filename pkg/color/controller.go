@@ -6,6 +6,7 @@ import (
 
 	"yplanning/config"
 	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
 	"yplanning/pkg/models"
 
 	"github.com/go-chi/chi/v5"
@@ -21,14 +22,15 @@ func NewColorConfig(cfg *config.Config) *ColorConfig {
 }
 
 // @Summary Create a new color
-// @Description Create a new color with the provided hex code and name
+// @Description Create a new color with the provided hex code and name. It belongs to its creator, who alone may use it on dates; colors created by administrators are shared by everyone.
 // @Tags colors
 // @Accept json
 // @Produce json
 // @Param color body models.ColorRequest true "Color information"
 // @Success 200 {object} models.ColorResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /color/ [post]
 func (config *ColorConfig) CreateColor(w http.ResponseWriter, r *http.Request) {
 	colorRequest := &models.ColorRequest{}
@@ -40,6 +42,9 @@ func (config *ColorConfig) CreateColor(w http.ResponseWriter, r *http.Request) {
 		HexCode: colorRequest.HexCode,
 		Name:    colorRequest.Name,
 	}
+	if user := authorization.UserFromContext(r.Context()); !user.IsAdmin {
+		color.UserID = user.ID
+	}
 	createdColor, err := config.ColorRepository.Create(color)
 	if err != nil {
 		http.Error(w, "Failed to create color", http.StatusInternalServerError)
@@ -55,7 +60,8 @@ func (config *ColorConfig) CreateColor(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.ColorResponse
-// @Failure 500 {object} http.Error
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /color/colors [get]
 func (config *ColorConfig) GetAllColors(w http.ResponseWriter, r *http.Request) {
 	colors, err := config.ColorRepository.FindAll()
@@ -81,8 +87,9 @@ func (config *ColorConfig) GetAllColors(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Param id path int true "Color ID"
 // @Success 200 {object} models.ColorResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /color/{id} [get]
 func (config *ColorConfig) GetColorByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -110,9 +117,10 @@ func (config *ColorConfig) GetColorByID(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Param color body models.ColorRequest true "Color information"
 // @Success 200 {object} models.ColorResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
-// @Router /color/hex [post]
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /color/hexcode [get]
 func (config *ColorConfig) GetByHexCode(w http.ResponseWriter, r *http.Request) {
 	colorRequest := &models.ColorRequest{}
 	if err := render.Bind(r, colorRequest); err != nil {
@@ -133,15 +141,17 @@ func (config *ColorConfig) GetByHexCode(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Update a color by ID
-// @Description Update a color's hex code and name by its ID
+// @Description Update a color's hex code and name by its ID (administrators only)
 // @Tags colors
 // @Accept json
 // @Produce json
 // @Param id path int true "Color ID"
 // @Param color body models.ColorRequest true "Updated color information"
 // @Success 200 {object} models.ColorResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /color/{id} [put]
 func (config *ColorConfig) UpdateColor(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -167,17 +177,17 @@ func (config *ColorConfig) UpdateColor(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, colorResponse)
 }
 
-// @Summary Update a color by ID
-// @Description Update a color's hex code and name by its ID
+// @Summary Delete a color by ID
+// @Description Delete a color by its ID (administrators only)
 // @Tags colors
-// @Accept json
 // @Produce json
 // @Param id path int true "Color ID"
-// @Param color body models.ColorRequest true "Updated color information"
-// @Success 200 {object} models.ColorResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
-// @Router /color/{id} [put]
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /color/{id} [delete]
 func (config *ColorConfig) DeleteColor(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -2,6 +2,7 @@ package color
 
 import (
 	"yplanning/config"
+	"yplanning/pkg/authorization"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -12,8 +13,8 @@ POST /color/ - create a new color
 GET /color/colors - get all colors (for testing purposes only)
 GET /color/{id} - get a color by ID
 GET /color/hexcode/{hexcode} - get a color by hex code
-PUT /color/{id} - update a color by ID
-DELETE /color/{id} - delete a color by ID
+PUT /color/{id} - update a color by ID (administrators only)
+DELETE /color/{id} - delete a color by ID (administrators only)
 */
 
 func Routes(config *config.Config) chi.Router {
@@ -23,7 +24,7 @@ func Routes(config *config.Config) chi.Router {
 	router.Get("/colors", colorConfig.GetAllColors) // FOR TESTING PURPOSES ONLY
 	router.Get("/{id}", colorConfig.GetColorByID)
 	router.Get("/hexcode", colorConfig.GetByHexCode)
-	router.Put("/{id}", colorConfig.UpdateColor)
-	router.Delete("/{id}", colorConfig.DeleteColor)
+	router.With(authorization.RequireAdmin).Put("/{id}", colorConfig.UpdateColor)
+	router.With(authorization.RequireAdmin).Delete("/{id}", colorConfig.DeleteColor)
 	return router
 }
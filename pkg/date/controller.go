@@ -1,17 +1,29 @@
 package date
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"yplanning/config"
 	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/ical"
 	"yplanning/pkg/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
+const (
+	// occurrenceConflict answers changes to one occurrence of a recurring
+	// date, which is regenerated from the recurring date.
+	occurrenceConflict = "This date is an occurrence of a recurring date: change the recurring date instead"
+	// modifiedConflict answers changes that lost the race to another one.
+	modifiedConflict = "The date was changed by another request, try again"
+)
+
 type DateConfig struct {
 	*config.Config
 }
@@ -27,8 +39,10 @@ func NewDateConfig(cfg *config.Config) *DateConfig {
 // @Produce json
 // @Param date body models.DateRequest true "Date details"
 // @Success 200 {object} models.DateResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/ [post]
 func (config *DateConfig) CreateDate(w http.ResponseWriter, r *http.Request) {
 	var dateRequest models.DateRequest
@@ -36,6 +50,14 @@ func (config *DateConfig) CreateDate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	if !authorization.CanChangeUser(viewer, dateRequest.UserID) {
+		http.Error(w, "You can only create your own dates", http.StatusForbidden)
+		return
+	}
+	if !config.usableColor(w, viewer, dateRequest.ColorID) {
+		return
+	}
 	date := &dbmodel.Date{
 		Title:        dateRequest.Title,
 		Body:         dateRequest.Body,
@@ -46,32 +68,40 @@ func (config *DateConfig) CreateDate(w http.ResponseWriter, r *http.Request) {
 		RecurrenceID: dateRequest.RecurrenceID,
 		ColorID:      dateRequest.ColorID,
 	}
-	createdDate, err := config.DateRepository.Create(date)
+	createdDate, err := config.DateRepository.CreateWithOccurrences(date, nil)
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		http.Error(w, "recurrence_id must not point to a recurring date", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to create date", http.StatusInternalServerError)
 		return
 	}
+	config.Events.Publish(events.DateEvent(events.ActionCreated, createdDate))
 	dateResponse := &models.DateResponse{
-		ID:           createdDate.ID,
-		Title:        createdDate.Title,
-		Body:         createdDate.Body,
-		DateBegin:    createdDate.BeginTime,
-		DateEnd:      createdDate.EndTime,
-		UserID:       createdDate.UserID,
-		Private:      createdDate.Private,
-		RecurrenceID: createdDate.RecurrenceID,
-		ColorID:      createdDate.ColorID,
+		ID:             createdDate.ID,
+		Title:          createdDate.Title,
+		Body:           createdDate.Body,
+		DateBegin:      createdDate.BeginTime,
+		DateEnd:        createdDate.EndTime,
+		UserID:         createdDate.UserID,
+		Private:        createdDate.Private,
+		RecurrenceID:   createdDate.RecurrenceID,
+		ColorID:        createdDate.ColorID,
+		SubscriptionID: createdDate.SubscriptionID,
 	}
 	render.JSON(w, r, dateResponse)
 }
 
 // @Summary Get all dates
-// @Description Retrieve a list of all dates
+// @Description Retrieve a list of all dates (administrators only)
 // @Tags dates
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.DateResponse
-// @Failure 500 {object} http.Error
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/dates [get]
 func (config *DateConfig) GetAllDates(w http.ResponseWriter, r *http.Request) {
 	dates, err := config.DateRepository.FindAll()
@@ -82,15 +112,16 @@ func (config *DateConfig) GetAllDates(w http.ResponseWriter, r *http.Request) {
 	DateResponse := make([]models.DateResponse, 0)
 	for _, date := range dates {
 		DateResponse = append(DateResponse, models.DateResponse{
-			ID:           date.ID,
-			Title:        date.Title,
-			Body:         date.Body,
-			DateBegin:    date.BeginTime,
-			DateEnd:      date.EndTime,
-			UserID:       date.UserID,
-			Private:      date.Private,
-			RecurrenceID: date.RecurrenceID,
-			ColorID:      date.ColorID,
+			ID:             date.ID,
+			Title:          date.Title,
+			Body:           date.Body,
+			DateBegin:      date.BeginTime,
+			DateEnd:        date.EndTime,
+			UserID:         date.UserID,
+			Private:        date.Private,
+			RecurrenceID:   date.RecurrenceID,
+			ColorID:        date.ColorID,
+			SubscriptionID: date.SubscriptionID,
 		})
 	}
 	render.JSON(w, r, DateResponse)
@@ -103,8 +134,10 @@ func (config *DateConfig) GetAllDates(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Date ID"
 // @Success 200 {object} models.DateResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/{id} [get]
 func (config *DateConfig) GetDateByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -121,17 +154,24 @@ func (config *DateConfig) GetDateByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to retrieve date", http.StatusInternalServerError)
 		return
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, date.UserID); err != nil || !visible {
+		http.Error(w, "Date not found", http.StatusNotFound)
+		return
+	}
 	dateResponse := &models.DateResponse{
-		ID:           date.ID,
-		Title:        date.Title,
-		Body:         date.Body,
-		DateBegin:    date.BeginTime,
-		DateEnd:      date.EndTime,
-		UserID:       date.UserID,
-		Private:      date.Private,
-		RecurrenceID: date.RecurrenceID,
-		ColorID:      date.ColorID,
+		ID:             date.ID,
+		Title:          date.Title,
+		Body:           date.Body,
+		DateBegin:      date.BeginTime,
+		DateEnd:        date.EndTime,
+		UserID:         date.UserID,
+		Private:        date.Private,
+		RecurrenceID:   date.RecurrenceID,
+		ColorID:        date.ColorID,
+		SubscriptionID: date.SubscriptionID,
 	}
+	hidePrivate(viewer, dateResponse)
 	render.JSON(w, r, dateResponse)
 }
 
@@ -142,8 +182,10 @@ func (config *DateConfig) GetDateByID(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param userID path int true "User ID"
 // @Success 200 {array} models.DateResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/user/{userID} [get]
 func (config *DateConfig) GetDatesByUserID(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
@@ -155,6 +197,11 @@ func (config *DateConfig) GetDatesByUserID(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "user_id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, uint(userID)); err != nil || !visible {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 	dates, err := config.DateRepository.FindByUserID(uint(userID))
 	if err != nil {
 		http.Error(w, "Failed to retrieve date", http.StatusInternalServerError)
@@ -163,17 +210,21 @@ func (config *DateConfig) GetDatesByUserID(w http.ResponseWriter, r *http.Reques
 	dateResponse := make([]models.DateResponse, 0)
 	for _, date := range dates {
 		dateResponse = append(dateResponse, models.DateResponse{
-			ID:           date.ID,
-			Title:        date.Title,
-			Body:         date.Body,
-			DateBegin:    date.BeginTime,
-			DateEnd:      date.EndTime,
-			UserID:       date.UserID,
-			Private:      date.Private,
-			RecurrenceID: date.RecurrenceID,
-			ColorID:      date.ColorID,
+			ID:             date.ID,
+			Title:          date.Title,
+			Body:           date.Body,
+			DateBegin:      date.BeginTime,
+			DateEnd:        date.EndTime,
+			UserID:         date.UserID,
+			Private:        date.Private,
+			RecurrenceID:   date.RecurrenceID,
+			ColorID:        date.ColorID,
+			SubscriptionID: date.SubscriptionID,
 		})
 	}
+	for i := range dateResponse {
+		hidePrivate(viewer, &dateResponse[i])
+	}
 	render.JSON(w, r, dateResponse)
 }
 
@@ -184,8 +235,10 @@ func (config *DateConfig) GetDatesByUserID(w http.ResponseWriter, r *http.Reques
 // @Produce json
 // @Param recurrenceID path int true "Recurrence ID"
 // @Success 200 {array} models.DateResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/recurrence/{recurrenceID} [get]
 func (config *DateConfig) GetDatesByRecurrenceID(w http.ResponseWriter, r *http.Request) {
 	recurrenceID, err := strconv.Atoi(chi.URLParam(r, "recurrenceID"))
@@ -202,17 +255,24 @@ func (config *DateConfig) GetDatesByRecurrenceID(w http.ResponseWriter, r *http.
 		http.Error(w, "Failed to retrieve date", http.StatusInternalServerError)
 		return
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, date.UserID); err != nil || !visible {
+		http.Error(w, "Date not found", http.StatusNotFound)
+		return
+	}
 	dateResponse := &models.DateResponse{
-		ID:           date.ID,
-		Title:        date.Title,
-		Body:         date.Body,
-		DateBegin:    date.BeginTime,
-		DateEnd:      date.EndTime,
-		UserID:       date.UserID,
-		Private:      date.Private,
-		RecurrenceID: date.RecurrenceID,
-		ColorID:      date.ColorID,
+		ID:             date.ID,
+		Title:          date.Title,
+		Body:           date.Body,
+		DateBegin:      date.BeginTime,
+		DateEnd:        date.EndTime,
+		UserID:         date.UserID,
+		Private:        date.Private,
+		RecurrenceID:   date.RecurrenceID,
+		ColorID:        date.ColorID,
+		SubscriptionID: date.SubscriptionID,
 	}
+	hidePrivate(viewer, dateResponse)
 	render.JSON(w, r, dateResponse)
 }
 
@@ -221,12 +281,12 @@ func (config *DateConfig) GetDatesByRecurrenceID(w http.ResponseWriter, r *http.
 // @Tags dates
 // @Accept json
 // @Produce json
-// @Param start query string true "Start date in ISO format (e.g., 2024-01-01T00:00:00Z)"
-// @Param end query string true "End date in ISO format (e.g., 2024-01-31T23:59:59Z)"
-// @Param userID query int false "User ID to filter dates (optional)"
+// @Param range body models.AvailabilityRequest true "Start and end of the range (e.g., 2024-01-01T00:00:00Z) and user ID"
 // @Success 200 {array} models.DateResponse
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/range [get]
 func (config *DateConfig) GetDateByDayRange(w http.ResponseWriter, r *http.Request) {
 	var rangeRequest models.AvailabilityRequest
@@ -239,6 +299,11 @@ func (config *DateConfig) GetDateByDayRange(w http.ResponseWriter, r *http.Reque
 		DateEnd:   rangeRequest.DateEnd,
 		UserID:    rangeRequest.UserID,
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, date.UserID); err != nil || !visible {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 
 	dates, err := config.DateRepository.FindByDayRange(date.DateBegin, date.DateEnd, uint(date.UserID))
 	if err != nil {
@@ -248,30 +313,38 @@ func (config *DateConfig) GetDateByDayRange(w http.ResponseWriter, r *http.Reque
 	DateResponse := make([]models.DateResponse, 0)
 	for _, date := range dates {
 		DateResponse = append(DateResponse, models.DateResponse{
-			ID:           date.ID,
-			Title:        date.Title,
-			Body:         date.Body,
-			DateBegin:    date.BeginTime,
-			DateEnd:      date.EndTime,
-			UserID:       date.UserID,
-			Private:      date.Private,
-			RecurrenceID: date.RecurrenceID,
-			ColorID:      date.ColorID,
+			ID:             date.ID,
+			Title:          date.Title,
+			Body:           date.Body,
+			DateBegin:      date.BeginTime,
+			DateEnd:        date.EndTime,
+			UserID:         date.UserID,
+			Private:        date.Private,
+			RecurrenceID:   date.RecurrenceID,
+			ColorID:        date.ColorID,
+			SubscriptionID: date.SubscriptionID,
 		})
 	}
+	for i := range DateResponse {
+		hidePrivate(viewer, &DateResponse[i])
+	}
 	render.JSON(w, r, DateResponse)
 }
 
 // @Summary Update a date by ID
-// @Description Update the details of a date identified by its ID
+// @Description Update the details of a date identified by its ID. The occurrences of a recurring date are regenerated; single occurrences cannot be changed.
 // @Tags dates
 // @Accept json
 // @Produce json
 // @Param id path int true "Date ID"
 // @Param date body models.DateRequest true "Updated date details"
 // @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/{id} [put]
 func (config *DateConfig) UpdateDate(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -283,38 +356,66 @@ func (config *DateConfig) UpdateDate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	viewer := authorization.UserFromContext(r.Context())
+	date, ok := config.writableDate(w, viewer, uint(id))
+	if !ok {
+		return
+	}
 	var dateRequest models.DateRequest
 	if err := render.Bind(r, &dateRequest); err != nil {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
-	date := &dbmodel.Date{
-		Title:        dateRequest.Title,
-		Body:         dateRequest.Body,
-		BeginTime:    dateRequest.DateBegin,
-		EndTime:      dateRequest.DateEnd,
-		UserID:       dateRequest.UserID,
-		Private:      dateRequest.Private,
-		RecurrenceID: dateRequest.RecurrenceID,
-		ColorID:      dateRequest.ColorID,
+	if !authorization.CanChangeUser(viewer, dateRequest.UserID) {
+		http.Error(w, "You cannot give a date to another user", http.StatusForbidden)
+		return
+	}
+	if dateRequest.ColorID != date.ColorID && !config.usableColor(w, viewer, dateRequest.ColorID) {
+		return
+	}
+	date.Title = dateRequest.Title
+	date.Body = dateRequest.Body
+	date.BeginTime = dateRequest.DateBegin
+	date.EndTime = dateRequest.DateEnd
+	date.UserID = dateRequest.UserID
+	date.Private = dateRequest.Private
+	date.RecurrenceID = dateRequest.RecurrenceID
+	date.ColorID = dateRequest.ColorID
+	occurrences, err := ical.DateOccurrences(date)
+	if err != nil {
+		http.Error(w, "Invalid recurrence: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = config.DateRepository.SaveWithOccurrences(date, occurrences)
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		http.Error(w, occurrenceConflict, http.StatusConflict)
+		return
+	}
+	if errors.Is(err, dbmodel.ErrModified) {
+		http.Error(w, modifiedConflict, http.StatusConflict)
+		return
 	}
-	err = config.DateRepository.UpdateByID(uint(id), date)
 	if err != nil {
 		http.Error(w, "Failed to update date", http.StatusInternalServerError)
 		return
 	}
+	config.Events.Publish(events.DateEvent(events.ActionUpdated, date))
 	render.JSON(w, r, map[string]string{"message": "Date updated successfully"})
 }
 
 // @Summary Delete a date by ID
-// @Description Delete a date identified by its ID
+// @Description Delete a date identified by its ID, with the occurrences of a recurring date. Single occurrences cannot be deleted.
 // @Tags dates
 // @Accept json
 // @Produce json
 // @Param id path int true "Date ID"
 // @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} http.Error
-// @Failure 500 {object} http.Error
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
 // @Router /date/{id} [delete]
 func (config *DateConfig) DeleteDate(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
@@ -326,10 +427,70 @@ func (config *DateConfig) DeleteDate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "id must be >= 1", http.StatusBadRequest)
 		return
 	}
-	err = config.DateRepository.DeleteByID(uint(id))
+	date, ok := config.writableDate(w, authorization.UserFromContext(r.Context()), uint(id))
+	if !ok {
+		return
+	}
+	err = config.DateRepository.DeleteWithOccurrences(date)
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		http.Error(w, occurrenceConflict, http.StatusConflict)
+		return
+	}
+	if errors.Is(err, dbmodel.ErrModified) {
+		http.Error(w, modifiedConflict, http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to delete date", http.StatusInternalServerError)
 		return
 	}
+	config.Events.Publish(events.DateEvent(events.ActionDeleted, date))
 	render.JSON(w, r, map[string]string{"message": "Date deleted successfully"})
 }
+
+// writableDate rejects changes to other users' dates and to dates mirrored
+// from a calendar subscription, which would be overwritten by the next sync
+// anyway.
+func (config *DateConfig) writableDate(w http.ResponseWriter, viewer *dbmodel.User, id uint) (*dbmodel.Date, bool) {
+	date, err := config.DateRepository.FindByID(id)
+	if err != nil {
+		http.Error(w, "Date not found", http.StatusNotFound)
+		return nil, false
+	}
+	if !authorization.CanChangeUser(viewer, date.UserID) {
+		if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, date.UserID); err != nil || !visible {
+			http.Error(w, "Date not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "You can only change your own dates", http.StatusForbidden)
+		}
+		return nil, false
+	}
+	if date.SubscriptionID != 0 {
+		http.Error(w, "Date is read-only: it is mirrored from a calendar subscription", http.StatusForbidden)
+		return nil, false
+	}
+	return date, true
+}
+
+// usableColor rejects the colors the viewer may not give to a date: the
+// other users' ones and those that do not exist.
+func (config *DateConfig) usableColor(w http.ResponseWriter, viewer *dbmodel.User, colorID uint) bool {
+	usable, err := authorization.CanUseColor(config.ColorRepository, viewer, colorID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve color", http.StatusInternalServerError)
+		return false
+	}
+	if !usable {
+		http.Error(w, "Color not found", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// hidePrivate hides the content of another user's private date, which only
+// shows as busy time.
+func hidePrivate(viewer *dbmodel.User, dateResponse *models.DateResponse) {
+	if dateResponse.Private && dateResponse.UserID != viewer.ID {
+		dateResponse.Title, dateResponse.Body = "Busy", ""
+	}
+}
@@ -0,0 +1,32 @@
+package date
+
+import (
+	"net/http"
+
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/ical"
+)
+
+// @Summary Export dates as an iCalendar file
+// @Description Download all of the current user's dates, private ones included, as an .ics file that ImportDates or any calendar application can read
+// @Tags dates
+// @Produce text/calendar
+// @Success 200 {string} string "iCalendar document"
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /date/export [get]
+func (config *DateConfig) ExportDates(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	dates, err := config.DateRepository.FindByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+		return
+	}
+	calendar := &ical.Calendar{Name: user.Username, Events: ical.FromDates(dates, nil)}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+user.Username+`.ics"`)
+	if err := ical.Write(w, calendar); err != nil {
+		http.Error(w, "Failed to write calendar", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,166 @@
+package date
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/ical"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportSize  = 10 << 20
+	importHorizon  = 2
+	cancelledEvent = "CANCELLED"
+)
+
+// @Summary Import dates from an iCalendar file
+// @Description Create or update the current user's dates from an .ics file sent as multipart (field "file") or as a raw text/calendar body. Events are matched by UID; with dry_run=true nothing is written and the report describes what would happen.
+// @Tags dates
+// @Accept multipart/form-data
+// @Accept text/calendar
+// @Produce json
+// @Param file formData file false "iCalendar file"
+// @Param dry_run query bool false "Only report what would be created, updated or skipped"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /date/import [post]
+func (config *DateConfig) ImportDates(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "dry_run must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	body, err := importBody(w, r)
+	if err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+	calendar, err := ical.Parse(body)
+	if err != nil {
+		http.Error(w, "Invalid iCalendar file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report := &models.ImportReport{
+		DryRun:  dryRun,
+		Created: make([]models.ImportItem, 0),
+		Updated: make([]models.ImportItem, 0),
+		Skipped: make([]models.ImportItem, 0),
+	}
+	for _, event := range calendar.Events {
+		config.importEvent(user.ID, event, dryRun, report)
+	}
+	render.JSON(w, r, report)
+}
+
+func importBody(w http.ResponseWriter, r *http.Request) (io.ReadCloser, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		return nil, err
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, errors.New("missing file field")
+	}
+	return file, nil
+}
+
+func (config *DateConfig) importEvent(userID uint, event ical.Event, dryRun bool, report *models.ImportReport) {
+	item := models.ImportItem{
+		UID:       event.UID,
+		Title:     event.Summary,
+		DateBegin: event.Start,
+		DateEnd:   event.End,
+	}
+	skip := func(reason string) {
+		item.Reason = reason
+		report.Skipped = append(report.Skipped, item)
+	}
+
+	switch {
+	case event.UID == "":
+		skip("missing UID")
+		return
+	case !event.RecurrenceID.IsZero():
+		skip("modified occurrences of a recurring event are not supported")
+		return
+	case event.Status == cancelledEvent:
+		skip("event is cancelled")
+		return
+	}
+
+	occurrences, err := event.Occurrences(event.Start.AddDate(importHorizon, 0, 0))
+	if err != nil {
+		skip(err.Error())
+		return
+	}
+	item.Occurrences = len(occurrences)
+	if item.Occurrences == 0 {
+		skip("every occurrence is excluded")
+		return
+	}
+
+	date := ical.ToDate(event, userID)
+	existing, err := config.DateRepository.FindByUIDAndUserID(event.UID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		skip("failed to look up date: " + err.Error())
+		return
+	}
+	if err == nil && existing.SubscriptionID != 0 {
+		skip("event comes from a calendar subscription")
+		return
+	}
+	if err == nil {
+		item.DateID = existing.ID
+		if existing.HasSameContent(date) {
+			skip("unchanged")
+			return
+		}
+		if !dryRun {
+			date.ID = existing.ID
+			date.CreatedAt = existing.CreatedAt
+			date.UpdatedAt = existing.UpdatedAt
+			date.ColorID = existing.ColorID
+			if err := config.DateRepository.SaveWithOccurrences(date, occurrences); err != nil {
+				skip("failed to update date: " + err.Error())
+				return
+			}
+			config.Events.Publish(events.DateEvent(events.ActionUpdated, date))
+		}
+		report.Updated = append(report.Updated, item)
+		return
+	}
+
+	if !dryRun {
+		created, err := config.DateRepository.CreateWithOccurrences(date, occurrences)
+		if err != nil {
+			skip("failed to create date: " + err.Error())
+			return
+		}
+		item.DateID = created.ID
+		config.Events.Publish(events.DateEvent(events.ActionCreated, created))
+	}
+	report.Created = append(report.Created, item)
+}
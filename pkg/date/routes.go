@@ -2,6 +2,7 @@ package date
 
 import (
 	"yplanning/config"
+	"yplanning/pkg/authorization"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -9,7 +10,9 @@ import (
 /*
 date routes:
 POST /dates - Create a new date
-GET /dates - Get all dates (for testing purposes only)
+POST /dates/import?dry_run={bool} - Import dates from an iCalendar file
+GET /dates/export - Export the current user's dates as an iCalendar file
+GET /dates - Get all dates (administrators only)
 GET /dates/{id} - Get a date by ID
 GET /dates/user/{userID} - Get dates by user ID
 GET /dates/recurrence/{recurrenceID} - Get dates by recurrence ID
@@ -22,7 +25,9 @@ func Routes(config *config.Config) chi.Router {
 	router := chi.NewRouter()
 	dateConfig := NewDateConfig(config)
 	router.Post("/", dateConfig.CreateDate)
-	router.Get("/dates", dateConfig.GetAllDates) //FOR TESTING PURPOSES ONLY
+	router.Post("/import", dateConfig.ImportDates)
+	router.Get("/export", dateConfig.ExportDates)
+	router.With(authorization.RequireAdmin).Get("/dates", dateConfig.GetAllDates)
 	router.Get("/{id}", dateConfig.GetDateByID)
 	router.Get("/user/{userID}", dateConfig.GetDatesByUserID)
 	router.Get("/recurrence/{recurrenceID}", dateConfig.GetDatesByRecurrenceID)
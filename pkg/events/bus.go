@@ -0,0 +1,114 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ResourceDate         = "date"
+	ResourceAvailability = "availability"
+	ResourceGroup        = "group"
+	ResourceMembership   = "membership"
+
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// Event describes a change to a calendar resource. ResourceID identifies
+// the resource, UserID is the user it belongs to and GroupID the group it
+// is attached to, if any; together they decide who gets to hear about it.
+type Event struct {
+	ID         uint64      `json:"id"`
+	Type       string      `json:"type"`
+	Resource   string      `json:"resource"`
+	Action     string      `json:"action"`
+	ResourceID uint        `json:"resource_id"`
+	UserID     uint        `json:"user_id"`
+	GroupID    uint        `json:"group_id"`
+	Data       interface{} `json:"data"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+type Handler func(event Event)
+
+// Bus fans events out to in-process subscribers. Handlers run
+// synchronously on the publishing goroutine, one event at a time in ID
+// order, and must not block or publish.
+type Bus struct {
+	// publishing keeps concurrent publishers from delivering their events
+	// out of order.
+	publishing  sync.Mutex
+	mutex       sync.RWMutex
+	lastID      uint64
+	nextHandler int
+	handlers    map[int]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[int]Handler)}
+}
+
+func (bus *Bus) Subscribe(handler Handler) func() {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	id := bus.nextHandler
+	bus.nextHandler++
+	bus.handlers[id] = handler
+	return func() {
+		bus.mutex.Lock()
+		defer bus.mutex.Unlock()
+		delete(bus.handlers, id)
+	}
+}
+
+func (bus *Bus) Publish(event Event) Event {
+	bus.publishing.Lock()
+	defer bus.publishing.Unlock()
+	bus.mutex.Lock()
+	bus.lastID++
+	event.ID = bus.lastID
+	event.Type = event.Resource + "." + event.Action
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	handlers := make([]Handler, 0, len(bus.handlers))
+	for _, handler := range bus.handlers {
+		handlers = append(handlers, handler)
+	}
+	bus.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+	return event
+}
+
+// Types lists every event type the bus can publish.
+var Types = []string{
+	ResourceDate + "." + ActionCreated, ResourceDate + "." + ActionUpdated, ResourceDate + "." + ActionDeleted,
+	ResourceAvailability + "." + ActionCreated, ResourceAvailability + "." + ActionUpdated, ResourceAvailability + "." + ActionDeleted,
+	ResourceGroup + "." + ActionCreated, ResourceGroup + "." + ActionUpdated, ResourceGroup + "." + ActionDeleted,
+	ResourceMembership + "." + ActionCreated, ResourceMembership + "." + ActionUpdated, ResourceMembership + "." + ActionDeleted,
+}
+
+// Matches reports whether an event type is selected by a filter pattern:
+// "*", a whole resource such as "date.*", or an exact type.
+func Matches(pattern string, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	return strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*"))
+}
+
+// ValidPattern reports whether a filter pattern can match any event type.
+func ValidPattern(pattern string) bool {
+	for _, eventType := range Types {
+		if Matches(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentPublishDeliversInOrder(t *testing.T) {
+	bus := NewBus()
+	var mutex sync.Mutex
+	var received []uint64
+	bus.Subscribe(func(event Event) {
+		// Yielding lets another publisher overtake this one if delivery
+		// is not ordered.
+		runtime.Gosched()
+		mutex.Lock()
+		defer mutex.Unlock()
+		received = append(received, event.ID)
+	})
+
+	const publishers, perPublisher = 8, 200
+	var wait sync.WaitGroup
+	for i := 0; i < publishers; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for j := 0; j < perPublisher; j++ {
+				bus.Publish(Event{Resource: ResourceDate, Action: ActionCreated})
+			}
+		}()
+	}
+	wait.Wait()
+
+	if len(received) != publishers*perPublisher {
+		t.Fatalf("received %d events, want %d", len(received), publishers*perPublisher)
+	}
+	for i, id := range received {
+		if id != uint64(i+1) {
+			t.Fatalf("event %d has ID %d, want %d", i, id, i+1)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/models"
+)
+
+func DateEvent(action string, date *dbmodel.Date) Event {
+	return Event{
+		Resource:   ResourceDate,
+		Action:     action,
+		ResourceID: date.ID,
+		UserID:     date.UserID,
+		Data: models.DateResponse{
+			ID:             date.ID,
+			Title:          date.Title,
+			Body:           date.Body,
+			DateBegin:      date.BeginTime,
+			DateEnd:        date.EndTime,
+			UserID:         date.UserID,
+			Private:        date.Private,
+			RecurrenceID:   date.RecurrenceID,
+			ColorID:        date.ColorID,
+			SubscriptionID: date.SubscriptionID,
+		},
+	}
+}
+
+func AvailabilityEvent(action string, availability *dbmodel.Availability) Event {
+	return Event{
+		Resource:   ResourceAvailability,
+		Action:     action,
+		ResourceID: availability.ID,
+		UserID:     availability.UserID,
+		Data: models.AvailabilityResponse{
+			ID:        availability.ID,
+			DateBegin: availability.BeginTime,
+			DateEnd:   availability.EndTime,
+			UserID:    availability.UserID,
+		},
+	}
+}
+
+func GroupEvent(action string, group *dbmodel.Group) Event {
+	return Event{
+		Resource:   ResourceGroup,
+		Action:     action,
+		ResourceID: group.ID,
+		UserID:     group.CreatorID,
+		GroupID:    group.ID,
+		Data:       models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID, ParentID: group.ParentID},
+	}
+}
+
+func MembershipEvent(action string, userGroup *dbmodel.UserGroup) Event {
+	return Event{
+		Resource:   ResourceMembership,
+		Action:     action,
+		ResourceID: userGroup.GroupID,
+		UserID:     userGroup.UserID,
+		GroupID:    userGroup.GroupID,
+		Data: models.MembershipResponse{
+			UserID:   userGroup.UserID,
+			GroupID:  userGroup.GroupID,
+			ColorID:  userGroup.ColorID,
+			Role:     userGroup.Role,
+			InviteID: userGroup.InviteID,
+		},
+	}
+}
+
+// RedactedFor hides the content of private dates from anyone but their
+// owner, the same way they appear as busy time in shared calendars.
+func (event Event) RedactedFor(userID uint) Event {
+	date, ok := event.Data.(models.DateResponse)
+	if !ok || !date.Private || date.UserID == userID {
+		return event
+	}
+	date.Title, date.Body = "Busy", ""
+	event.Data = date
+	return event
+}
@@ -0,0 +1,248 @@
+package feed
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/ical"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const tokenBytes = 32
+
+type FeedConfig struct {
+	*config.Config
+}
+
+func NewFeedConfig(cfg *config.Config) *FeedConfig {
+	return &FeedConfig{Config: cfg}
+}
+
+// @Summary		List feed tokens
+// @Description	List the calendar feed tokens of the current user
+// @Tags		feeds
+// @Produce		json
+// @Success		200	{array}		models.FeedTokenResponse
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/feed/ [get]
+func (config *FeedConfig) GetFeedTokens(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	feedTokens, err := config.FeedTokenRepository.FindByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve feed tokens", http.StatusInternalServerError)
+		return
+	}
+	feedTokenResponse := make([]models.FeedTokenResponse, 0)
+	for _, feedToken := range feedTokens {
+		feedTokenResponse = append(feedTokenResponse, *toFeedTokenResponse(&feedToken))
+	}
+	render.JSON(w, r, feedTokenResponse)
+}
+
+// @Summary		Create a feed token
+// @Description	Create a secret token exposing the current user's calendar, or one of their groups' calendar, as a read-only iCalendar feed. The token is only returned once.
+// @Tags		feeds
+// @Accept		json
+// @Produce		json
+// @Param		request	body	models.FeedTokenRequest	true	"Feed token data"
+// @Success		200	{object}	models.FeedTokenResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/feed/ [post]
+func (config *FeedConfig) CreateFeedToken(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	req := &models.FeedTokenRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.GroupID != 0 && !config.canSeeMembers(user, req.GroupID) {
+		http.Error(w, "You are not a member of this group, or only a guest", http.StatusForbidden)
+		return
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+	feedToken := &dbmodel.FeedToken{
+		UserID:         user.ID,
+		GroupID:        req.GroupID,
+		Name:           req.Name,
+		TokenHash:      hashToken(token),
+		IncludePrivate: req.IncludePrivate,
+	}
+	created, err := config.FeedTokenRepository.Create(feedToken)
+	if err != nil {
+		http.Error(w, "Failed to create feed token", http.StatusInternalServerError)
+		return
+	}
+	feedTokenResponse := toFeedTokenResponse(created)
+	feedTokenResponse.Token = token
+	feedTokenResponse.URL = feedURL(config.PublicURL, token)
+	render.JSON(w, r, feedTokenResponse)
+}
+
+// @Summary		Revoke a feed token
+// @Description	Revoke one of the current user's feed tokens; its URL stops working immediately
+// @Tags		feeds
+// @Produce		json
+// @Param		id	path	int	true	"Feed token ID"
+// @Success		200	{object}	map[string]string
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/feed/{id} [delete]
+func (config *FeedConfig) RevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid feed token ID", http.StatusBadRequest)
+		return
+	}
+	if id < 1 {
+		http.Error(w, "id must be >= 1", http.StatusBadRequest)
+		return
+	}
+	user := authentication.UserFromContext(r.Context())
+	feedToken, err := config.FeedTokenRepository.FindByID(uint(id))
+	if err != nil || feedToken.UserID != user.ID {
+		http.Error(w, "Feed token not found", http.StatusNotFound)
+		return
+	}
+	if err := config.FeedTokenRepository.DeleteByID(feedToken.ID); err != nil {
+		http.Error(w, "Failed to revoke feed token", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, map[string]string{"message": "Feed token revoked successfully"})
+}
+
+// @Summary		Get an iCalendar feed
+// @Description	Public read-only iCalendar feed identified by a secret feed token, meant for calendar apps that cannot send an Authorization header. A group feed holds the dates of the members its owner may see through the API, which leaves out the group's guests
+// @Tags		feeds
+// @Produce		text/calendar
+// @Param		token	path	string	true	"Feed token"
+// @Success		200	{string}	string	"iCalendar document"
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @x-base-path	"/"
+// @Router		/feeds/{token}.ics [get]
+func (config *FeedConfig) GetFeed(w http.ResponseWriter, r *http.Request) {
+	feedToken, err := config.FeedTokenRepository.FindByTokenHash(hashToken(chi.URLParam(r, "token")))
+	if err != nil {
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+	owner, err := config.UserRepository.FindByID(feedToken.UserID)
+	if err != nil {
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+
+	calendar := &ical.Calendar{Name: owner.Username}
+	if feedToken.GroupID == 0 {
+		dates, err := config.DateRepository.FindByUserID(owner.ID)
+		if err != nil {
+			http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+			return
+		}
+		calendar.Events = ical.FromDates(dates, func(date *dbmodel.Date) bool {
+			return date.Private && !feedToken.IncludePrivate
+		})
+	} else {
+		group, err := config.GroupRepository.FindByID(feedToken.GroupID)
+		if err != nil || !config.canSeeMembers(owner, group.ID) {
+			http.Error(w, "Feed not found", http.StatusNotFound)
+			return
+		}
+		calendar.Name = group.Name
+		// Guests and the members the owner shares no group with keep
+		// their calendars, as they would through the API.
+		memberIDs, err := config.GroupRepository.FindMemberIDs(group.ID)
+		if err == nil {
+			memberIDs, err = authorization.VisibleUserIDs(config.GroupRepository, owner, memberIDs)
+		}
+		if err != nil {
+			http.Error(w, "Failed to retrieve group members", http.StatusInternalServerError)
+			return
+		}
+		for _, memberID := range memberIDs {
+			dates, err := config.DateRepository.FindByUserID(memberID)
+			if err != nil {
+				http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+				return
+			}
+			calendar.Events = append(calendar.Events, ical.FromDates(dates, func(date *dbmodel.Date) bool {
+				return date.Private && (date.UserID != owner.ID || !feedToken.IncludePrivate)
+			})...)
+		}
+	}
+
+	if err := config.FeedTokenRepository.UpdateLastUsedAt(feedToken.ID, time.Now()); err != nil {
+		http.Error(w, "Failed to update feed token", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if err := ical.Write(w, calendar); err != nil {
+		http.Error(w, "Failed to write feed", http.StatusInternalServerError)
+	}
+}
+
+// canSeeMembers reports whether a user may follow the calendars of a
+// group's members, which its guests may not.
+func (config *FeedConfig) canSeeMembers(user *dbmodel.User, groupID uint) bool {
+	group, err := config.GroupRepository.FindByID(groupID)
+	if err != nil {
+		return false
+	}
+	allowed, err := authorization.CanInGroup(config.UserGroupRepository, user, group, authorization.SeeMembers)
+	return err == nil && allowed
+}
+
+func generateToken() (string, error) {
+	buffer := make([]byte, tokenBytes)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func feedURL(publicURL string, token string) string {
+	return publicURL + "/feeds/" + token + ".ics"
+}
+
+func toFeedTokenResponse(feedToken *dbmodel.FeedToken) *models.FeedTokenResponse {
+	return &models.FeedTokenResponse{
+		ID:             feedToken.ID,
+		Name:           feedToken.Name,
+		GroupID:        feedToken.GroupID,
+		IncludePrivate: feedToken.IncludePrivate,
+		CreatedAt:      feedToken.CreatedAt,
+		LastUsedAt:     feedToken.LastUsedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestGroupFeedLeavesOutGuests(t *testing.T) {
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	begin := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	user := func(username string) *dbmodel.User {
+		created, err := cfg.UserRepository.Create(&dbmodel.User{Username: username, Email: username + "@example.com", Password: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cfg.DateRepository.CreateWithOccurrences(&dbmodel.Date{Title: username + "'s date", UserID: created.ID, BeginTime: begin, EndTime: begin.Add(time.Hour)}, nil); err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+	join := func(user *dbmodel.User, group *dbmodel.Group, role string) {
+		if _, err := cfg.UserGroupRepository.Create(&dbmodel.UserGroup{UserID: user.ID, GroupID: group.ID, Role: role}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alice := user("alice")
+	group, err := cfg.GroupRepository.Create(&dbmodel.Group{Name: "Team", CreatorID: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := cfg.GroupRepository.Create(&dbmodel.Group{Name: "Subteam", CreatorID: alice.ID, ParentID: &group.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	join(user("bob"), group, dbmodel.RoleMember)
+	join(user("carol"), group, dbmodel.RoleGuest)
+	join(user("erin"), child, dbmodel.RoleMember)
+	join(user("dave"), child, dbmodel.RoleGuest)
+
+	token := "group-feed-token"
+	if _, err := cfg.FeedTokenRepository.Create(&dbmodel.FeedToken{UserID: alice.ID, GroupID: group.ID, Name: "Team", TokenHash: hashToken(token)}); err != nil {
+		t.Fatal(err)
+	}
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add("token", token)
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, routeContext))
+	response := httptest.NewRecorder()
+	NewFeedConfig(cfg).GetFeed(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("feed = %d %s", response.Code, response.Body)
+	}
+
+	body := response.Body.String()
+	for _, username := range []string{"alice", "bob", "erin"} {
+		if !strings.Contains(body, "SUMMARY:"+username+"'s date") {
+			t.Errorf("feed misses %s's date", username)
+		}
+	}
+	for _, username := range []string{"carol", "dave"} {
+		if strings.Contains(body, username+"'s date") {
+			t.Errorf("feed holds the date of %s, a guest", username)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package feed
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+feed routes:
+GET /feed/ - List the current user's feed tokens
+POST /feed/ - Create a feed token
+DELETE /feed/{id} - Revoke a feed token
+
+public feed routes:
+GET /feeds/{token}.ics - Read-only iCalendar feed
+*/
+
+func Routes(config *config.Config) chi.Router {
+	FeedConfig := NewFeedConfig(config)
+	router := chi.NewRouter()
+	router.Get("/", FeedConfig.GetFeedTokens)
+	router.Post("/", FeedConfig.CreateFeedToken)
+	router.Delete("/{id}", FeedConfig.RevokeFeedToken)
+	return router
+}
+
+func PublicRoutes(config *config.Config) chi.Router {
+	FeedConfig := NewFeedConfig(config)
+	router := chi.NewRouter()
+	router.Get("/{token}.ics", FeedConfig.GetFeed)
+	return router
+}
@@ -0,0 +1,68 @@
+// Package freebusy computes busy and free time from dates.
+package freebusy
+
+import (
+	"sort"
+	"time"
+
+	"yplanning/database/dbmodel"
+)
+
+type Period struct {
+	Begin time.Time
+	End   time.Time
+}
+
+// Busy returns the time taken by dates between from and to, clipped to the
+// range and with overlapping dates merged. Only times are used, so the
+// result is safe to share for private dates.
+func Busy(dates []dbmodel.Date, from time.Time, to time.Time) []Period {
+	periods := make([]Period, 0, len(dates))
+	for _, date := range dates {
+		period := Period{Begin: date.BeginTime, End: date.EndTime}
+		if period.Begin.Before(from) {
+			period.Begin = from
+		}
+		if period.End.After(to) {
+			period.End = to
+		}
+		if period.Begin.Before(period.End) {
+			periods = append(periods, period)
+		}
+	}
+	return Merge(periods)
+}
+
+// Free returns the gaps between busy periods from from to to that last at
+// least minimum.
+func Free(busy []Period, from time.Time, to time.Time, minimum time.Duration) []Period {
+	var free []Period
+	cursor := from
+	for _, period := range append(Merge(busy), Period{Begin: to, End: to}) {
+		if period.Begin.Sub(cursor) >= minimum && period.Begin.After(cursor) {
+			free = append(free, Period{Begin: cursor, End: period.Begin})
+		}
+		if period.End.After(cursor) {
+			cursor = period.End
+		}
+	}
+	return free
+}
+
+// Merge sorts periods and merges those that overlap or touch.
+func Merge(periods []Period) []Period {
+	sorted := append([]Period(nil), periods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Begin.Before(sorted[j].Begin) })
+	var merged []Period
+	for _, current := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && !current.Begin.After(merged[last].End) {
+			if current.End.After(merged[last].End) {
+				merged[last].End = current.End
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+	return merged
+}
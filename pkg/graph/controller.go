@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"net/http"
+
+	"yplanning/config"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/render"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxParallelism = 100
+	// maxDepth allows me { groups { members { user { dates { ... } } } } }
+	// and a few more levels, but not queries that walk the graph in circles.
+	maxDepth       = 10
+	maxQueryLength = 16 << 10
+	// maxRequestSize also leaves room for the variables.
+	maxRequestSize = 64 << 10
+)
+
+type GraphConfig struct {
+	*config.Config
+	schema *graphql.Schema
+}
+
+func NewGraphConfig(cfg *config.Config) *GraphConfig {
+	return &GraphConfig{
+		Config: cfg,
+		schema: graphql.MustParseSchema(schema, &resolver{},
+			graphql.MaxParallelism(maxParallelism),
+			graphql.MaxDepth(maxDepth),
+			graphql.MaxQueryLength(maxQueryLength),
+		),
+	}
+}
+
+// @Summary		Run a GraphQL query
+// @Description	Run a read-only GraphQL query over users, groups, memberships, colors, dates and availabilities. Lookups are batched per request, and the caller only sees itself, its groups and their members, as with the REST endpoints. Queries nested deeper than 10 fields or longer than 16 KiB are rejected in the errors array, and bodies over 64 KiB with a 400. Errors of individual fields are reported in the errors array of a 200 response.
+// @Tags		graphql
+// @Accept		json
+// @Produce		json
+// @Param		request	body	models.GraphQLRequest	true	"GraphQL query"
+// @Success		200	{object}	map[string]interface{}
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/graphql/ [post]
+func (config *GraphConfig) Query(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	req := &models.GraphQLRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	session, err := newSession(config.Config, user)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	response := config.schema.Exec(withSession(r.Context(), session), req.Query, req.OperationName, req.Variables)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+)
+
+func TestQueryLimits(t *testing.T) {
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := cfg.UserRepository.Create(&dbmodel.User{Username: "alice", Email: "alice@example.com", Password: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := NewGraphConfig(cfg)
+	exec := func(query string) []string {
+		session, err := newSession(cfg, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := graph.schema.Exec(withSession(context.Background(), session), query, "", nil)
+		messages := make([]string, 0, len(response.Errors))
+		for _, err := range response.Errors {
+			messages = append(messages, err.Message)
+		}
+		return messages
+	}
+	// nested walks from the caller to its groups' members and back cycles
+	// times, three fields per cycle.
+	nested := func(cycles int) string {
+		query := "id"
+		for i := 0; i < cycles; i++ {
+			query = "groups { members { user { " + query + " } } }"
+		}
+		return "{ me { " + query + " } }"
+	}
+
+	if errors := exec(nested(2)); len(errors) != 0 {
+		t.Errorf("shallow query: %v", errors)
+	}
+	if errors := exec(nested(4)); len(errors) == 0 {
+		t.Error("deep query succeeded, want a depth error")
+	}
+	if errors := exec("{ me { id " + strings.Repeat(" ", maxQueryLength) + "} }"); len(errors) == 0 {
+		t.Error("long query succeeded, want a length error")
+	}
+
+	body := `{"query":"{ me { id } }","variables":{"padding":"` + strings.Repeat("x", maxRequestSize) + `"}}`
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	graph.Query(response, request)
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("oversized body = %d, want 400", response.Code)
+	}
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	batchWindow  = 2 * time.Millisecond
+	maxBatchSize = 500
+)
+
+// loader batches the lookups resolvers make concurrently into a single
+// repository call, and caches the results for the rest of the request.
+// A key missing from the batch result resolves to the zero value.
+type loader[K comparable, V any] struct {
+	fetch   func(keys []K) (map[K]V, error)
+	mutex   sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*result[V]
+	full    chan struct{}
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, cache: make(map[K]*result[V])}
+}
+
+func (loader *loader[K, V]) load(key K) (V, error) {
+	loader.mutex.Lock()
+	if cached, ok := loader.cache[key]; ok {
+		loader.mutex.Unlock()
+		<-cached.done
+		return cached.value, cached.err
+	}
+	pending := &result[V]{done: make(chan struct{})}
+	loader.cache[key] = pending
+	if loader.pending == nil {
+		loader.pending = &batch[K, V]{results: make(map[K]*result[V]), full: make(chan struct{})}
+		go loader.dispatch(loader.pending)
+	}
+	current := loader.pending
+	current.keys = append(current.keys, key)
+	current.results[key] = pending
+	if len(current.keys) >= maxBatchSize {
+		loader.pending = nil
+		close(current.full)
+	}
+	loader.mutex.Unlock()
+
+	<-pending.done
+	return pending.value, pending.err
+}
+
+func (loader *loader[K, V]) dispatch(current *batch[K, V]) {
+	select {
+	case <-time.After(batchWindow):
+		loader.mutex.Lock()
+		if loader.pending == current {
+			loader.pending = nil
+		}
+		loader.mutex.Unlock()
+	case <-current.full:
+	}
+
+	values, err := loader.fetch(current.keys)
+	for key, pending := range current.results {
+		pending.value, pending.err = values[key], err
+		close(pending.done)
+	}
+}
+
+// loadAll loads keys concurrently, so that they share a batch, and returns
+// the values in the order of keys.
+func (loader *loader[K, V]) loadAll(keys []K) ([]V, error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key K) {
+			defer wg.Done()
+			values[i], errs[i] = loader.load(key)
+		}(i, key)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
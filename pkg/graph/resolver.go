@@ -0,0 +1,319 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"yplanning/database/dbmodel"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+var errNotFound = errors.New("not found")
+
+// resolver is the root of the schema. Every field resolves through the
+// request session, which enforces the same visibility rules as the REST
+// endpoints: a caller sees itself, its groups and the members of those
+// groups, and only the titles of its own private dates.
+type resolver struct{}
+
+func (*resolver) Me(ctx context.Context) *userResolver {
+	session := sessionFrom(ctx)
+	return &userResolver{session: session, user: session.viewer}
+}
+
+func (*resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	session := sessionFrom(ctx)
+	id, err := parseID(args.ID)
+	if err != nil || !session.users[id] {
+		return nil, errNotFound
+	}
+	return session.user(id)
+}
+
+func (*resolver) Group(ctx context.Context, args struct{ ID graphql.ID }) (*groupResolver, error) {
+	session := sessionFrom(ctx)
+	id, err := parseID(args.ID)
+	if err != nil || !session.groups[id] {
+		return nil, errNotFound
+	}
+	return session.group(id)
+}
+
+func (*resolver) Groups(ctx context.Context) ([]*groupResolver, error) {
+	session := sessionFrom(ctx)
+	groupIDs, err := session.userGroupLoader.load(session.viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+	return session.groupList(groupIDs)
+}
+
+func (*resolver) Colors(ctx context.Context) ([]*colorResolver, error) {
+	session := sessionFrom(ctx)
+	colors, err := session.ColorRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	colorResolvers := make([]*colorResolver, 0, len(colors))
+	for i := range colors {
+		colorResolvers = append(colorResolvers, &colorResolver{color: &colors[i]})
+	}
+	return colorResolvers, nil
+}
+
+type userResolver struct {
+	session *session
+	user    *dbmodel.User
+}
+
+func (userResolver *userResolver) ID() graphql.ID   { return formatID(userResolver.user.ID) }
+func (userResolver *userResolver) Username() string { return userResolver.user.Username }
+func (userResolver *userResolver) Email() string    { return userResolver.user.Email }
+func (userResolver *userResolver) Name() string     { return userResolver.user.Name }
+func (userResolver *userResolver) Surname() string  { return userResolver.user.Surname }
+
+func (userResolver *userResolver) Color() (*colorResolver, error) {
+	return userResolver.session.color(userResolver.user.ColorID)
+}
+
+func (userResolver *userResolver) Groups() ([]*groupResolver, error) {
+	groupIDs, err := userResolver.session.userGroupLoader.load(userResolver.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return userResolver.session.groupList(groupIDs)
+}
+
+type rangeArgs struct {
+	From *graphql.Time
+	To   *graphql.Time
+}
+
+func (args rangeArgs) key(userID uint) rangeKey {
+	key := rangeKey{userID: userID}
+	if args.From != nil {
+		key.from = args.From.Time.UTC()
+	}
+	if args.To != nil {
+		key.to = args.To.Time.UTC()
+	}
+	return key
+}
+
+func (userResolver *userResolver) Dates(args rangeArgs) ([]*dateResolver, error) {
+	dates, err := userResolver.session.dateLoader.load(args.key(userResolver.user.ID))
+	if err != nil {
+		return nil, err
+	}
+	dateResolvers := make([]*dateResolver, 0, len(dates))
+	for i := range dates {
+		date := dates[i]
+		if date.Private && date.UserID != userResolver.session.viewer.ID {
+			date.Title, date.Body = "Busy", ""
+		}
+		dateResolvers = append(dateResolvers, &dateResolver{session: userResolver.session, date: &date})
+	}
+	return dateResolvers, nil
+}
+
+func (userResolver *userResolver) Availabilities(args rangeArgs) ([]*availabilityResolver, error) {
+	availabilities, err := userResolver.session.availabilityLoader.load(args.key(userResolver.user.ID))
+	if err != nil {
+		return nil, err
+	}
+	availabilityResolvers := make([]*availabilityResolver, 0, len(availabilities))
+	for i := range availabilities {
+		availabilityResolvers = append(availabilityResolvers, &availabilityResolver{session: userResolver.session, availability: &availabilities[i]})
+	}
+	return availabilityResolvers, nil
+}
+
+type groupResolver struct {
+	session *session
+	group   *dbmodel.Group
+}
+
+func (groupResolver *groupResolver) ID() graphql.ID { return formatID(groupResolver.group.ID) }
+func (groupResolver *groupResolver) Name() string   { return groupResolver.group.Name }
+
+func (groupResolver *groupResolver) Creator() (*userResolver, error) {
+	return groupResolver.session.user(groupResolver.group.CreatorID)
+}
+
+// Members is empty for the guests of the group, who only see its free/busy
+// time.
+func (groupResolver *groupResolver) Members() ([]*membershipResolver, error) {
+	if groupResolver.session.guestOf[groupResolver.group.ID] {
+		return []*membershipResolver{}, nil
+	}
+	userGroups, err := groupResolver.session.memberLoader.load(groupResolver.group.ID)
+	if err != nil {
+		return nil, err
+	}
+	membershipResolvers := make([]*membershipResolver, 0, len(userGroups))
+	for _, userGroup := range userGroups {
+		membershipResolvers = append(membershipResolvers, &membershipResolver{session: groupResolver.session, group: groupResolver, userGroup: userGroup})
+	}
+	return membershipResolvers, nil
+}
+
+type membershipResolver struct {
+	session   *session
+	group     *groupResolver
+	userGroup dbmodel.UserGroup
+}
+
+func (membershipResolver *membershipResolver) User() (*userResolver, error) {
+	return membershipResolver.session.user(membershipResolver.userGroup.UserID)
+}
+
+func (membershipResolver *membershipResolver) Group() *groupResolver {
+	return membershipResolver.group
+}
+
+func (membershipResolver *membershipResolver) Color() (*colorResolver, error) {
+	return membershipResolver.session.color(membershipResolver.userGroup.ColorID)
+}
+
+func (membershipResolver *membershipResolver) Role() string {
+	if membershipResolver.userGroup.UserID == membershipResolver.group.group.CreatorID {
+		return dbmodel.RoleOwner
+	}
+	return membershipResolver.userGroup.Role
+}
+
+type colorResolver struct {
+	color *dbmodel.Color
+}
+
+func (colorResolver *colorResolver) ID() graphql.ID  { return formatID(colorResolver.color.ID) }
+func (colorResolver *colorResolver) Name() string    { return colorResolver.color.Name }
+func (colorResolver *colorResolver) HexCode() string { return colorResolver.color.HexCode }
+
+type dateResolver struct {
+	session *session
+	date    *dbmodel.Date
+}
+
+func (dateResolver *dateResolver) ID() graphql.ID { return formatID(dateResolver.date.ID) }
+func (dateResolver *dateResolver) Title() string  { return dateResolver.date.Title }
+func (dateResolver *dateResolver) Body() string   { return dateResolver.date.Body }
+func (dateResolver *dateResolver) Begin() graphql.Time {
+	return graphql.Time{Time: dateResolver.date.BeginTime}
+}
+func (dateResolver *dateResolver) End() graphql.Time {
+	return graphql.Time{Time: dateResolver.date.EndTime}
+}
+func (dateResolver *dateResolver) Private() bool { return dateResolver.date.Private }
+
+func (dateResolver *dateResolver) User() (*userResolver, error) {
+	return dateResolver.session.user(dateResolver.date.UserID)
+}
+
+func (dateResolver *dateResolver) Color() (*colorResolver, error) {
+	return dateResolver.session.color(dateResolver.date.ColorID)
+}
+
+func (dateResolver *dateResolver) RecurrenceID() *graphql.ID {
+	return optionalID(dateResolver.date.RecurrenceID)
+}
+
+func (dateResolver *dateResolver) SubscriptionID() *graphql.ID {
+	return optionalID(dateResolver.date.SubscriptionID)
+}
+
+type availabilityResolver struct {
+	session      *session
+	availability *dbmodel.Availability
+}
+
+func (availabilityResolver *availabilityResolver) ID() graphql.ID {
+	return formatID(availabilityResolver.availability.ID)
+}
+
+func (availabilityResolver *availabilityResolver) Begin() graphql.Time {
+	return graphql.Time{Time: availabilityResolver.availability.BeginTime}
+}
+
+func (availabilityResolver *availabilityResolver) End() graphql.Time {
+	return graphql.Time{Time: availabilityResolver.availability.EndTime}
+}
+
+func (availabilityResolver *availabilityResolver) User() (*userResolver, error) {
+	return availabilityResolver.session.user(availabilityResolver.availability.UserID)
+}
+
+// user resolves a user the caller may see, or null.
+func (session *session) user(id uint) (*userResolver, error) {
+	if !session.users[id] {
+		return nil, nil
+	}
+	user, err := session.userLoader.load(id)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &userResolver{session: session, user: user}, nil
+}
+
+// group resolves a group the caller belongs to, or null.
+func (session *session) group(id uint) (*groupResolver, error) {
+	if !session.groups[id] {
+		return nil, nil
+	}
+	group, err := session.groupLoader.load(id)
+	if err != nil || group == nil {
+		return nil, err
+	}
+	return &groupResolver{session: session, group: group}, nil
+}
+
+// groupList resolves the groups among groupIDs that the caller belongs to.
+func (session *session) groupList(groupIDs []uint) ([]*groupResolver, error) {
+	var visible []uint
+	for _, groupID := range groupIDs {
+		if session.groups[groupID] {
+			visible = append(visible, groupID)
+		}
+	}
+	groups, err := session.groupLoader.loadAll(visible)
+	if err != nil {
+		return nil, err
+	}
+	groupResolvers := make([]*groupResolver, 0, len(groups))
+	for _, group := range groups {
+		if group != nil {
+			groupResolvers = append(groupResolvers, &groupResolver{session: session, group: group})
+		}
+	}
+	return groupResolvers, nil
+}
+
+func (session *session) color(id uint) (*colorResolver, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	color, err := session.colorLoader.load(id)
+	if err != nil || color == nil {
+		return nil, err
+	}
+	return &colorResolver{color: color}, nil
+}
+
+func formatID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func optionalID(id uint) *graphql.ID {
+	if id == 0 {
+		return nil
+	}
+	formatted := formatID(id)
+	return &formatted
+}
+
+func parseID(id graphql.ID) (uint, error) {
+	parsed, err := strconv.ParseUint(string(id), 10, 64)
+	return uint(parsed), err
+}
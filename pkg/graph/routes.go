@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+graphql routes:
+POST /graphql/ - Run a GraphQL query
+*/
+
+func Routes(config *config.Config) chi.Router {
+	GraphConfig := NewGraphConfig(config)
+	router := chi.NewRouter()
+	router.Post("/", GraphConfig.Query)
+	return router
+}
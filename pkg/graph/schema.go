@@ -0,0 +1,79 @@
+package graph
+
+// schema is the GraphQL schema served at /api/graphql. It is read-only:
+// writes keep going through the REST endpoints.
+const schema = `
+scalar Time
+
+schema {
+	query: Query
+}
+
+type Query {
+	# The authenticated user.
+	me: User!
+	# A user the caller shares a group with, or the caller.
+	user(id: ID!): User
+	# A group the caller belongs to.
+	group(id: ID!): Group
+	# The groups the caller belongs to.
+	groups: [Group!]!
+	colors: [Color!]!
+}
+
+type User {
+	id: ID!
+	username: String!
+	email: String!
+	name: String!
+	surname: String!
+	color: Color
+	# The groups of this user that the caller belongs to as well.
+	groups: [Group!]!
+	# Dates overlapping the range; other users' private dates read "Busy".
+	dates(from: Time, to: Time): [Date!]!
+	availabilities(from: Time, to: Time): [Availability!]!
+}
+
+type Group {
+	id: ID!
+	name: String!
+	creator: User
+	members: [Membership!]!
+}
+
+type Membership {
+	user: User
+	group: Group!
+	# The color the member picked for this group.
+	color: Color
+	# owner, admin, member or guest.
+	role: String!
+}
+
+type Color {
+	id: ID!
+	name: String!
+	hexCode: String!
+}
+
+type Date {
+	id: ID!
+	title: String!
+	body: String!
+	begin: Time!
+	end: Time!
+	private: Boolean!
+	user: User
+	color: Color
+	recurrenceId: ID
+	subscriptionId: ID
+}
+
+type Availability {
+	id: ID!
+	begin: Time!
+	end: Time!
+	user: User
+}
+`
@@ -0,0 +1,237 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+)
+
+type sessionKey struct{}
+
+// session is the state of one GraphQL request: who is asking, what they
+// may see, and the loaders that batch and cache repository calls.
+type session struct {
+	*config.Config
+	viewer *dbmodel.User
+	// users holds the caller and everyone sharing a group with them, where
+	// neither is a guest; groups the groups the caller belongs to, and
+	// guestOf those where they are only a guest.
+	users   map[uint]bool
+	groups  map[uint]bool
+	guestOf map[uint]bool
+
+	userLoader         *loader[uint, *dbmodel.User]
+	colorLoader        *loader[uint, *dbmodel.Color]
+	groupLoader        *loader[uint, *dbmodel.Group]
+	memberLoader       *loader[uint, []dbmodel.UserGroup]
+	userGroupLoader    *loader[uint, []uint]
+	dateLoader         *loader[rangeKey, []dbmodel.Date]
+	availabilityLoader *loader[rangeKey, []dbmodel.Availability]
+}
+
+type rangeKey struct {
+	userID uint
+	from   time.Time
+	to     time.Time
+}
+
+func newSession(cfg *config.Config, viewer *dbmodel.User) (*session, error) {
+	session := &session{
+		Config:  cfg,
+		viewer:  viewer,
+		users:   map[uint]bool{viewer.ID: true},
+		groups:  make(map[uint]bool),
+		guestOf: make(map[uint]bool),
+	}
+	groups, err := cfg.GroupRepository.FindByMemberID(viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		session.groups[group.ID] = true
+	}
+	peerIDs, err := cfg.GroupRepository.FindPeerIDs(viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, peerID := range peerIDs {
+		session.users[peerID] = true
+	}
+	// Roles may be inherited from nested groups, so they are not read off
+	// the caller's memberships directly
+	for i := range groups {
+		role, err := authorization.GroupRole(cfg.UserGroupRepository, viewer, &groups[i])
+		if err != nil {
+			return nil, err
+		}
+		session.guestOf[groups[i].ID] = role == dbmodel.RoleGuest
+	}
+
+	session.userLoader = newLoader(session.fetchUsers)
+	session.colorLoader = newLoader(session.fetchColors)
+	session.groupLoader = newLoader(session.fetchGroups)
+	session.memberLoader = newLoader(session.fetchMembers)
+	session.userGroupLoader = newLoader(session.fetchUserGroups)
+	session.dateLoader = newLoader(session.fetchDates)
+	session.availabilityLoader = newLoader(session.fetchAvailabilities)
+	return session, nil
+}
+
+func withSession(ctx context.Context, session *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+func sessionFrom(ctx context.Context) *session {
+	return ctx.Value(sessionKey{}).(*session)
+}
+
+func (session *session) fetchUsers(ids []uint) (map[uint]*dbmodel.User, error) {
+	users, err := session.UserRepository.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*dbmodel.User, len(users))
+	for i := range users {
+		byID[users[i].ID] = &users[i]
+	}
+	return byID, nil
+}
+
+func (session *session) fetchColors(ids []uint) (map[uint]*dbmodel.Color, error) {
+	colors, err := session.ColorRepository.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*dbmodel.Color, len(colors))
+	for i := range colors {
+		byID[colors[i].ID] = &colors[i]
+	}
+	return byID, nil
+}
+
+func (session *session) fetchGroups(ids []uint) (map[uint]*dbmodel.Group, error) {
+	groups, err := session.GroupRepository.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*dbmodel.Group, len(groups))
+	for i := range groups {
+		byID[groups[i].ID] = &groups[i]
+	}
+	return byID, nil
+}
+
+// fetchMembers returns the membership rows of each group, with one for the
+// creator first when the creator has none of their own.
+func (session *session) fetchMembers(groupIDs []uint) (map[uint][]dbmodel.UserGroup, error) {
+	groups, err := session.groupLoaderMap(groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	userGroups, err := session.UserGroupRepository.FindByGroupIDs(groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[uint][]dbmodel.UserGroup, len(groupIDs))
+	for _, userGroup := range userGroups {
+		members[userGroup.GroupID] = append(members[userGroup.GroupID], userGroup)
+	}
+	for groupID, group := range groups {
+		hasCreator := false
+		for _, userGroup := range members[groupID] {
+			hasCreator = hasCreator || userGroup.UserID == group.CreatorID
+		}
+		if !hasCreator {
+			members[groupID] = append([]dbmodel.UserGroup{{UserID: group.CreatorID, GroupID: groupID}}, members[groupID]...)
+		}
+	}
+	return members, nil
+}
+
+func (session *session) groupLoaderMap(groupIDs []uint) (map[uint]*dbmodel.Group, error) {
+	groups, err := session.groupLoader.loadAll(groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*dbmodel.Group, len(groupIDs))
+	for _, group := range groups {
+		if group != nil {
+			byID[group.ID] = group
+		}
+	}
+	return byID, nil
+}
+
+// fetchUserGroups returns the IDs of the groups each user created or
+// joined.
+func (session *session) fetchUserGroups(userIDs []uint) (map[uint][]uint, error) {
+	groupIDs := make(map[uint][]uint, len(userIDs))
+	created, err := session.GroupRepository.FindByCreatorIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range created {
+		groupIDs[group.CreatorID] = append(groupIDs[group.CreatorID], group.ID)
+	}
+	userGroups, err := session.UserGroupRepository.FindByUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, userGroup := range userGroups {
+		groupIDs[userGroup.UserID] = appendUnique(groupIDs[userGroup.UserID], userGroup.GroupID)
+	}
+	return groupIDs, nil
+}
+
+func (session *session) fetchDates(keys []rangeKey) (map[rangeKey][]dbmodel.Date, error) {
+	byKey := make(map[rangeKey][]dbmodel.Date, len(keys))
+	for window, userIDs := range byWindow(keys) {
+		dates, err := session.DateRepository.FindByUserIDsInRange(userIDs, window.from, window.to)
+		if err != nil {
+			return nil, err
+		}
+		for _, date := range dates {
+			key := rangeKey{userID: date.UserID, from: window.from, to: window.to}
+			byKey[key] = append(byKey[key], date)
+		}
+	}
+	return byKey, nil
+}
+
+func (session *session) fetchAvailabilities(keys []rangeKey) (map[rangeKey][]dbmodel.Availability, error) {
+	byKey := make(map[rangeKey][]dbmodel.Availability, len(keys))
+	for window, userIDs := range byWindow(keys) {
+		availabilities, err := session.AvailabilityRepository.FindByUserIDsInRange(userIDs, window.from, window.to)
+		if err != nil {
+			return nil, err
+		}
+		for _, availability := range availabilities {
+			key := rangeKey{userID: availability.UserID, from: window.from, to: window.to}
+			byKey[key] = append(byKey[key], availability)
+		}
+	}
+	return byKey, nil
+}
+
+// byWindow groups range lookups by their time range, so that each
+// distinct range costs one query whatever the number of users.
+func byWindow(keys []rangeKey) map[rangeKey][]uint {
+	windows := make(map[rangeKey][]uint)
+	for _, key := range keys {
+		window := rangeKey{from: key.from, to: key.to}
+		windows[window] = append(windows[window], key.userID)
+	}
+	return windows
+}
+
+func appendUnique(ids []uint, id uint) []uint {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
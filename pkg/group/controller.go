@@ -6,6 +6,8 @@ import (
 
 	"yplanning/config"
 	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
 	"yplanning/pkg/models"
 
 	"github.com/go-chi/chi/v5"
@@ -27,7 +29,8 @@ func NewGroupConfig(cfg *config.Config) *GroupConfig {
 // @Produce		json
 // @Param		request	body	models.GroupRequest	true	"Group creation data"
 // @Success		200	{object}	models.GroupResponse
-// @Failure 	400 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
 // @Security 	BearerAuth
 // @Router		/group/ [post]
 func (config *GroupConfig) CreateGroup(w http.ResponseWriter, r *http.Request) {
@@ -36,22 +39,33 @@ func (config *GroupConfig) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	user := authorization.UserFromContext(r.Context())
+	if !authorization.CanChangeUser(user, req.CreatorID) {
+		http.Error(w, "You can only create groups for yourself", http.StatusForbidden)
+		return
+	}
+	if !authorization.VerificationAllows(user, authorization.CreateGroups) {
+		http.Error(w, "Verify your email to create groups", http.StatusForbidden)
+		return
+	}
 	group := &dbmodel.Group{Name: req.Name, CreatorID: req.CreatorID}
 	created, err := config.GroupRepository.Create(group)
 	if err != nil {
 		http.Error(w, "Failed to create group", http.StatusInternalServerError)
 		return
 	}
-	groupResponse := &models.GroupResponse{ID: created.ID, Name: created.Name, CreatorID: created.CreatorID}
+	config.Events.Publish(events.GroupEvent(events.ActionCreated, created))
+	groupResponse := &models.GroupResponse{ID: created.ID, Name: created.Name, CreatorID: created.CreatorID, ParentID: created.ParentID}
 	render.JSON(w, r, groupResponse)
 }
 
 // @Summary		Get all groups
-// @Description	Retrieve a list of all groups
+// @Description	Retrieve a list of all groups (administrators only)
 // @Tags		groups
 // @Produce		json
 // @Success		200	{array}	models.GroupResponse
-// @Failure 	500 {object} 	http.Error
+// @Failure 	403 {string} 	string
+// @Failure 	500 {string} 	string
 // @Security 	BearerAuth
 // @Router		/group/groups [get]
 func (config *GroupConfig) GetAllGroups(w http.ResponseWriter, r *http.Request) {
@@ -67,6 +81,7 @@ func (config *GroupConfig) GetAllGroups(w http.ResponseWriter, r *http.Request)
 			ID:        group.ID,
 			Name:      group.Name,
 			CreatorID: group.CreatorID,
+			ParentID:  group.ParentID,
 		})
 	}
 	render.JSON(w, r, GroupResponse)
@@ -78,8 +93,9 @@ func (config *GroupConfig) GetAllGroups(w http.ResponseWriter, r *http.Request)
 // @Produce		json
 // @Param		id	path	int	true	"Group ID"
 // @Success		200	{object}	models.GroupResponse
-// @Failure 	400 {object} 	http.Error
-// @Failure 	500 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
 // @Security 	BearerAuth
 // @Router		/group/{id} [get]
 func (config *GroupConfig) GetGroupByID(w http.ResponseWriter, r *http.Request) {
@@ -98,7 +114,11 @@ func (config *GroupConfig) GetGroupByID(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Failed to retrieve group", http.StatusInternalServerError)
 		return
 	}
-	groupResponse := &models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID}
+	if visible, err := authorization.CanSeeGroup(config.GroupRepository, authorization.UserFromContext(r.Context()), group.ID); err != nil || !visible {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	groupResponse := &models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID, ParentID: group.ParentID}
 	render.JSON(w, r, groupResponse)
 }
 
@@ -108,8 +128,9 @@ func (config *GroupConfig) GetGroupByID(w http.ResponseWriter, r *http.Request)
 // @Produce		json
 // @Param		id	path	int	true	"Creator ID"
 // @Success		200	{object}	models.GroupResponse
-// @Failure 	400 {object} 	http.Error
-// @Failure 	500 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
 // @Security 	BearerAuth
 // @Router		/group/creator/{id} [get]
 func (config *GroupConfig) GetGroupByCreatorID(w http.ResponseWriter, r *http.Request) {
@@ -128,20 +149,26 @@ func (config *GroupConfig) GetGroupByCreatorID(w http.ResponseWriter, r *http.Re
 		http.Error(w, "Failed to retrieve group", http.StatusInternalServerError)
 		return
 	}
-	groupResponse := &models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID}
+	if visible, err := authorization.CanSeeGroup(config.GroupRepository, authorization.UserFromContext(r.Context()), group.ID); err != nil || !visible {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	groupResponse := &models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID, ParentID: group.ParentID}
 	render.JSON(w, r, groupResponse)
 }
 
 // @Summary		Update a group
-// @Description	Update a group by its ID with the provided name and creator ID
+// @Description	Rename a group. Group admins and the owner may do so; the creator ID must stay the same.
 // @Tags		groups
 // @Accept		json
 // @Produce		json
 // @Param		id	path	int	true	"Group ID"
 // @Param		request	body	models.GroupRequest	true	"Group update data"
 // @Success		200	{object}	models.GroupResponse
-// @Failure 	400 {object} 	http.Error
-// @Failure 	500 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
 // @Security 	BearerAuth
 // @Router		/group/{id} [put]
 func (config *GroupConfig) Updategroup(w http.ResponseWriter, r *http.Request) {
@@ -161,6 +188,14 @@ func (config *GroupConfig) Updategroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	existing, _, ok := config.groupWith(w, r, uint(id), authorization.RenameGroup)
+	if !ok {
+		return
+	}
+	if req.CreatorID != existing.CreatorID {
+		http.Error(w, "creator_id cannot be changed, transfer the ownership instead", http.StatusBadRequest)
+		return
+	}
 
 	group := &dbmodel.Group{Name: req.Name, CreatorID: req.CreatorID}
 	updated, err := config.GroupRepository.UpdateByID(uint(id), group)
@@ -168,19 +203,23 @@ func (config *GroupConfig) Updategroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to update group", http.StatusInternalServerError)
 		return
 	}
+	updated.ID, updated.ParentID = uint(id), existing.ParentID
+	config.Events.Publish(events.GroupEvent(events.ActionUpdated, updated))
 
-	groupResponse := &models.GroupResponse{ID: uint(id), Name: updated.Name, CreatorID: updated.CreatorID}
+	groupResponse := &models.GroupResponse{ID: uint(id), Name: updated.Name, CreatorID: updated.CreatorID, ParentID: updated.ParentID}
 	render.JSON(w, r, groupResponse)
 }
 
 // @Summary		Delete a group
-// @Description	Delete a group by its ID
+// @Description	Delete a group by its ID. Only the owner may do so.
 // @Tags		groups
 // @Produce		json
 // @Param		id	path	int	true	"Group ID"
 // @Success		200	{string}	string	"Successfully deleted entry"
-// @Failure 	400 {object} 	http.Error
-// @Failure 	500 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
 // @Security 	BearerAuth
 // @Router		/group/{id} [delete]
 func (config *GroupConfig) DeleteGroupHandler(w http.ResponseWriter, r *http.Request) {
@@ -193,10 +232,40 @@ func (config *GroupConfig) DeleteGroupHandler(w http.ResponseWriter, r *http.Req
 		http.Error(w, "id must be >= 1", http.StatusBadRequest)
 		return
 	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.DeleteGroup)
+	if !ok {
+		return
+	}
 	err = config.GroupRepository.DeleteByID(uint(id))
 	if err != nil {
 		http.Error(w, "Failed to delete group", http.StatusInternalServerError)
 		return
 	}
+	config.Events.Publish(events.GroupEvent(events.ActionDeleted, group))
 	render.JSON(w, r, "Succefully deleted entry")
 }
+
+// groupWith returns a group along with the caller's role in it, or writes
+// why the caller may not act on it: 404 when they are not a member, 403
+// when their role lacks permission.
+func (config *GroupConfig) groupWith(w http.ResponseWriter, r *http.Request, id uint, permission authorization.GroupPermission) (*dbmodel.Group, string, bool) {
+	group, err := config.GroupRepository.FindByID(id)
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return nil, "", false
+	}
+	role, err := authorization.GroupRole(config.UserGroupRepository, authorization.UserFromContext(r.Context()), group)
+	if err != nil {
+		http.Error(w, "Failed to retrieve membership", http.StatusInternalServerError)
+		return nil, "", false
+	}
+	if role == "" {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return nil, "", false
+	}
+	if !authorization.RoleCan(role, permission) {
+		http.Error(w, "Your role in this group does not allow this", http.StatusForbidden)
+		return nil, "", false
+	}
+	return group, role, true
+}
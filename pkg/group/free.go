@@ -0,0 +1,90 @@
+package group
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/freebusy"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultFreeRange = 7 * 24 * time.Hour
+	maxFreeRange     = 62 * 24 * time.Hour
+)
+
+// @Summary		Find free slots in a group
+// @Description	List the periods between from and to in which no member of the group has a date, lasting at least the given number of minutes. The range defaults to the next 7 days and may not exceed 62 days. The caller must belong to the group, guests included.
+// @Tags		groups
+// @Produce		json
+// @Param		id		path	int		true	"Group ID"
+// @Param		from	query	string	false	"Start of the range (RFC 3339), now by default"
+// @Param		to		query	string	false	"End of the range (RFC 3339), 7 days after from by default"
+// @Param		minutes	query	int		false	"Minimum length of a slot in minutes"
+// @Success		200	{array}		models.PeriodResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/free [get]
+func (config *GroupConfig) GetFreeSlots(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	if visible, err := authorization.CanSeeGroup(config.GroupRepository, user, uint(id)); err != nil || !visible {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	from, to := time.Now().UTC().Truncate(time.Minute), time.Time{}
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "from must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+	to = from.Add(defaultFreeRange)
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "to must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+	if !to.After(from) || to.Sub(from) > maxFreeRange {
+		http.Error(w, "to must be after from, by at most 62 days", http.StatusBadRequest)
+		return
+	}
+	minutes := 0
+	if value := query.Get("minutes"); value != "" {
+		if minutes, err = strconv.Atoi(value); err != nil || minutes < 0 {
+			http.Error(w, "minutes must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	memberIDs, err := config.GroupRepository.FindMemberIDs(uint(id))
+	if err != nil {
+		http.Error(w, "Failed to retrieve members", http.StatusInternalServerError)
+		return
+	}
+	dates, err := config.DateRepository.FindByUserIDsInRange(memberIDs, from, to)
+	if err != nil {
+		http.Error(w, "Failed to retrieve dates", http.StatusInternalServerError)
+		return
+	}
+	periodResponse := make([]models.PeriodResponse, 0)
+	for _, period := range freebusy.Free(freebusy.Busy(dates, from, to), from, to, time.Duration(minutes)*time.Minute) {
+		periodResponse = append(periodResponse, models.PeriodResponse{DateBegin: period.Begin, DateEnd: period.End})
+	}
+	render.JSON(w, r, periodResponse)
+}
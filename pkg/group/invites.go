@@ -0,0 +1,264 @@
+package group
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Invite codes leave out the letters and digits that are easy to confuse
+// when read aloud or copied from a board.
+const (
+	inviteAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteCodeLength = 8
+)
+
+// @Summary		Create an invite
+// @Description	Create an invite code that lets anyone holding it join the group through POST /group/join/{code}, as a member by default. max_uses 0 means unlimited and expires_at is optional. Group admins may create invites for roles below their own.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"Group ID"
+// @Param		request	body	models.GroupInviteRequest	true	"Invite settings"
+// @Success		200	{object}	models.GroupInviteResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/invites [post]
+func (config *GroupConfig) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.GroupInviteRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Role == "" {
+		req.Role = dbmodel.RoleMember
+	}
+	if !authorization.ValidRole(req.Role) || req.Role == dbmodel.RoleOwner {
+		http.Error(w, "role must be admin, member or guest", http.StatusBadRequest)
+		return
+	}
+
+	group, role, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	if !authorization.RoleAbove(role, req.Role) {
+		http.Error(w, "You can only invite with roles below your own", http.StatusForbidden)
+		return
+	}
+	code, err := generateInviteCode()
+	if err != nil {
+		http.Error(w, "Failed to generate invite code", http.StatusInternalServerError)
+		return
+	}
+	invite, err := config.InviteRepository.Create(&dbmodel.GroupInvite{
+		GroupID:   group.ID,
+		CreatorID: authorization.UserFromContext(r.Context()).ID,
+		Code:      code,
+		Role:      req.Role,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		http.Error(w, "Failed to create invite", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toInviteResponse(r, invite, nil))
+}
+
+// @Summary		List the invites of a group
+// @Description	List the invites of a group that were not revoked, with the users who joined through each of them. Only group admins and the owner may list them.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{array}		models.GroupInviteResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/invites [get]
+func (config *GroupConfig) GetInvites(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	invites, err := config.InviteRepository.FindByGroupID(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve invites", http.StatusInternalServerError)
+		return
+	}
+	userGroups, err := config.UserGroupRepository.FindByGroupID(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve members", http.StatusInternalServerError)
+		return
+	}
+	joined := make(map[uint][]uint)
+	for _, userGroup := range userGroups {
+		if userGroup.InviteID != 0 {
+			joined[userGroup.InviteID] = append(joined[userGroup.InviteID], userGroup.UserID)
+		}
+	}
+
+	inviteResponse := make([]models.GroupInviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		inviteResponse = append(inviteResponse, *toInviteResponse(r, &invite, joined[invite.ID]))
+	}
+	render.JSON(w, r, inviteResponse)
+}
+
+// @Summary		Revoke an invite
+// @Description	Revoke an invite of a group; its code stops working immediately. Members who joined through it stay in the group.
+// @Tags		groups
+// @Produce		json
+// @Param		id			path	int	true	"Group ID"
+// @Param		inviteID	path	int	true	"Invite ID"
+// @Success		200	{string}	string	"Successfully deleted entry"
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/invites/{inviteID} [delete]
+func (config *GroupConfig) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	inviteID, err := strconv.Atoi(chi.URLParam(r, "inviteID"))
+	if err != nil || inviteID < 1 {
+		http.Error(w, "Invalid invite ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	invite, err := config.InviteRepository.FindByID(uint(inviteID))
+	if err != nil || invite.GroupID != group.ID {
+		http.Error(w, "Invite not found", http.StatusNotFound)
+		return
+	}
+	if err := config.InviteRepository.DeleteByID(invite.ID); err != nil {
+		http.Error(w, "Failed to revoke invite", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, "Succefully deleted entry")
+}
+
+// @Summary		Join a group with an invite code
+// @Description	Join the group of an invite with the role it grants. UNVERIFIED_RESTRICTIONS may require a verified email.
+// @Tags		groups
+// @Produce		json
+// @Param		code	path	string	true	"Invite code"
+// @Success		200	{object}	models.MembershipResponse
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	410 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/join/{code} [post]
+func (config *GroupConfig) JoinGroup(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	if !authorization.VerificationAllows(user, authorization.JoinGroups) {
+		http.Error(w, "Verify your email to join groups", http.StatusForbidden)
+		return
+	}
+	invite, err := config.InviteRepository.FindByCode(chi.URLParam(r, "code"))
+	if err != nil {
+		http.Error(w, "Invite not found", http.StatusNotFound)
+		return
+	}
+	group, err := config.GroupRepository.FindByID(invite.GroupID)
+	if err != nil {
+		http.Error(w, "Invite not found", http.StatusNotFound)
+		return
+	}
+	if invite.ExpiresAt != nil && !invite.ExpiresAt.After(time.Now()) {
+		http.Error(w, "This invite has expired", http.StatusGone)
+		return
+	}
+	if member, err := config.isDirectMember(group.ID, user.ID); err != nil || member {
+		http.Error(w, "You are already a member of this group", http.StatusConflict)
+		return
+	}
+
+	userGroup, err := config.InviteRepository.Redeem(invite, user.ID)
+	if errors.Is(err, dbmodel.ErrInviteUsedUp) {
+		http.Error(w, "This invite has been used up", http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to join group", http.StatusInternalServerError)
+		return
+	}
+	config.Events.Publish(events.MembershipEvent(events.ActionCreated, userGroup))
+	render.JSON(w, r, toMembershipResponse(userGroup))
+}
+
+func generateInviteCode() (string, error) {
+	buffer := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	for i, b := range buffer {
+		buffer[i] = inviteAlphabet[int(b)%len(inviteAlphabet)]
+	}
+	return string(buffer), nil
+}
+
+// inviteURL is the address an invite code is redeemed at.
+func inviteURL(r *http.Request, code string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/api/group/join/" + code
+}
+
+func toInviteResponse(r *http.Request, invite *dbmodel.GroupInvite, joinedUserIDs []uint) *models.GroupInviteResponse {
+	if joinedUserIDs == nil {
+		joinedUserIDs = []uint{}
+	}
+	expired := invite.ExpiresAt != nil && !invite.ExpiresAt.After(time.Now())
+	usedUp := invite.MaxUses > 0 && invite.Uses >= invite.MaxUses
+	return &models.GroupInviteResponse{
+		ID:            invite.ID,
+		GroupID:       invite.GroupID,
+		CreatorID:     invite.CreatorID,
+		Code:          invite.Code,
+		URL:           inviteURL(r, invite.Code),
+		Role:          invite.Role,
+		MaxUses:       invite.MaxUses,
+		Uses:          invite.Uses,
+		ExpiresAt:     invite.ExpiresAt,
+		CreatedAt:     invite.CreatedAt,
+		Active:        !expired && !usedUp,
+		JoinedUserIDs: joinedUserIDs,
+	}
+}
@@ -0,0 +1,334 @@
+package group
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// @Summary		List my groups
+// @Description	List the groups the caller belongs to, with their role and the color they picked in each. The ancestors of their groups are listed too, with the role inherited from them.
+// @Tags		groups
+// @Produce		json
+// @Success		200	{array}		models.MyGroupResponse
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/mine [get]
+func (config *GroupConfig) GetMyGroups(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	groups, err := config.GroupRepository.FindByMemberID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	userGroups, err := config.UserGroupRepository.FindByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve memberships", http.StatusInternalServerError)
+		return
+	}
+	memberships := make(map[uint]dbmodel.UserGroup, len(userGroups))
+	for _, userGroup := range userGroups {
+		memberships[userGroup.GroupID] = userGroup
+	}
+
+	myGroupResponse := make([]models.MyGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		membership, direct := memberships[group.ID]
+		role := membership.Role
+		if group.CreatorID == user.ID {
+			role = dbmodel.RoleOwner
+		} else if !direct {
+			// Ancestors of the caller's groups, which they belong to implicitly
+			if role, err = authorization.GroupRole(config.UserGroupRepository, user, &group); err != nil {
+				http.Error(w, "Failed to retrieve memberships", http.StatusInternalServerError)
+				return
+			}
+		}
+		myGroupResponse = append(myGroupResponse, models.MyGroupResponse{
+			ID:        group.ID,
+			Name:      group.Name,
+			CreatorID: group.CreatorID,
+			Role:      role,
+			ColorID:   membership.ColorID,
+		})
+	}
+	render.JSON(w, r, myGroupResponse)
+}
+
+// @Summary		List the members of a group
+// @Description	List the members of a group, the owner first. Guests may not list them.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{array}		models.MembershipResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/members [get]
+func (config *GroupConfig) GetMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.SeeMembers)
+	if !ok {
+		return
+	}
+	userGroups, err := config.UserGroupRepository.FindByGroupID(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve members", http.StatusInternalServerError)
+		return
+	}
+
+	membershipResponse := make([]models.MembershipResponse, 0, len(userGroups))
+	for _, userGroup := range userGroups {
+		if userGroup.UserID == group.CreatorID {
+			userGroup.Role = dbmodel.RoleOwner
+			membershipResponse = append([]models.MembershipResponse{*toMembershipResponse(&userGroup)}, membershipResponse...)
+			continue
+		}
+		membershipResponse = append(membershipResponse, *toMembershipResponse(&userGroup))
+	}
+	render.JSON(w, r, membershipResponse)
+}
+
+// @Summary		Add a member to a group
+// @Description	Add an existing user to a group as an admin, member (the default) or guest. Group admins may add members and guests; only the owner may add admins. UNVERIFIED_RESTRICTIONS may require the user to have verified their email.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int						true	"Group ID"
+// @Param		request	body	models.AddMemberRequest	true	"New member"
+// @Success		200	{object}	models.MembershipResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/members [post]
+func (config *GroupConfig) AddMember(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.AddMemberRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Role == "" {
+		req.Role = dbmodel.RoleMember
+	}
+	if !authorization.ValidRole(req.Role) || req.Role == dbmodel.RoleOwner {
+		http.Error(w, "role must be admin, member or guest", http.StatusBadRequest)
+		return
+	}
+
+	group, role, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	if !authorization.RoleAbove(role, req.Role) {
+		http.Error(w, "You can only give roles below your own", http.StatusForbidden)
+		return
+	}
+	member, err := config.UserRepository.FindByID(req.UserID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if !authorization.VerificationAllows(member, authorization.BeInvited) {
+		http.Error(w, "This user has not verified their email", http.StatusConflict)
+		return
+	}
+	if req.ColorID != 0 {
+		if _, err := config.ColorRepository.FindByID(req.ColorID); err != nil {
+			http.Error(w, "Color not found", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.UserID == group.CreatorID {
+		http.Error(w, "This user is already a member of the group", http.StatusConflict)
+		return
+	}
+	if _, err := config.UserGroupRepository.FindByUserIDAndGroupID(req.UserID, group.ID); err == nil {
+		http.Error(w, "This user is already a member of the group", http.StatusConflict)
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Failed to retrieve member", http.StatusInternalServerError)
+		return
+	}
+
+	userGroup, err := config.UserGroupRepository.Create(&dbmodel.UserGroup{UserID: req.UserID, GroupID: group.ID, ColorID: req.ColorID, Role: req.Role})
+	if err != nil {
+		http.Error(w, "Failed to add member", http.StatusInternalServerError)
+		return
+	}
+	config.Events.Publish(events.MembershipEvent(events.ActionCreated, userGroup))
+	render.JSON(w, r, toMembershipResponse(userGroup))
+}
+
+// @Summary		Remove a member from a group
+// @Description	Remove a member from a group. Group admins may remove members and guests; only the owner may remove admins. The owner cannot be removed.
+// @Tags		groups
+// @Produce		json
+// @Param		id		path	int	true	"Group ID"
+// @Param		userID	path	int	true	"User ID"
+// @Success		200	{string}	string	"Successfully deleted entry"
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/members/{userID} [delete]
+func (config *GroupConfig) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil || userID < 1 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	group, role, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	userGroup, ok := config.membership(w, uint(userID), group)
+	if !ok {
+		return
+	}
+	if !authorization.RoleAbove(role, userGroup.Role) {
+		http.Error(w, "You can only remove members below you", http.StatusForbidden)
+		return
+	}
+	config.deleteMembership(w, r, userGroup)
+}
+
+// @Summary		Leave a group
+// @Description	Leave a group the caller belongs to. The owner must transfer the ownership first.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{string}	string	"Successfully deleted entry"
+// @Failure 	400 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/leave [post]
+func (config *GroupConfig) LeaveGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, err := config.GroupRepository.FindByID(uint(id))
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	userGroup, ok := config.membership(w, authorization.UserFromContext(r.Context()).ID, group)
+	if !ok {
+		return
+	}
+	config.deleteMembership(w, r, userGroup)
+}
+
+// @Summary		Set my color in a group
+// @Description	Set the color the caller uses for a group they belong to; 0 clears it.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"Group ID"
+// @Param		request	body	models.MemberColorRequest	true	"Color"
+// @Success		200	{object}	models.MembershipResponse
+// @Failure 	400 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/color [put]
+func (config *GroupConfig) SetMyColor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.MemberColorRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ColorID != 0 {
+		if _, err := config.ColorRepository.FindByID(req.ColorID); err != nil {
+			http.Error(w, "Color not found", http.StatusBadRequest)
+			return
+		}
+	}
+
+	userID := authorization.UserFromContext(r.Context()).ID
+	userGroup, err := config.UserGroupRepository.FindByUserIDAndGroupID(userID, uint(id))
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	if err := config.UserGroupRepository.UpdateColorByUserIDAndGroupID(userID, uint(id), req.ColorID); err != nil {
+		http.Error(w, "Failed to update color", http.StatusInternalServerError)
+		return
+	}
+	userGroup.ColorID = req.ColorID
+	config.Events.Publish(events.MembershipEvent(events.ActionUpdated, userGroup))
+	render.JSON(w, r, toMembershipResponse(userGroup))
+}
+
+// membership returns the membership of userID in a group, or writes why it
+// cannot be removed: 404 when there is none, 409 for the owner's.
+func (config *GroupConfig) membership(w http.ResponseWriter, userID uint, group *dbmodel.Group) (*dbmodel.UserGroup, bool) {
+	if userID == group.CreatorID {
+		http.Error(w, "The owner cannot leave the group before transferring the ownership", http.StatusConflict)
+		return nil, false
+	}
+	userGroup, err := config.UserGroupRepository.FindByUserIDAndGroupID(userID, group.ID)
+	if err != nil {
+		http.Error(w, "Member not found", http.StatusNotFound)
+		return nil, false
+	}
+	return userGroup, true
+}
+
+func (config *GroupConfig) deleteMembership(w http.ResponseWriter, r *http.Request, userGroup *dbmodel.UserGroup) {
+	if err := config.UserGroupRepository.DeleteByUserIDAndGroupID(userGroup.UserID, userGroup.GroupID); err != nil {
+		http.Error(w, "Failed to remove member", http.StatusInternalServerError)
+		return
+	}
+	config.Events.Publish(events.MembershipEvent(events.ActionDeleted, userGroup))
+	render.JSON(w, r, "Succefully deleted entry")
+}
+
+// isDirectMember reports whether userID was added to the group itself,
+// rather than belonging to it through a nested group.
+func (config *GroupConfig) isDirectMember(groupID uint, userID uint) (bool, error) {
+	_, err := config.UserGroupRepository.FindByUserIDAndGroupID(userID, groupID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,362 @@
+package group
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// @Summary		Discover groups
+// @Description	List the discoverable groups, whose name contains name when it is given. Anyone may ask to join them.
+// @Tags		groups
+// @Produce		json
+// @Param		name	query	string	false	"Part of the group name"
+// @Success		200	{array}		models.DiscoverableGroupResponse
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/discover [get]
+func (config *GroupConfig) DiscoverGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := config.GroupRepository.FindDiscoverable(r.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	discoverableGroupResponse := make([]models.DiscoverableGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		discoverableGroupResponse = append(discoverableGroupResponse, models.DiscoverableGroupResponse{ID: group.ID, Name: group.Name})
+	}
+	render.JSON(w, r, discoverableGroupResponse)
+}
+
+// @Summary		Get the settings of a group
+// @Description	Get whether a group is discoverable and the email domains whose join requests are approved automatically. Only group admins and the owner may see them.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{object}	models.GroupSettingsResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/settings [get]
+func (config *GroupConfig) GetSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.RenameGroup)
+	if !ok {
+		return
+	}
+	render.JSON(w, r, toSettingsResponse(group))
+}
+
+// @Summary		Change the settings of a group
+// @Description	Make a group discoverable or not, and set the email domains whose join requests are approved automatically. Only group admins and the owner may do so.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"Group ID"
+// @Param		request	body	models.GroupSettingsRequest	true	"Settings"
+// @Success		200	{object}	models.GroupSettingsResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/settings [put]
+func (config *GroupConfig) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.GroupSettingsRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.RenameGroup)
+	if !ok {
+		return
+	}
+	domains := make([]string, 0, len(req.AutoApproveDomains))
+	for _, domain := range req.AutoApproveDomains {
+		domains = append(domains, strings.ToLower(domain))
+	}
+	group.Discoverable, group.AutoApproveDomains = req.Discoverable, strings.Join(domains, ",")
+	if err := config.GroupRepository.UpdateSettingsByID(group.ID, group.Discoverable, group.AutoApproveDomains); err != nil {
+		http.Error(w, "Failed to update settings", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toSettingsResponse(group))
+}
+
+// @Summary		Ask to join a group
+// @Description	Ask to join a discoverable group. The request is approved at once when the caller's verified email domain is one the group approves automatically; otherwise it waits for a group admin. UNVERIFIED_RESTRICTIONS may require a verified email.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"Group ID"
+// @Param		request	body	models.JoinRequestRequest	true	"Message to the group admins"
+// @Success		200	{object}	models.JoinRequestResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/requests [post]
+func (config *GroupConfig) RequestToJoin(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.JoinRequestRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	group, err := config.GroupRepository.FindByID(uint(id))
+	if err != nil || !group.Discoverable {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	if !authorization.VerificationAllows(user, authorization.JoinGroups) {
+		http.Error(w, "Verify your email to join groups", http.StatusForbidden)
+		return
+	}
+	if member, err := config.isDirectMember(group.ID, user.ID); err != nil || member {
+		http.Error(w, "You are already a member of this group", http.StatusConflict)
+		return
+	}
+	if _, err := config.JoinRequestRepository.FindPendingByUserIDAndGroupID(user.ID, group.ID); err == nil {
+		http.Error(w, "You already asked to join this group", http.StatusConflict)
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Failed to retrieve requests", http.StatusInternalServerError)
+		return
+	}
+
+	joinRequest, err := config.JoinRequestRepository.Create(&dbmodel.JoinRequest{GroupID: group.ID, UserID: user.ID, Message: req.Message, Status: dbmodel.JoinRequestPending})
+	if err != nil {
+		http.Error(w, "Failed to create request", http.StatusInternalServerError)
+		return
+	}
+	// The domain of an unverified email proves nothing
+	if user.Verified && autoApproved(group, user.Email) {
+		userGroup, err := config.JoinRequestRepository.Approve(joinRequest, 0)
+		if err != nil {
+			http.Error(w, "Failed to approve request", http.StatusInternalServerError)
+			return
+		}
+		config.Events.Publish(events.MembershipEvent(events.ActionCreated, userGroup))
+	}
+	render.JSON(w, r, toJoinRequestResponse(joinRequest))
+}
+
+// @Summary		List my join requests
+// @Description	List the caller's requests to join groups, whatever their status.
+// @Tags		groups
+// @Produce		json
+// @Success		200	{array}		models.JoinRequestResponse
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/requests/mine [get]
+func (config *GroupConfig) GetMyJoinRequests(w http.ResponseWriter, r *http.Request) {
+	joinRequests, err := config.JoinRequestRepository.FindByUserID(authorization.UserFromContext(r.Context()).ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve requests", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toJoinRequestResponses(joinRequests))
+}
+
+// @Summary		List pending join requests
+// @Description	List the requests to join a group that wait for a decision. Only group admins and the owner may list them.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{array}		models.JoinRequestResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/requests [get]
+func (config *GroupConfig) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	joinRequests, err := config.JoinRequestRepository.FindPendingByGroupID(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve requests", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toJoinRequestResponses(joinRequests))
+}
+
+// @Summary		Approve a join request
+// @Description	Approve a pending request to join a group; its user becomes a member. Only group admins and the owner may do so.
+// @Tags		groups
+// @Produce		json
+// @Param		id			path	int	true	"Group ID"
+// @Param		requestID	path	int	true	"Join request ID"
+// @Success		200	{object}	models.JoinRequestResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/requests/{requestID}/approve [post]
+func (config *GroupConfig) ApproveJoinRequest(w http.ResponseWriter, r *http.Request) {
+	group, joinRequest, ok := config.pendingJoinRequest(w, r)
+	if !ok {
+		return
+	}
+	if member, err := config.isDirectMember(group.ID, joinRequest.UserID); err != nil || member {
+		http.Error(w, "This user is already a member of the group", http.StatusConflict)
+		return
+	}
+	requester, err := config.UserRepository.FindByID(joinRequest.UserID)
+	if err != nil {
+		http.Error(w, "Join request not found", http.StatusNotFound)
+		return
+	}
+	if !authorization.VerificationAllows(requester, authorization.BeInvited) {
+		http.Error(w, "This user has not verified their email", http.StatusConflict)
+		return
+	}
+	userGroup, err := config.JoinRequestRepository.Approve(joinRequest, authorization.UserFromContext(r.Context()).ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Join request not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to approve request", http.StatusInternalServerError)
+		return
+	}
+	config.Events.Publish(events.MembershipEvent(events.ActionCreated, userGroup))
+	render.JSON(w, r, toJoinRequestResponse(joinRequest))
+}
+
+// @Summary		Reject a join request
+// @Description	Reject a pending request to join a group. Only group admins and the owner may do so.
+// @Tags		groups
+// @Produce		json
+// @Param		id			path	int	true	"Group ID"
+// @Param		requestID	path	int	true	"Join request ID"
+// @Success		200	{object}	models.JoinRequestResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/requests/{requestID}/reject [post]
+func (config *GroupConfig) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	_, joinRequest, ok := config.pendingJoinRequest(w, r)
+	if !ok {
+		return
+	}
+	err := config.JoinRequestRepository.Reject(joinRequest, authorization.UserFromContext(r.Context()).ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Join request not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to reject request", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toJoinRequestResponse(joinRequest))
+}
+
+// pendingJoinRequest returns the pending join request named in the URL if
+// the caller may decide on it, or writes why not.
+func (config *GroupConfig) pendingJoinRequest(w http.ResponseWriter, r *http.Request) (*dbmodel.Group, *dbmodel.JoinRequest, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	requestID, err := strconv.Atoi(chi.URLParam(r, "requestID"))
+	if err != nil || requestID < 1 {
+		http.Error(w, "Invalid join request ID", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return nil, nil, false
+	}
+	joinRequest, err := config.JoinRequestRepository.FindByID(uint(requestID))
+	if err != nil || joinRequest.GroupID != group.ID || joinRequest.Status != dbmodel.JoinRequestPending {
+		http.Error(w, "Join request not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	return group, joinRequest, true
+}
+
+// autoApproved reports whether the domain of email is one whose users join
+// the group without approval.
+func autoApproved(group *dbmodel.Group, email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || group.AutoApproveDomains == "" {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range strings.Split(group.AutoApproveDomains, ",") {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func toSettingsResponse(group *dbmodel.Group) *models.GroupSettingsResponse {
+	domains := []string{}
+	if group.AutoApproveDomains != "" {
+		domains = strings.Split(group.AutoApproveDomains, ",")
+	}
+	return &models.GroupSettingsResponse{ID: group.ID, Discoverable: group.Discoverable, AutoApproveDomains: domains}
+}
+
+func toJoinRequestResponse(joinRequest *dbmodel.JoinRequest) *models.JoinRequestResponse {
+	return &models.JoinRequestResponse{
+		ID:        joinRequest.ID,
+		GroupID:   joinRequest.GroupID,
+		UserID:    joinRequest.UserID,
+		Message:   joinRequest.Message,
+		Status:    joinRequest.Status,
+		DecidedBy: joinRequest.DecidedBy,
+		DecidedAt: joinRequest.DecidedAt,
+		CreatedAt: joinRequest.CreatedAt,
+	}
+}
+
+func toJoinRequestResponses(joinRequests []dbmodel.JoinRequest) []models.JoinRequestResponse {
+	joinRequestResponse := make([]models.JoinRequestResponse, 0, len(joinRequests))
+	for _, joinRequest := range joinRequests {
+		joinRequestResponse = append(joinRequestResponse, *toJoinRequestResponse(&joinRequest))
+	}
+	return joinRequestResponse
+}
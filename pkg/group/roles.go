@@ -0,0 +1,153 @@
+package group
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// @Summary		Change the role of a member
+// @Description	Set a member's role to admin, member or guest. Group admins may change the role of members and guests; only the owner may grant or revoke admin. The owner's role only changes through an ownership transfer.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int						true	"Group ID"
+// @Param		userID	path	int						true	"User ID"
+// @Param		request	body	models.GroupRoleRequest	true	"New role"
+// @Success		200	{object}	models.MembershipResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/members/{userID}/role [put]
+func (config *GroupConfig) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil || userID < 1 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.GroupRoleRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !authorization.ValidRole(req.Role) || req.Role == dbmodel.RoleOwner {
+		http.Error(w, "role must be admin, member or guest", http.StatusBadRequest)
+		return
+	}
+
+	group, role, ok := config.groupWith(w, r, uint(id), authorization.ManageMembers)
+	if !ok {
+		return
+	}
+	userGroup, err := config.UserGroupRepository.FindByUserIDAndGroupID(uint(userID), group.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Member not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to retrieve member", http.StatusInternalServerError)
+		return
+	}
+	if group.CreatorID == userGroup.UserID || userGroup.Role == dbmodel.RoleOwner {
+		http.Error(w, "The owner keeps their role until they transfer the ownership", http.StatusConflict)
+		return
+	}
+	if !authorization.RoleAbove(role, userGroup.Role) || !authorization.RoleAbove(role, req.Role) {
+		http.Error(w, "You can only give roles below your own to members below you", http.StatusForbidden)
+		return
+	}
+
+	if err := config.UserGroupRepository.UpdateRoleByUserIDAndGroupID(userGroup.UserID, group.ID, req.Role); err != nil {
+		http.Error(w, "Failed to update role", http.StatusInternalServerError)
+		return
+	}
+	userGroup.Role = req.Role
+	config.Events.Publish(events.MembershipEvent(events.ActionUpdated, userGroup))
+	render.JSON(w, r, toMembershipResponse(userGroup))
+}
+
+// @Summary		Transfer the ownership of a group
+// @Description	Make another member the owner of the group. The previous owner stays in the group as an admin. Only the owner may do so.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int								true	"Group ID"
+// @Param		request	body	models.TransferOwnershipRequest	true	"New owner"
+// @Success		200	{object}	models.GroupResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/transfer [post]
+func (config *GroupConfig) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.TransferOwnershipRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.DeleteGroup)
+	if !ok {
+		return
+	}
+	if req.UserID == group.CreatorID {
+		http.Error(w, "This user already owns the group", http.StatusBadRequest)
+		return
+	}
+	newOwner, err := config.UserGroupRepository.FindByUserIDAndGroupID(req.UserID, group.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "The new owner must already be a member of the group", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to retrieve member", http.StatusInternalServerError)
+		return
+	}
+
+	previousOwnerID := group.CreatorID
+	if err := config.UserGroupRepository.TransferOwnership(group.ID, previousOwnerID, newOwner.UserID); err != nil {
+		http.Error(w, "Failed to transfer ownership", http.StatusInternalServerError)
+		return
+	}
+	group.CreatorID = newOwner.UserID
+	config.Events.Publish(events.GroupEvent(events.ActionUpdated, group))
+	newOwner.Role = dbmodel.RoleOwner
+	config.Events.Publish(events.MembershipEvent(events.ActionUpdated, newOwner))
+	if previousOwner, err := config.UserGroupRepository.FindByUserIDAndGroupID(previousOwnerID, group.ID); err == nil {
+		config.Events.Publish(events.MembershipEvent(events.ActionUpdated, previousOwner))
+	}
+	render.JSON(w, r, &models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID, ParentID: group.ParentID})
+}
+
+func toMembershipResponse(userGroup *dbmodel.UserGroup) *models.MembershipResponse {
+	return &models.MembershipResponse{
+		UserID:   userGroup.UserID,
+		GroupID:  userGroup.GroupID,
+		ColorID:  userGroup.ColorID,
+		Role:     userGroup.Role,
+		InviteID: userGroup.InviteID,
+	}
+}
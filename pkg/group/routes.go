@@ -2,6 +2,7 @@ package group
 
 import (
 	"yplanning/config"
+	"yplanning/pkg/authorization"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -9,21 +10,69 @@ import (
 /*
 group routes:
 POST /groups - Create a new group
-GET /groups - Get all groups (for testing purposes, remove later)
+GET /groups - Get all groups (administrators only)
 GET /groups/{id} - Get a group by ID
 GET /groups/creator/{id} - Get groups by creator ID
-PUT /groups/{id} - Update a group by ID
-DELETE /groups/{id} - Delete a group by ID
+GET /groups/mine - Get the groups of the caller with their role and color
+GET /groups/{id}/free - Find the free slots of a group
+PUT /groups/{id} - Rename a group (admins and owner)
+DELETE /groups/{id} - Delete a group by ID (owner)
+PUT /groups/{id}/members/{userID}/role - Change the role of a member (admins and owner)
+POST /groups/{id}/transfer - Transfer the ownership of a group (owner)
+GET /groups/{id}/members - List the members of a group (not guests)
+POST /groups/{id}/members - Add a member to a group (admins and owner)
+DELETE /groups/{id}/members/{userID} - Remove a member from a group (admins and owner)
+POST /groups/{id}/leave - Leave a group
+PUT /groups/{id}/color - Set the caller's color in a group
+POST /groups/{id}/invites - Create an invite code (admins and owner)
+GET /groups/{id}/invites - List the invites of a group and who joined through them (admins and owner)
+DELETE /groups/{id}/invites/{inviteID} - Revoke an invite (admins and owner)
+POST /groups/join/{code} - Join a group with an invite code
+GET /groups/discover - List the discoverable groups
+GET /groups/{id}/settings - Get whether a group is discoverable and its auto-approved domains (admins and owner)
+PUT /groups/{id}/settings - Change them (admins and owner)
+POST /groups/{id}/requests - Ask to join a discoverable group
+GET /groups/requests/mine - List the caller's join requests
+GET /groups/{id}/requests - List the pending join requests (admins and owner)
+POST /groups/{id}/requests/{requestID}/approve - Approve a join request (admins and owner)
+POST /groups/{id}/requests/{requestID}/reject - Reject a join request (admins and owner)
+PUT /groups/{id}/parent - Nest a group in another one, or make it a root (owner)
+GET /groups/{id}/tree - Get a group with the groups nested in it
+GET /groups/{id}/members/effective - List the members of a group and of its descendants (not guests)
 */
 
 func Routes(config *config.Config) chi.Router {
 	GroupConfig := NewGroupConfig(config)
 	router := chi.NewRouter()
 	router.Post("/", GroupConfig.CreateGroup)
-	router.Get("/groups", GroupConfig.GetAllGroups) // FOR TESTING PURPOSES ONLY, REMOVE LATER
+	router.With(authorization.RequireAdmin).Get("/groups", GroupConfig.GetAllGroups)
 	router.Get("/{id}", GroupConfig.GetGroupByID)
 	router.Get("/creator/{id}", GroupConfig.GetGroupByCreatorID)
+	router.Get("/mine", GroupConfig.GetMyGroups)
+	router.Get("/{id}/free", GroupConfig.GetFreeSlots)
 	router.Put("/{id}", GroupConfig.Updategroup)
 	router.Delete("/{id}", GroupConfig.DeleteGroupHandler)
+	router.Put("/{id}/members/{userID}/role", GroupConfig.UpdateMemberRole)
+	router.Post("/{id}/transfer", GroupConfig.TransferOwnership)
+	router.Get("/{id}/members", GroupConfig.GetMembers)
+	router.Post("/{id}/members", GroupConfig.AddMember)
+	router.Delete("/{id}/members/{userID}", GroupConfig.RemoveMember)
+	router.Post("/{id}/leave", GroupConfig.LeaveGroup)
+	router.Put("/{id}/color", GroupConfig.SetMyColor)
+	router.Post("/{id}/invites", GroupConfig.CreateInvite)
+	router.Get("/{id}/invites", GroupConfig.GetInvites)
+	router.Delete("/{id}/invites/{inviteID}", GroupConfig.RevokeInvite)
+	router.Post("/join/{code}", GroupConfig.JoinGroup)
+	router.Get("/discover", GroupConfig.DiscoverGroups)
+	router.Get("/{id}/settings", GroupConfig.GetSettings)
+	router.Put("/{id}/settings", GroupConfig.UpdateSettings)
+	router.Post("/{id}/requests", GroupConfig.RequestToJoin)
+	router.Get("/requests/mine", GroupConfig.GetMyJoinRequests)
+	router.Get("/{id}/requests", GroupConfig.GetJoinRequests)
+	router.Post("/{id}/requests/{requestID}/approve", GroupConfig.ApproveJoinRequest)
+	router.Post("/{id}/requests/{requestID}/reject", GroupConfig.RejectJoinRequest)
+	router.Put("/{id}/parent", GroupConfig.SetParent)
+	router.Get("/{id}/tree", GroupConfig.GetTree)
+	router.Get("/{id}/members/effective", GroupConfig.GetEffectiveMembers)
 	return router
 }
@@ -0,0 +1,222 @@
+package group
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Nest a group in another one
+// @Description	Set the parent of a group, or make it a root with a null parent_id. The members of a group are implicitly members of all its ancestors, as members at most. Only the owner may move a group, and they must be an admin of the new parent; admins of the current parent may also detach it. A group cannot be nested in itself or its descendants.
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"Group ID"
+// @Param		request	body	models.GroupParentRequest	true	"New parent"
+// @Success		200	{object}	models.GroupResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	409 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/parent [put]
+func (config *GroupConfig) SetParent(w http.ResponseWriter, r *http.Request) {
+	user := authorization.UserFromContext(r.Context())
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	req := &models.GroupParentRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	group, err := config.GroupRepository.FindByID(uint(id))
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	role, err := authorization.GroupRole(config.UserGroupRepository, user, group)
+	if err != nil {
+		http.Error(w, "Failed to retrieve membership", http.StatusInternalServerError)
+		return
+	}
+	managesParent := config.canManageParent(user, group)
+	if role == "" && !managesParent {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+	if req.ParentID != nil {
+		if !authorization.RoleCan(role, authorization.DeleteGroup) {
+			http.Error(w, "Your role in this group does not allow this", http.StatusForbidden)
+			return
+		}
+		parent, err := config.GroupRepository.FindByID(*req.ParentID)
+		if err != nil {
+			http.Error(w, "Parent group not found", http.StatusNotFound)
+			return
+		}
+		parentRole, err := authorization.GroupRole(config.UserGroupRepository, user, parent)
+		if err != nil {
+			http.Error(w, "Failed to retrieve membership", http.StatusInternalServerError)
+			return
+		}
+		if parentRole == "" {
+			http.Error(w, "Parent group not found", http.StatusNotFound)
+			return
+		}
+		if !authorization.RoleCan(parentRole, authorization.ManageMembers) {
+			http.Error(w, "Your role in the parent group does not allow this", http.StatusForbidden)
+			return
+		}
+	} else if !authorization.RoleCan(role, authorization.DeleteGroup) && !managesParent {
+		http.Error(w, "Your role in this group does not allow this", http.StatusForbidden)
+		return
+	}
+
+	err = config.GroupRepository.SetParentByID(group.ID, req.ParentID)
+	if errors.Is(err, dbmodel.ErrGroupCycle) {
+		http.Error(w, "A group cannot be nested in itself or its descendants", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to move group", http.StatusInternalServerError)
+		return
+	}
+	group.ParentID = req.ParentID
+	config.Events.Publish(events.GroupEvent(events.ActionUpdated, group))
+	render.JSON(w, r, &models.GroupResponse{ID: group.ID, Name: group.Name, CreatorID: group.CreatorID, ParentID: group.ParentID})
+}
+
+// @Summary		Get the tree of a group
+// @Description	Get a group with the groups nested in it, at any depth. Any member may see it, guests included.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{object}	models.GroupTreeResponse
+// @Failure 	400 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/tree [get]
+func (config *GroupConfig) GetTree(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.SeeFreeBusy)
+	if !ok {
+		return
+	}
+	subtree, err := config.GroupRepository.FindSubtree(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	children := make(map[uint][]dbmodel.Group)
+	for _, descendant := range subtree[1:] {
+		children[*descendant.ParentID] = append(children[*descendant.ParentID], descendant)
+	}
+	render.JSON(w, r, toTreeResponse(&subtree[0], children))
+}
+
+// @Summary		List the effective members of a group
+// @Description	List the members of a group along with those of its descendants, who are implicitly members of it, each with the groups of the tree they were added to. Guests may not list them.
+// @Tags		groups
+// @Produce		json
+// @Param		id	path	int	true	"Group ID"
+// @Success		200	{array}		models.EffectiveMemberResponse
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/group/{id}/members/effective [get]
+func (config *GroupConfig) GetEffectiveMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+	group, _, ok := config.groupWith(w, r, uint(id), authorization.SeeMembers)
+	if !ok {
+		return
+	}
+	subtree, err := config.GroupRepository.FindSubtree(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	groupIDs := make([]uint, 0, len(subtree))
+	for _, descendant := range subtree {
+		groupIDs = append(groupIDs, descendant.ID)
+	}
+	userGroups, err := config.UserGroupRepository.FindByGroupIDs(groupIDs)
+	if err != nil {
+		http.Error(w, "Failed to retrieve members", http.StatusInternalServerError)
+		return
+	}
+
+	effectiveMemberResponse := make([]models.EffectiveMemberResponse, 0)
+	indexes := make(map[uint]int)
+	for _, userGroup := range userGroups {
+		role := userGroup.Role
+		if userGroup.UserID == group.CreatorID {
+			role = dbmodel.RoleOwner
+		} else if userGroup.GroupID != group.ID {
+			role = authorization.InheritedRole(role)
+		}
+		index, seen := indexes[userGroup.UserID]
+		if !seen {
+			index = len(effectiveMemberResponse)
+			indexes[userGroup.UserID] = index
+			effectiveMemberResponse = append(effectiveMemberResponse, models.EffectiveMemberResponse{UserID: userGroup.UserID, GroupIDs: []uint{}})
+		}
+		member := &effectiveMemberResponse[index]
+		if authorization.RoleAbove(role, member.Role) {
+			member.Role = role
+		}
+		member.GroupIDs = append(member.GroupIDs, userGroup.GroupID)
+	}
+	render.JSON(w, r, effectiveMemberResponse)
+}
+
+// canManageParent reports whether user administers the group a group is
+// nested in, which lets them detach it.
+func (config *GroupConfig) canManageParent(user *dbmodel.User, group *dbmodel.Group) bool {
+	if group.ParentID == nil {
+		return false
+	}
+	parent, err := config.GroupRepository.FindByID(*group.ParentID)
+	if err != nil {
+		return false
+	}
+	allowed, err := authorization.CanInGroup(config.UserGroupRepository, user, parent, authorization.ManageMembers)
+	return err == nil && allowed
+}
+
+func toTreeResponse(group *dbmodel.Group, children map[uint][]dbmodel.Group) models.GroupTreeResponse {
+	treeResponse := models.GroupTreeResponse{
+		ID:        group.ID,
+		Name:      group.Name,
+		CreatorID: group.CreatorID,
+		ParentID:  group.ParentID,
+		Children:  make([]models.GroupTreeResponse, 0, len(children[group.ID])),
+	}
+	for _, child := range children[group.ID] {
+		treeResponse.Children = append(treeResponse.Children, toTreeResponse(&child, children))
+	}
+	return treeResponse
+}
@@ -0,0 +1,103 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"yplanning/database/dbmodel"
+)
+
+const (
+	untitledDate = "Untitled"
+	busySummary  = "Busy"
+	classPrivate = "PRIVATE"
+	// ExpansionYears is how far past its start the occurrences of a
+	// recurring date written by its owner are materialised.
+	ExpansionYears = 2
+)
+
+// DateUID returns the UID a date is published under: the one it was
+// imported with, or a stable one derived from its ID.
+func DateUID(date *dbmodel.Date) string {
+	if date.UID != "" {
+		return date.UID
+	}
+	return fmt.Sprintf("date-%d@yplanning", date.ID)
+}
+
+func FromDate(date *dbmodel.Date) Event {
+	exDates, _ := SplitDateTimes(date.ExDate)
+	event := Event{
+		UID:         DateUID(date),
+		Summary:     date.Title,
+		Description: date.Body,
+		Start:       date.BeginTime,
+		End:         date.EndTime,
+		RRule:       date.RRule,
+		ExDates:     exDates,
+		Stamp:       date.UpdatedAt,
+	}
+	if date.Private {
+		event.Class = classPrivate
+	}
+	return event
+}
+
+// DateOccurrences expands the RRULE of a stored date, for writers that
+// change it and must regenerate its occurrences.
+func DateOccurrences(date *dbmodel.Date) ([]time.Time, error) {
+	event := FromDate(date)
+	return event.Occurrences(date.BeginTime.AddDate(ExpansionYears, 0, 0))
+}
+
+// FromDateAsBusy exports a date without leaking its content, for private
+// dates shown to someone who may only know the slot is taken.
+func FromDateAsBusy(date *dbmodel.Date) Event {
+	event := FromDate(date)
+	event.Summary = busySummary
+	event.Description = ""
+	return event
+}
+
+func ToDate(event Event, userID uint) *dbmodel.Date {
+	title := strings.TrimSpace(event.Summary)
+	if title == "" {
+		title = untitledDate
+	}
+	return &dbmodel.Date{
+		Title:     title,
+		Body:      event.Description,
+		UserID:    userID,
+		BeginTime: event.Start,
+		EndTime:   event.End,
+		Private:   event.Class == classPrivate || event.Class == "CONFIDENTIAL",
+		UID:       event.UID,
+		RRule:     event.RRule,
+		ExDate:    JoinDateTimes(event.ExDates),
+	}
+}
+
+// FromDates converts a user's dates into events. Occurrences materialised
+// from an RRULE are left out since the master event already describes them.
+func FromDates(dates []dbmodel.Date, busyOnly func(date *dbmodel.Date) bool) []Event {
+	recurring := make(map[uint]bool)
+	for _, date := range dates {
+		if date.RRule != "" {
+			recurring[date.ID] = true
+		}
+	}
+	events := make([]Event, 0, len(dates))
+	for i := range dates {
+		date := &dates[i]
+		if date.RecurrenceID != 0 && recurring[date.RecurrenceID] {
+			continue
+		}
+		if busyOnly != nil && busyOnly(date) {
+			events = append(events, FromDateAsBusy(date))
+		} else {
+			events = append(events, FromDate(date))
+		}
+	}
+	return events
+}
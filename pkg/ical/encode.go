@@ -0,0 +1,107 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	productID      = "-//yplanning//yplanning API//EN"
+	maxLineOctets  = 75
+	lineTerminator = "\r\n"
+)
+
+func Write(writer io.Writer, calendar *Calendar) error {
+	buffered := bufio.NewWriter(writer)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + productID,
+		"CALSCALE:GREGORIAN",
+	}
+	if calendar.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escapeText(calendar.Name))
+	}
+	for _, event := range calendar.Events {
+		lines = append(lines, event.lines()...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := buffered.WriteString(fold(line)); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+func (event *Event) lines() []string {
+	stamp := event.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + event.UID,
+		"DTSTAMP:" + FormatDateTime(stamp),
+	}
+	if event.AllDay {
+		lines = append(lines,
+			"DTSTART;VALUE=DATE:"+event.Start.Format(dateLayout),
+			"DTEND;VALUE=DATE:"+event.End.Format(dateLayout),
+		)
+	} else {
+		lines = append(lines,
+			"DTSTART:"+FormatDateTime(event.Start),
+			"DTEND:"+FormatDateTime(event.End),
+		)
+	}
+	lines = append(lines, "SUMMARY:"+escapeText(event.Summary))
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeText(event.Description))
+	}
+	if event.RRule != "" {
+		lines = append(lines, "RRULE:"+event.RRule)
+	}
+	if len(event.ExDates) > 0 {
+		lines = append(lines, "EXDATE:"+JoinDateTimes(event.ExDates))
+	}
+	if event.Class != "" {
+		lines = append(lines, "CLASS:"+event.Class)
+	}
+	if event.Status != "" {
+		lines = append(lines, "STATUS:"+event.Status)
+	}
+	return append(lines, "END:VEVENT")
+}
+
+// fold splits a content line into chunks of at most 75 octets as required
+// by RFC 5545, never cutting through a multi-byte character.
+func fold(line string) string {
+	var builder strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			builder.WriteString(lineTerminator + " ")
+			width = 1
+		}
+		builder.WriteRune(r)
+		width += size
+	}
+	builder.WriteString(lineTerminator)
+	return builder.String()
+}
+
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
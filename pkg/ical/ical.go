@@ -0,0 +1,347 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout        = "20060102"
+	dateTimeLayout    = "20060102T150405"
+	dateTimeUTCLayout = "20060102T150405Z"
+)
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	RRule        string
+	ExDates      []time.Time
+	Status       string
+	Class        string
+	RecurrenceID time.Time
+	Stamp        time.Time
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func Parse(reader io.Reader) (*Calendar, error) {
+	lines, err := unfold(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &Calendar{}
+	var event *Event
+	var duration string
+	var stack []string
+	for number, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			stack = append(stack, component)
+			if component == "VEVENT" && len(stack) == 2 {
+				event = &Event{}
+				duration = ""
+			}
+			continue
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", number+1, prop.value)
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" && event != nil && len(stack) == 1 {
+				if err := event.finish(duration); err != nil {
+					return nil, fmt.Errorf("event %q: %w", event.UID, err)
+				}
+				calendar.Events = append(calendar.Events, *event)
+				event = nil
+			}
+			continue
+		}
+
+		if len(stack) == 1 && stack[0] == "VCALENDAR" && prop.name == "X-WR-CALNAME" {
+			calendar.Name = unescapeText(prop.value)
+			continue
+		}
+		if event == nil || len(stack) != 2 {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			event.UID = prop.value
+		case "SUMMARY":
+			event.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(prop.value)
+		case "STATUS":
+			event.Status = strings.ToUpper(prop.value)
+		case "CLASS":
+			event.Class = strings.ToUpper(prop.value)
+		case "DTSTAMP":
+			event.Stamp, _, err = parseTime(prop)
+		case "RRULE":
+			event.RRule = prop.value
+		case "DURATION":
+			duration = prop.value
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseTime(prop)
+		case "DTEND":
+			event.End, _, err = parseTime(prop)
+		case "RECURRENCE-ID":
+			event.RecurrenceID, _, err = parseTime(prop)
+		case "EXDATE":
+			for _, value := range strings.Split(prop.value, ",") {
+				var exDate time.Time
+				exDate, _, err = parseTime(property{name: prop.name, params: prop.params, value: value})
+				if err != nil {
+					break
+				}
+				event.ExDates = append(event.ExDates, exDate)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", number+1, prop.name, err)
+		}
+	}
+	if len(stack) != 0 {
+		return nil, errors.New("unterminated " + stack[len(stack)-1])
+	}
+	return calendar, nil
+}
+
+func (event *Event) finish(duration string) error {
+	if event.Start.IsZero() {
+		return errors.New("missing DTSTART")
+	}
+	if event.End.IsZero() {
+		switch {
+		case duration != "":
+			d, err := ParseDuration(duration)
+			if err != nil {
+				return err
+			}
+			event.End = event.Start.Add(d)
+		case event.AllDay:
+			event.End = event.Start.AddDate(0, 0, 1)
+		default:
+			event.End = event.Start
+		}
+	}
+	if event.End.Before(event.Start) {
+		return errors.New("DTEND is before DTSTART")
+	}
+	return nil
+}
+
+func unfold(reader io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+	prop.value = line[colon+1:]
+
+	parts := splitUnquoted(line[:colon], ';')
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+func splitUnquoted(s string, separator rune) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, r := range s {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == separator && !inQuotes {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseTime(prop property) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if prop.params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, time.UTC)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeUTCLayout, value)
+		return t, false, err
+	}
+	location := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, value, location)
+	return t, false, err
+}
+
+// ParseDuration parses an RFC 5545 duration such as "PT1H30M" or "-P1D".
+func ParseDuration(value string) (time.Duration, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	}
+	s = strings.TrimPrefix(s, "+")
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s[1:] {
+		switch {
+		case r == 'T':
+			inTime = true
+			continue
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		number = ""
+		switch {
+		case r == 'W' && !inTime:
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			total += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return sign * total, nil
+}
+
+func unescapeText(value string) string {
+	var builder strings.Builder
+	escaped := false
+	for _, r := range value {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+				continue
+			}
+			builder.WriteRune(r)
+			continue
+		}
+		escaped = false
+		switch r {
+		case 'n', 'N':
+			builder.WriteRune('\n')
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeUTCLayout)
+}
+
+// JoinDateTimes serialises times as a comma separated list of UTC
+// date-times, the format used for EXDATE values.
+func JoinDateTimes(times []time.Time) string {
+	values := make([]string, 0, len(times))
+	for _, t := range times {
+		values = append(values, FormatDateTime(t))
+	}
+	return strings.Join(values, ",")
+}
+
+func SplitDateTimes(value string) ([]time.Time, error) {
+	var times []time.Time
+	if value == "" {
+		return times, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		t, err := time.Parse(dateTimeUTCLayout, part)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
@@ -0,0 +1,117 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func calendarOf(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+func TestParse(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  Event
+	}{
+		{
+			name:  "utc with end",
+			input: calendarOf("BEGIN:VEVENT", "UID:a", "SUMMARY:Meeting", "DTSTART:20240105T090000Z", "DTEND:20240105T100000Z", "END:VEVENT"),
+			want:  Event{UID: "a", Summary: "Meeting", Start: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:  "tzid",
+			input: calendarOf("BEGIN:VEVENT", "UID:b", "DTSTART;TZID=Europe/Paris:20240705T090000", "DTEND;TZID=\"Europe/Paris\":20240705T100000", "END:VEVENT"),
+			want:  Event{UID: "b", Start: time.Date(2024, 7, 5, 9, 0, 0, 0, paris), End: time.Date(2024, 7, 5, 10, 0, 0, 0, paris)},
+		},
+		{
+			name:  "all day without end",
+			input: calendarOf("BEGIN:VEVENT", "UID:c", "DTSTART;VALUE=DATE:20240105", "END:VEVENT"),
+			want:  Event{UID: "c", AllDay: true, Start: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:  "duration",
+			input: calendarOf("BEGIN:VEVENT", "UID:d", "DTSTART:20240105T090000Z", "DURATION:PT1H30M", "END:VEVENT"),
+			want:  Event{UID: "d", Start: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 5, 10, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:  "folded and escaped text",
+			input: calendarOf("BEGIN:VEVENT", "UID:e", "SUMMARY:Long", "  title", "DESCRIPTION:one\\, two\\nthree", "DTSTART:20240105T090000Z", "END:VEVENT"),
+			want:  Event{UID: "e", Summary: "Long title", Description: "one, two\nthree", Start: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:  "recurrence and exceptions",
+			input: calendarOf("BEGIN:VEVENT", "UID:f", "DTSTART:20240105T090000Z", "RRULE:FREQ=DAILY;COUNT=3", "EXDATE:20240106T090000Z,20240107T090000Z", "STATUS:confirmed", "CLASS:private", "END:VEVENT"),
+			want: Event{
+				UID: "f", Start: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+				RRule: "FREQ=DAILY;COUNT=3", Status: "CONFIRMED", Class: "PRIVATE",
+				ExDates: []time.Time{time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 7, 9, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:  "alarm properties ignored",
+			input: calendarOf("BEGIN:VEVENT", "UID:g", "DTSTART:20240105T090000Z", "BEGIN:VALARM", "DESCRIPTION:Reminder", "END:VALARM", "END:VEVENT"),
+			want:  Event{UID: "g", Start: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calendar, err := Parse(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(calendar.Events) != 1 {
+				t.Fatalf("got %d events, want 1", len(calendar.Events))
+			}
+			got := calendar.Events[0]
+			if got.UID != test.want.UID || got.Summary != test.want.Summary || got.Description != test.want.Description ||
+				got.AllDay != test.want.AllDay || got.RRule != test.want.RRule || got.Status != test.want.Status || got.Class != test.want.Class {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+			if !got.Start.Equal(test.want.Start) || !got.End.Equal(test.want.End) {
+				t.Errorf("got %v to %v, want %v to %v", got.Start, got.End, test.want.Start, test.want.End)
+			}
+			if !equalSlices(got.ExDates, test.want.ExDates) {
+				t.Errorf("got exdates %v, want %v", got.ExDates, test.want.ExDates)
+			}
+		})
+	}
+}
+
+func TestParseCalendarName(t *testing.T) {
+	calendar, err := Parse(strings.NewReader(calendarOf("X-WR-CALNAME:Team\\, work")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calendar.Name != "Team, work" || len(calendar.Events) != 0 {
+		t.Errorf("got %+v", calendar)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing start", input: calendarOf("BEGIN:VEVENT", "UID:a", "END:VEVENT")},
+		{name: "end before start", input: calendarOf("BEGIN:VEVENT", "UID:a", "DTSTART:20240105T090000Z", "DTEND:20240105T080000Z", "END:VEVENT")},
+		{name: "invalid date", input: calendarOf("BEGIN:VEVENT", "UID:a", "DTSTART:2024-01-05", "END:VEVENT")},
+		{name: "invalid duration", input: calendarOf("BEGIN:VEVENT", "UID:a", "DTSTART:20240105T090000Z", "DURATION:1H", "END:VEVENT")},
+		{name: "malformed line", input: calendarOf("BEGIN:VEVENT", "UID a", "END:VEVENT")},
+		{name: "mismatched end", input: calendarOf("BEGIN:VEVENT", "END:VTODO")},
+		{name: "unterminated", input: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(test.input)); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
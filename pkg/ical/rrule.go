@@ -0,0 +1,213 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MaxOccurrences = 1000
+
+// ErrUnsupportedRule is returned for valid recurrence rules that use parts
+// this package does not expand, such as ordinal BYDAY values.
+var ErrUnsupportedRule = errors.New("unsupported recurrence rule")
+
+type RecurrenceRule struct {
+	Frequency string
+	Interval  int
+	Count     int
+	Until     time.Time
+	// UntilDate is set when UNTIL is a DATE, which includes that whole day.
+	UntilDate  bool
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("INTERVAL must be >= 1")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			rule.Until, rule.UntilDate, err = parseTime(property{value: val, params: map[string]string{}})
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				day = strings.ToUpper(day)
+				weekday, ok := weekdays[day]
+				if !ok && len(day) > 2 {
+					if _, known := weekdays[day[len(day)-2:]]; known {
+						if _, err := strconv.Atoi(day[:len(day)-2]); err == nil {
+							return nil, fmt.Errorf("%w: ordinal BYDAY value %q", ErrUnsupportedRule, day)
+						}
+					}
+				}
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err == nil && n >= -31 && n <= -1 {
+					return nil, fmt.Errorf("%w: negative BYMONTHDAY value %q", ErrUnsupportedRule, day)
+				}
+				if err != nil || n < 1 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY value %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+		default:
+			return nil, fmt.Errorf("%w: RRULE part %q", ErrUnsupportedRule, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE %s: %w", key, err)
+		}
+	}
+	switch rule.Frequency {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("RRULE is missing FREQ")
+	default:
+		return nil, fmt.Errorf("%w: frequency %q", ErrUnsupportedRule, rule.Frequency)
+	}
+	return rule, nil
+}
+
+// Occurrences returns the start time of every instance of the event up to
+// horizon, DTSTART included and EXDATE excluded. Events without an RRULE
+// have a single occurrence.
+func (event *Event) Occurrences(horizon time.Time) ([]time.Time, error) {
+	if event.RRule == "" {
+		return []time.Time{event.Start}, nil
+	}
+	rule, err := ParseRecurrenceRule(event.RRule)
+	if err != nil {
+		return nil, err
+	}
+	until := rule.Until
+	if rule.UntilDate {
+		year, month, day := until.Date()
+		until = time.Date(year, month, day+1, 0, 0, 0, 0, event.Start.Location()).Add(-time.Nanosecond)
+	}
+	if !until.IsZero() && until.Before(horizon) {
+		horizon = until
+	}
+
+	excluded := make(map[int64]bool, len(event.ExDates))
+	for _, exDate := range event.ExDates {
+		excluded[exDate.Unix()] = true
+	}
+
+	var occurrences []time.Time
+	emitted := 0
+	start := event.Start
+	for period := 0; emitted < MaxOccurrences && period < 100*MaxOccurrences; period++ {
+		candidates := rule.candidates(start, period)
+		if len(candidates) == 0 {
+			continue
+		}
+		if candidates[0].After(horizon) {
+			break
+		}
+		for _, candidate := range candidates {
+			if candidate.Before(start) || candidate.After(horizon) {
+				continue
+			}
+			if rule.Count > 0 && emitted >= rule.Count {
+				return occurrences, nil
+			}
+			emitted++
+			if !excluded[candidate.Unix()] {
+				occurrences = append(occurrences, candidate)
+			}
+		}
+		if rule.Count > 0 && emitted >= rule.Count {
+			break
+		}
+	}
+	return occurrences, nil
+}
+
+func (rule *RecurrenceRule) candidates(start time.Time, period int) []time.Time {
+	year, month, day := start.Date()
+	hour, minute, second := start.Clock()
+	location := start.Location()
+	step := period * rule.Interval
+
+	var candidates []time.Time
+	switch rule.Frequency {
+	case "DAILY":
+		candidate := time.Date(year, month, day+step, hour, minute, second, 0, location)
+		if len(rule.ByDay) == 0 || containsWeekday(rule.ByDay, candidate.Weekday()) {
+			candidates = append(candidates, candidate)
+		}
+	case "WEEKLY":
+		days := rule.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := day - offset + 7*step
+		for _, weekday := range days {
+			candidates = append(candidates, time.Date(year, month, monday+(int(weekday)+6)%7, hour, minute, second, 0, location))
+		}
+	case "MONTHLY":
+		days := rule.ByMonthDay
+		if len(days) == 0 {
+			days = []int{day}
+		}
+		for _, monthDay := range days {
+			candidate := time.Date(year, month+time.Month(step), monthDay, hour, minute, second, 0, location)
+			if candidate.Day() == monthDay {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case "YEARLY":
+		candidate := time.Date(year+step, month, day, hour, minute, second, 0, location)
+		if candidate.Day() == day {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+func containsWeekday(days []time.Weekday, weekday time.Weekday) bool {
+	for _, day := range days {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
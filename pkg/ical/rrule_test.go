@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		want        RecurrenceRule
+		unsupported bool
+		invalid     bool
+	}{
+		{name: "daily", rule: "FREQ=DAILY", want: RecurrenceRule{Frequency: "DAILY", Interval: 1}},
+		{name: "lower case", rule: "freq=weekly;interval=2", want: RecurrenceRule{Frequency: "WEEKLY", Interval: 2}},
+		{name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=MO,we", want: RecurrenceRule{Frequency: "WEEKLY", Interval: 1, ByDay: []time.Weekday{time.Monday, time.Wednesday}}},
+		{name: "count", rule: "FREQ=MONTHLY;COUNT=3;BYMONTHDAY=1,15", want: RecurrenceRule{Frequency: "MONTHLY", Interval: 1, Count: 3, ByMonthDay: []int{1, 15}}},
+		{name: "until date time", rule: "FREQ=DAILY;UNTIL=20240110T120000Z", want: RecurrenceRule{Frequency: "DAILY", Interval: 1, Until: time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)}},
+		{name: "until date", rule: "FREQ=DAILY;UNTIL=20240110", want: RecurrenceRule{Frequency: "DAILY", Interval: 1, Until: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), UntilDate: true}},
+		{name: "week start ignored", rule: "FREQ=WEEKLY;WKST=SU", want: RecurrenceRule{Frequency: "WEEKLY", Interval: 1}},
+		{name: "ordinal by day", rule: "FREQ=MONTHLY;BYDAY=1MO", unsupported: true},
+		{name: "negative ordinal by day", rule: "FREQ=MONTHLY;BYDAY=-1FR", unsupported: true},
+		{name: "negative month day", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", unsupported: true},
+		{name: "by set position", rule: "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1", unsupported: true},
+		{name: "hourly", rule: "FREQ=HOURLY", unsupported: true},
+		{name: "missing frequency", rule: "INTERVAL=2", invalid: true},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0", invalid: true},
+		{name: "unknown day", rule: "FREQ=WEEKLY;BYDAY=XX", invalid: true},
+		{name: "ordinal of unknown day", rule: "FREQ=WEEKLY;BYDAY=1XX", invalid: true},
+		{name: "month day out of range", rule: "FREQ=MONTHLY;BYMONTHDAY=32", invalid: true},
+		{name: "part without value", rule: "FREQ=DAILY;COUNT", invalid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(test.rule)
+			switch {
+			case test.unsupported:
+				if !errors.Is(err, ErrUnsupportedRule) {
+					t.Fatalf("ParseRecurrenceRule(%q) = %v, want %v", test.rule, err, ErrUnsupportedRule)
+				}
+			case test.invalid:
+				if err == nil || errors.Is(err, ErrUnsupportedRule) {
+					t.Fatalf("ParseRecurrenceRule(%q) = %v, want an invalid rule error", test.rule, err)
+				}
+			case err != nil:
+				t.Fatalf("ParseRecurrenceRule(%q) = %v", test.rule, err)
+			default:
+				if rule.Frequency != test.want.Frequency || rule.Interval != test.want.Interval || rule.Count != test.want.Count ||
+					!rule.Until.Equal(test.want.Until) || rule.UntilDate != test.want.UntilDate ||
+					!equalSlices(rule.ByDay, test.want.ByDay) || !equalSlices(rule.ByMonthDay, test.want.ByMonthDay) {
+					t.Fatalf("ParseRecurrenceRule(%q) = %+v, want %+v", test.rule, *rule, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	utc := func(day, hour int) time.Time { return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC) }
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		event   Event
+		horizon time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "single",
+			event:   Event{Start: utc(1, 9)},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(1, 9)},
+		},
+		{
+			name:    "daily count",
+			event:   Event{Start: utc(1, 9), RRule: "FREQ=DAILY;COUNT=3"},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(1, 9), utc(2, 9), utc(3, 9)},
+		},
+		{
+			name:    "count includes excluded dates",
+			event:   Event{Start: utc(1, 9), RRule: "FREQ=DAILY;COUNT=3", ExDates: []time.Time{utc(2, 9)}},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(1, 9), utc(3, 9)},
+		},
+		{
+			name:    "horizon",
+			event:   Event{Start: utc(1, 9), RRule: "FREQ=DAILY"},
+			horizon: utc(3, 12),
+			want:    []time.Time{utc(1, 9), utc(2, 9), utc(3, 9)},
+		},
+		{
+			name:    "until date time excludes later start",
+			event:   Event{Start: utc(1, 9), RRule: "FREQ=DAILY;UNTIL=20240103T080000Z"},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(1, 9), utc(2, 9)},
+		},
+		{
+			name:    "until date includes its whole day",
+			event:   Event{Start: utc(1, 9), RRule: "FREQ=DAILY;UNTIL=20240103"},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(1, 9), utc(2, 9), utc(3, 9)},
+		},
+		{
+			name:    "until date in the event's time zone",
+			event:   Event{Start: time.Date(2024, 1, 1, 23, 30, 0, 0, paris), RRule: "FREQ=DAILY;UNTIL=20240102"},
+			horizon: utc(31, 0),
+			want:    []time.Time{time.Date(2024, 1, 1, 23, 30, 0, 0, paris), time.Date(2024, 1, 2, 23, 30, 0, 0, paris)},
+		},
+		{
+			name:    "weekly by day",
+			event:   Event{Start: utc(1, 9), RRule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4"},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(1, 9), utc(3, 9), utc(8, 9), utc(10, 9)},
+		},
+		{
+			name:    "every other week",
+			event:   Event{Start: utc(2, 9), RRule: "FREQ=WEEKLY;INTERVAL=2;COUNT=3"},
+			horizon: utc(31, 0),
+			want:    []time.Time{utc(2, 9), utc(16, 9), utc(30, 9)},
+		},
+		{
+			name:    "monthly skips short months",
+			event:   Event{Start: utc(31, 9), RRule: "FREQ=MONTHLY;COUNT=3"},
+			horizon: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    []time.Time{utc(31, 9), time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "yearly on leap day",
+			event:   Event{Start: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), RRule: "FREQ=YEARLY;COUNT=2"},
+			horizon: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    []time.Time{time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.event.Occurrences(test.horizon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("Occurrences = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if !got[i].Equal(test.want[i]) {
+					t.Fatalf("Occurrences = %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrencesUnsupportedRule(t *testing.T) {
+	event := Event{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), RRule: "FREQ=MONTHLY;BYDAY=1MO"}
+	if _, err := event.Occurrences(event.Start.AddDate(1, 0, 0)); !errors.Is(err, ErrUnsupportedRule) {
+		t.Fatalf("Occurrences = %v, want %v", err, ErrUnsupportedRule)
+	}
+}
+
+func equalSlices[T comparable](a []T, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
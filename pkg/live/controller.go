@@ -0,0 +1,398 @@
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"yplanning/config"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	heartbeatInterval = 25 * time.Second
+	writeTimeout      = 10 * time.Second
+	retryMilliseconds = 3000
+	resetEvent        = "reset"
+)
+
+var upgrader = websocket.Upgrader{CheckOrigin: checkOrigin}
+
+// checkOrigin accepts WebSocket connections from clients that send no
+// Origin, such as command-line tools, from pages served by this host, and
+// from the origins listed in ALLOWED_ORIGINS, separated by commas.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range strings.Split(os.Getenv("ALLOWED_ORIGINS"), ",") {
+		if allowed = strings.TrimSuffix(strings.TrimSpace(allowed), "/"); allowed != "" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+type LiveConfig struct {
+	*config.Config
+	hub *Hub
+}
+
+func NewLiveConfig(cfg *config.Config) *LiveConfig {
+	return &LiveConfig{Config: cfg, hub: NewHub(cfg.Events)}
+}
+
+// channel is what a client follows: the events it receives and whose
+// point of view private dates are redacted from.
+type channel struct {
+	viewerID uint
+	filter   func(events.Event) bool
+	// visible re-checks, outside the hub lock, that the viewer may still
+	// see an event before it is sent: false skips the event, and an error
+	// ends the stream once the viewer lost access to the channel.
+	visible func(events.Event) (bool, error)
+}
+
+var errNoAccess = errors.New("viewer no longer has access to the channel")
+
+// resetMessage tells the client that events were missed and it should
+// reload the calendar; ID is the event to resume from afterwards.
+type resetMessage struct {
+	ID   uint64 `json:"id"`
+	Type string `json:"type"`
+}
+
+// @Summary		Follow a user's calendar (SSE)
+// @Description	Server-Sent Events stream of the date, availability, group and membership events of a user. The caller must be that user or share a group with them, and only receives the group and membership events of groups it belongs to. The stream ends once the caller no longer shares a group with the user. Reconnecting clients send Last-Event-ID (or last_event_id) to receive the events they missed; a "reset" event means the calendar must be reloaded. Browsers may pass the token as access_token.
+// @Tags		live
+// @Produce		text/event-stream
+// @Param		userID	path	int	true	"User ID"
+// @Param		last_event_id	query	int	false	"Resume after this event ID"
+// @Success		200	{string}	string	"event stream"
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	403 {string} 	string
+// @Security 	BearerAuth
+// @Router		/live/user/{userID} [get]
+func (config *LiveConfig) UserEvents(w http.ResponseWriter, r *http.Request) {
+	if channel, ok := config.userChannel(w, r); ok {
+		config.serveEventStream(w, r, channel)
+	}
+}
+
+// @Summary		Follow a user's calendar (WebSocket)
+// @Description	Same events as the Server-Sent Events stream, as JSON text messages over a WebSocket
+// @Tags		live
+// @Param		userID	path	int	true	"User ID"
+// @Param		last_event_id	query	int	false	"Resume after this event ID"
+// @Success		101	{string}	string	"switching protocols"
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	403 {string} 	string
+// @Security 	BearerAuth
+// @Router		/live/user/{userID}/ws [get]
+func (config *LiveConfig) UserSocket(w http.ResponseWriter, r *http.Request) {
+	if channel, ok := config.userChannel(w, r); ok {
+		config.serveWebSocket(w, r, channel)
+	}
+}
+
+// @Summary		Follow a group's calendar (SSE)
+// @Description	Server-Sent Events stream of the events of a group and of its members' dates and availabilities. The caller must be a member, and more than a guest. The dates and availabilities of guests are left out, and the stream ends once the caller leaves the group. Resumption works as for user streams.
+// @Tags		live
+// @Produce		text/event-stream
+// @Param		groupID	path	int	true	"Group ID"
+// @Param		last_event_id	query	int	false	"Resume after this event ID"
+// @Success		200	{string}	string	"event stream"
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	403 {string} 	string
+// @Security 	BearerAuth
+// @Router		/live/group/{groupID} [get]
+func (config *LiveConfig) GroupEvents(w http.ResponseWriter, r *http.Request) {
+	if channel, ok := config.groupChannel(w, r); ok {
+		config.serveEventStream(w, r, channel)
+	}
+}
+
+// @Summary		Follow a group's calendar (WebSocket)
+// @Description	Same events as the group Server-Sent Events stream, as JSON text messages over a WebSocket
+// @Tags		live
+// @Param		groupID	path	int	true	"Group ID"
+// @Param		last_event_id	query	int	false	"Resume after this event ID"
+// @Success		101	{string}	string	"switching protocols"
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	403 {string} 	string
+// @Security 	BearerAuth
+// @Router		/live/group/{groupID}/ws [get]
+func (config *LiveConfig) GroupSocket(w http.ResponseWriter, r *http.Request) {
+	if channel, ok := config.groupChannel(w, r); ok {
+		config.serveWebSocket(w, r, channel)
+	}
+}
+
+func (config *LiveConfig) userChannel(w http.ResponseWriter, r *http.Request) (*channel, bool) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil || userID < 1 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return nil, false
+	}
+	viewer := authorization.UserFromContext(r.Context())
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, uint(userID)); err != nil || !visible {
+		http.Error(w, "You do not share a group with this user", http.StatusForbidden)
+		return nil, false
+	}
+	groups, err := config.GroupRepository.FindByMemberID(viewer.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return nil, false
+	}
+	joined := make(map[uint]bool)
+	for _, group := range groups {
+		joined[group.ID] = true
+	}
+	// Group and membership events of the followed user only reach viewers
+	// who belong to that group; the viewer's own memberships keep the set
+	// up to date, as for group channels.
+	return &channel{
+		viewerID: viewer.ID,
+		filter: func(event events.Event) bool {
+			if event.Resource == events.ResourceMembership && event.UserID == viewer.ID {
+				joined[event.GroupID] = event.Action != events.ActionDeleted
+			}
+			if event.UserID != uint(userID) {
+				return false
+			}
+			return event.GroupID == 0 || viewer.IsAdmin || event.UserID == viewer.ID || joined[event.GroupID]
+		},
+		visible: func(events.Event) (bool, error) {
+			if visible, err := authorization.CanSeeUser(config.GroupRepository, viewer, uint(userID)); err != nil || !visible {
+				return false, errNoAccess
+			}
+			return true, nil
+		},
+	}, true
+}
+
+func (config *LiveConfig) groupChannel(w http.ResponseWriter, r *http.Request) (*channel, bool) {
+	groupID, err := strconv.Atoi(chi.URLParam(r, "groupID"))
+	if err != nil || groupID < 1 {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return nil, false
+	}
+	viewer := authorization.UserFromContext(r.Context())
+	group, err := config.GroupRepository.FindByID(uint(groupID))
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return nil, false
+	}
+	if allowed, err := authorization.CanInGroup(config.UserGroupRepository, viewer, group, authorization.SeeMembers); err != nil || !allowed {
+		http.Error(w, "You are not a member of this group, or only a guest", http.StatusForbidden)
+		return nil, false
+	}
+	memberIDs, err := config.GroupRepository.FindMemberIDs(group.ID)
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return nil, false
+	}
+	members := make(map[uint]bool)
+	for _, memberID := range memberIDs {
+		members[memberID] = true
+	}
+	// The member set is kept up to date from membership events rather than
+	// queried per event, since filters run with the hub locked.
+	return &channel{
+		viewerID: viewer.ID,
+		filter: func(event events.Event) bool {
+			if event.GroupID == uint(groupID) {
+				if event.Resource == events.ResourceMembership {
+					members[event.UserID] = event.Action != events.ActionDeleted
+				}
+				return true
+			}
+			return event.GroupID == 0 && members[event.UserID]
+		},
+		// The member set includes guests, whose calendars the viewer may
+		// not follow.
+		visible: func(event events.Event) (bool, error) {
+			current, err := config.GroupRepository.FindByID(uint(groupID))
+			if err != nil {
+				return false, errNoAccess
+			}
+			if allowed, err := authorization.CanInGroup(config.UserGroupRepository, viewer, current, authorization.SeeMembers); err != nil || !allowed {
+				return false, errNoAccess
+			}
+			if event.GroupID != 0 {
+				return true, nil
+			}
+			return authorization.CanSeeUser(config.GroupRepository, viewer, event.UserID)
+		},
+	}, true
+}
+
+func (config *LiveConfig) serveEventStream(w http.ResponseWriter, r *http.Request, channel *channel) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	subscription := config.hub.Subscribe(channel.filter, lastEventID(r))
+	defer subscription.Cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprintf(w, "retry: %d\n\n", retryMilliseconds)
+
+	send := func(id uint64, eventType string, data interface{}) error {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, eventType, payload); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	sendEvent := func(event events.Event) error {
+		if visible, err := channel.visible(event); err != nil || !visible {
+			return err
+		}
+		return send(event.ID, event.Type, event.RedactedFor(channel.viewerID))
+	}
+	if !subscription.Complete {
+		if send(subscription.LatestID, resetEvent, resetMessage{ID: subscription.LatestID, Type: resetEvent}) != nil {
+			return
+		}
+	} else {
+		for _, event := range subscription.Replay {
+			if sendEvent(event) != nil {
+				return
+			}
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-subscription.Events:
+			if !open || sendEvent(event) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (config *LiveConfig) serveWebSocket(w http.ResponseWriter, r *http.Request, channel *channel) {
+	connection, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer connection.Close()
+	subscription := config.hub.Subscribe(channel.filter, lastEventID(r))
+	defer subscription.Cancel()
+
+	// Incoming messages are not used; reading detects the client closing.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := connection.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(data interface{}) error {
+		connection.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return connection.WriteJSON(data)
+	}
+	sendEvent := func(event events.Event) error {
+		if visible, err := channel.visible(event); err != nil || !visible {
+			return err
+		}
+		return send(event.RedactedFor(channel.viewerID))
+	}
+	if !subscription.Complete {
+		if send(resetMessage{ID: subscription.LatestID, Type: resetEvent}) != nil {
+			return
+		}
+	} else {
+		for _, event := range subscription.Replay {
+			if sendEvent(event) != nil {
+				return
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case event, open := <-subscription.Events:
+			if !open {
+				connection.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"), time.Now().Add(writeTimeout))
+				return
+			}
+			if err := sendEvent(event); errors.Is(err, errNoAccess) {
+				connection.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "access revoked"), time.Now().Add(writeTimeout))
+				return
+			} else if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if connection.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// lastEventID reads the Last-Event-ID header EventSource sends when it
+// reconnects, or the last_event_id query parameter for the first
+// connection and for WebSockets.
+func lastEventID(r *http.Request) uint64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
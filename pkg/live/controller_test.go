@@ -0,0 +1,35 @@
+package live
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	t.Setenv("ALLOWED_ORIGINS", "https://app.example.com, https://admin.example.com/")
+	tests := []struct {
+		name   string
+		origin string
+		want   bool
+	}{
+		{name: "no origin", origin: "", want: true},
+		{name: "same host", origin: "https://api.example.com", want: true},
+		{name: "same host other case", origin: "https://API.example.com", want: true},
+		{name: "allowed", origin: "https://app.example.com", want: true},
+		{name: "allowed with trailing slash", origin: "https://admin.example.com", want: true},
+		{name: "other scheme", origin: "http://app.example.com", want: false},
+		{name: "unknown", origin: "https://evil.example.net", want: false},
+		{name: "suffix of allowed", origin: "https://app.example.com.evil.net", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://api.example.com/api/live/user/1/ws", nil)
+			if test.origin != "" {
+				r.Header.Set("Origin", test.origin)
+			}
+			if got := checkOrigin(r); got != test.want {
+				t.Fatalf("checkOrigin(%q) = %v, want %v", test.origin, got, test.want)
+			}
+		})
+	}
+}
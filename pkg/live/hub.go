@@ -0,0 +1,102 @@
+package live
+
+import (
+	"sync"
+
+	"yplanning/pkg/events"
+)
+
+const (
+	historySize       = 1000
+	subscriberBacklog = 64
+)
+
+// Hub keeps the most recent bus events so that reconnecting clients can
+// resume from their last event ID, and forwards new events to connected
+// subscribers.
+type Hub struct {
+	mutex       sync.Mutex
+	history     []events.Event
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	filter func(events.Event) bool
+	events chan events.Event
+}
+
+func NewHub(bus *events.Bus) *Hub {
+	hub := &Hub{subscribers: make(map[*subscriber]struct{})}
+	bus.Subscribe(hub.publish)
+	return hub
+}
+
+func (hub *Hub) publish(event events.Event) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.history = append(hub.history, event)
+	if len(hub.history) > historySize {
+		hub.history = hub.history[len(hub.history)-historySize:]
+	}
+	for subscriber := range hub.subscribers {
+		if !subscriber.filter(event) {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+			// A client that cannot keep up is disconnected; it resumes
+			// from its last event ID when it reconnects.
+			delete(hub.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+}
+
+// Subscription is a subscriber's view of the hub: the events it missed
+// since its last event ID, followed by live updates on Events.
+type Subscription struct {
+	Replay []events.Event
+	// Complete is false when some missed events are no longer in the
+	// history, or the last event ID is unknown to this server (it was
+	// restarted), in which case the client should reload the calendar.
+	Complete bool
+	LatestID uint64
+	Events   <-chan events.Event
+	Cancel   func()
+}
+
+// Subscribe registers a subscriber receiving the events accepted by filter.
+// The filter runs with the hub locked and must not block.
+func (hub *Hub) Subscribe(filter func(events.Event) bool, lastEventID uint64) *Subscription {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	subscription := &Subscription{Complete: true}
+	if len(hub.history) > 0 {
+		subscription.LatestID = hub.history[len(hub.history)-1].ID
+	}
+	if lastEventID > 0 {
+		if len(hub.history) == 0 || lastEventID > subscription.LatestID || lastEventID+1 < hub.history[0].ID {
+			subscription.Complete = false
+		}
+		for _, event := range hub.history {
+			if event.ID > lastEventID && filter(event) {
+				subscription.Replay = append(subscription.Replay, event)
+			}
+		}
+	}
+
+	subscriber := &subscriber{filter: filter, events: make(chan events.Event, subscriberBacklog)}
+	hub.subscribers[subscriber] = struct{}{}
+	subscription.Events = subscriber.events
+	subscription.Cancel = func() {
+		hub.mutex.Lock()
+		defer hub.mutex.Unlock()
+		if _, ok := hub.subscribers[subscriber]; ok {
+			delete(hub.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+	return subscription
+}
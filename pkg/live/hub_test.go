@@ -0,0 +1,44 @@
+package live
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"yplanning/pkg/events"
+)
+
+func TestHubResumesAfterConcurrentPublish(t *testing.T) {
+	bus := events.NewBus()
+	// A slow subscriber lets publishers overtake each other on their way
+	// to the hub if delivery is not ordered.
+	bus.Subscribe(func(events.Event) { runtime.Gosched() })
+	hub := NewHub(bus)
+	all := func(events.Event) bool { return true }
+
+	var wait sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for j := 0; j < 100; j++ {
+				bus.Publish(events.Event{Resource: events.ResourceDate, Action: events.ActionCreated})
+			}
+		}()
+	}
+	wait.Wait()
+
+	subscription := hub.Subscribe(all, 400)
+	defer subscription.Cancel()
+	if !subscription.Complete {
+		t.Fatal("resumption is incomplete")
+	}
+	if len(subscription.Replay) != 400 {
+		t.Fatalf("replayed %d events, want 400", len(subscription.Replay))
+	}
+	for i, event := range subscription.Replay {
+		if event.ID != uint64(401+i) {
+			t.Fatalf("replayed event %d has ID %d, want %d", i, event.ID, 401+i)
+		}
+	}
+}
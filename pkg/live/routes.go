@@ -0,0 +1,25 @@
+package live
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+live routes:
+GET /live/user/{userID} - Server-Sent Events stream of a user's calendar changes
+GET /live/user/{userID}/ws - WebSocket stream of a user's calendar changes
+GET /live/group/{groupID} - Server-Sent Events stream of a group's calendar changes
+GET /live/group/{groupID}/ws - WebSocket stream of a group's calendar changes
+*/
+
+func Routes(config *config.Config) chi.Router {
+	LiveConfig := NewLiveConfig(config)
+	router := chi.NewRouter()
+	router.Get("/user/{userID}", LiveConfig.UserEvents)
+	router.Get("/user/{userID}/ws", LiveConfig.UserSocket)
+	router.Get("/group/{groupID}", LiveConfig.GroupEvents)
+	router.Get("/group/{groupID}/ws", LiveConfig.GroupSocket)
+	return router
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// devFrom is the sender address of messages that are not really sent.
+const devFrom = "yplanning@localhost"
+
+// FileSender writes each message to a .eml file in Dir instead of sending
+// it, for local development and tests.
+type FileSender struct {
+	Dir   string
+	count atomic.Uint64
+}
+
+func (sender *FileSender) Send(message Message) error {
+	if err := validate(message); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102T150405.000000000"), sender.count.Add(1))
+	return os.WriteFile(filepath.Join(sender.Dir, name), format(devFrom, message), 0o600)
+}
+
+// LogSender writes messages to the log instead of sending them.
+type LogSender struct{}
+
+func (LogSender) Send(message Message) error {
+	if err := validate(message); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
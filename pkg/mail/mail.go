@@ -0,0 +1,77 @@
+// Package mail sends the emails of the service, such as password reset
+// links. Senders are picked from the environment: SMTP in production, and
+// files or the log for local development.
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages.
+type Sender interface {
+	Send(message Message) error
+}
+
+// FromEnv returns the sender configured by the environment:
+//
+//   - SMTP_ADDR, the host:port of an SMTP server, with SMTP_USERNAME,
+//     SMTP_PASSWORD and MAIL_FROM, the sender address;
+//   - otherwise MAIL_DIR, a directory where each message is written to a
+//     file;
+//   - otherwise the messages are written to the log.
+func FromEnv() (Sender, error) {
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		from := os.Getenv("MAIL_FROM")
+		if from == "" {
+			return nil, errors.New("MAIL_FROM is required with SMTP_ADDR")
+		}
+		return &SMTPSender{
+			Addr:     addr,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	}
+	if dir := os.Getenv("MAIL_DIR"); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("MAIL_DIR: %w", err)
+		}
+		return &FileSender{Dir: dir}, nil
+	}
+	return LogSender{}, nil
+}
+
+// format renders message as an RFC 5322 email from from.
+func format(from string, message Message) []byte {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "From: %s\r\n", from)
+	fmt.Fprintf(&builder, "To: %s\r\n", message.To)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&builder, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(builder.String())
+}
+
+// validate rejects messages whose headers could smuggle in other headers.
+func validate(message Message) error {
+	if message.To == "" {
+		return errors.New("message without recipient")
+	}
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return errors.New("line break in message header")
+	}
+	return nil
+}
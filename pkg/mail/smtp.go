@@ -0,0 +1,30 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+)
+
+// SMTPSender delivers messages through an SMTP server, authenticating with
+// PLAIN when Username is set.
+type SMTPSender struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+func (sender *SMTPSender) Send(message Message) error {
+	if err := validate(message); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if sender.Username != "" {
+		host, _, err := net.SplitHostPort(sender.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", sender.Username, sender.Password, host)
+	}
+	return smtp.SendMail(sender.Addr, auth, sender.From, []string{message.To}, format(sender.From, message))
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type AppPasswordRequest struct {
+	Name string `json:"name"`
+}
+
+func (a *AppPasswordRequest) Bind(r *http.Request) error {
+	if a.Name == "" {
+		return errors.New("name must not be null")
+	}
+	return nil
+}
+
+type AppPasswordResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at" extensions:"x-nullable"`
+	Username   string     `json:"username,omitempty"`
+	Password   string     `json:"password,omitempty"`
+	ServerURL  string     `json:"server_url,omitempty"`
+}
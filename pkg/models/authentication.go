@@ -3,6 +3,7 @@ package models
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 
 type TokenRequest struct {
@@ -21,3 +22,58 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"`
 }
+
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	// Current marks the session of the access token used for the request.
+	Current bool `json:"current"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+func (f *ForgotPasswordRequest) Bind(r *http.Request) error {
+	if f.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (p *ResetPasswordRequest) Bind(r *http.Request) error {
+	if p.Token == "" {
+		return errors.New("token is required")
+	} else if p.Password == "" {
+		return errors.New("password is required")
+	}
+	return nil
+}
+
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+func (e *EmailRequest) Bind(r *http.Request) error {
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+type EmailResponse struct {
+	Email      string     `json:"email"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at" extensions:"x-nullable"`
+	// PendingEmail is the address waiting for verification before it
+	// replaces Email, if any.
+	PendingEmail string `json:"pending_email"`
+}
@@ -31,13 +31,14 @@ func (u *DateRequest) Bind(r *http.Request) error {
 }
 
 type DateResponse struct {
-	ID           uint      `json:"id"`
-	Title        string    `json:"title"`
-	Body         string    `json:"body"`
-	DateBegin    time.Time `json:"date_begin"`
-	DateEnd      time.Time `json:"date_end"`
-	UserID       uint      `json:"user_id"`
-	Private      bool      `json:"private"`
-	RecurrenceID uint      `json:"recurrence_id"`
-	ColorID      uint      `json:"color_id"`
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	DateBegin      time.Time `json:"date_begin"`
+	DateEnd        time.Time `json:"date_end"`
+	UserID         uint      `json:"user_id"`
+	Private        bool      `json:"private"`
+	RecurrenceID   uint      `json:"recurrence_id"`
+	ColorID        uint      `json:"color_id"`
+	SubscriptionID uint      `json:"subscription_id"`
 }
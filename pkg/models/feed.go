@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type FeedTokenRequest struct {
+	Name           string `json:"name"`
+	GroupID        uint   `json:"group_id"`
+	IncludePrivate bool   `json:"include_private"`
+}
+
+func (f *FeedTokenRequest) Bind(r *http.Request) error {
+	if f.Name == "" {
+		return errors.New("name must not be null")
+	}
+	return nil
+}
+
+type FeedTokenResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	GroupID        uint       `json:"group_id"`
+	IncludePrivate bool       `json:"include_private"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at" extensions:"x-nullable"`
+	Token          string     `json:"token,omitempty"`
+	URL            string     `json:"url,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+import "time"
+
+type PeriodResponse struct {
+	DateBegin time.Time `json:"date_begin"`
+	DateEnd   time.Time `json:"date_end"`
+}
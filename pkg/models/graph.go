@@ -0,0 +1,19 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+)
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (g *GraphQLRequest) Bind(r *http.Request) error {
+	if g.Query == "" {
+		return errors.New("query must not be null")
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type GroupRequest struct {
@@ -23,4 +25,174 @@ type GroupResponse struct {
 	ID        uint   `json:"id"`
 	Name      string `json:"name"`
 	CreatorID uint   `json:"creator_id"`
+	ParentID  *uint  `json:"parent_id" extensions:"x-nullable"`
+}
+
+type GroupParentRequest struct {
+	// ParentID null makes the group a root.
+	ParentID *uint `json:"parent_id" extensions:"x-nullable"`
+}
+
+func (a *GroupParentRequest) Bind(r *http.Request) error {
+	if a.ParentID != nil && *a.ParentID == 0 {
+		return errors.New("invalid parent ID")
+	}
+	return nil
+}
+
+type GroupTreeResponse struct {
+	ID        uint                `json:"id"`
+	Name      string              `json:"name"`
+	CreatorID uint                `json:"creator_id"`
+	ParentID  *uint               `json:"parent_id" extensions:"x-nullable"`
+	Children  []GroupTreeResponse `json:"children"`
+}
+
+type EffectiveMemberResponse struct {
+	UserID uint `json:"user_id"`
+	// Role is the member's own role in the group, or the one they inherit
+	// from its descendants, capped at member.
+	Role string `json:"role"`
+	// GroupIDs lists the groups of the tree the user was added to.
+	GroupIDs []uint `json:"group_ids"`
+}
+
+type GroupRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func (a *GroupRoleRequest) Bind(r *http.Request) error {
+	if a.Role == "" {
+		return errors.New("role must not be null")
+	}
+	return nil
+}
+
+type TransferOwnershipRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+func (a *TransferOwnershipRequest) Bind(r *http.Request) error {
+	if a.UserID == 0 {
+		return errors.New("invalid user ID")
+	}
+	return nil
+}
+
+type MembershipResponse struct {
+	UserID   uint   `json:"user_id"`
+	GroupID  uint   `json:"group_id"`
+	ColorID  uint   `json:"color_id"`
+	Role     string `json:"role"`
+	InviteID uint   `json:"invite_id"`
+}
+
+type AddMemberRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+	// Role defaults to member.
+	Role    string `json:"role"`
+	ColorID uint   `json:"color_id"`
+}
+
+func (a *AddMemberRequest) Bind(r *http.Request) error {
+	if a.UserID == 0 {
+		return errors.New("invalid user ID")
+	}
+	return nil
+}
+
+type MemberColorRequest struct {
+	// ColorID 0 clears the color.
+	ColorID uint `json:"color_id"`
+}
+
+func (a *MemberColorRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type MyGroupResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	CreatorID uint   `json:"creator_id"`
+	Role      string `json:"role"`
+	ColorID   uint   `json:"color_id"`
+}
+
+type GroupInviteRequest struct {
+	// Role defaults to member.
+	Role string `json:"role"`
+	// MaxUses 0 means unlimited.
+	MaxUses   int        `json:"max_uses"`
+	ExpiresAt *time.Time `json:"expires_at" extensions:"x-nullable"`
+}
+
+func (a *GroupInviteRequest) Bind(r *http.Request) error {
+	if a.MaxUses < 0 {
+		return errors.New("max_uses must be >= 0")
+	} else if a.ExpiresAt != nil && !a.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+type GroupInviteResponse struct {
+	ID        uint       `json:"id"`
+	GroupID   uint       `json:"group_id"`
+	CreatorID uint       `json:"creator_id"`
+	Code      string     `json:"code"`
+	URL       string     `json:"url"`
+	Role      string     `json:"role"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at" extensions:"x-nullable"`
+	CreatedAt time.Time  `json:"created_at"`
+	// Active is false once the invite expired or was used up.
+	Active        bool   `json:"active"`
+	JoinedUserIDs []uint `json:"joined_user_ids"`
+}
+
+type GroupSettingsRequest struct {
+	Discoverable bool `json:"discoverable"`
+	// AutoApproveDomains lists the email domains, such as "example.edu",
+	// whose users join without waiting for approval.
+	AutoApproveDomains []string `json:"auto_approve_domains"`
+}
+
+func (a *GroupSettingsRequest) Bind(r *http.Request) error {
+	for _, domain := range a.AutoApproveDomains {
+		if domain == "" || strings.ContainsAny(domain, "@, ") {
+			return errors.New("auto_approve_domains must hold bare domain names")
+		}
+	}
+	return nil
+}
+
+type GroupSettingsResponse struct {
+	ID                 uint     `json:"id"`
+	Discoverable       bool     `json:"discoverable"`
+	AutoApproveDomains []string `json:"auto_approve_domains"`
+}
+
+type DiscoverableGroupResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type JoinRequestRequest struct {
+	Message string `json:"message"`
+}
+
+func (a *JoinRequestRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type JoinRequestResponse struct {
+	ID        uint       `json:"id"`
+	GroupID   uint       `json:"group_id"`
+	UserID    uint       `json:"user_id"`
+	Message   string     `json:"message"`
+	Status    string     `json:"status"`
+	DecidedBy uint       `json:"decided_by"`
+	DecidedAt *time.Time `json:"decided_at" extensions:"x-nullable"`
+	CreatedAt time.Time  `json:"created_at"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type ImportItem struct {
+	UID         string    `json:"uid"`
+	Title       string    `json:"title"`
+	DateBegin   time.Time `json:"date_begin"`
+	DateEnd     time.Time `json:"date_end"`
+	Occurrences int       `json:"occurrences"`
+	DateID      uint      `json:"date_id,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool         `json:"dry_run"`
+	Created []ImportItem `json:"created"`
+	Updated []ImportItem `json:"updated"`
+	Skipped []ImportItem `json:"skipped"`
+}
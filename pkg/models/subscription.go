@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type SubscriptionRequest struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	RefreshMinutes int    `json:"refresh_minutes"`
+}
+
+func (s *SubscriptionRequest) Bind(r *http.Request) error {
+	if s.Name == "" {
+		return errors.New("name must not be null")
+	} else if s.URL == "" {
+		return errors.New("url must not be null")
+	} else if s.RefreshMinutes < 0 {
+		return errors.New("refresh_minutes must be >= 0")
+	}
+	return nil
+}
+
+type SubscriptionResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	URL            string     `json:"url"`
+	RefreshMinutes int        `json:"refresh_minutes"`
+	LastSyncedAt   *time.Time `json:"last_synced_at" extensions:"x-nullable"`
+	LastError      string     `json:"last_error"`
+	DateCount      int        `json:"date_count"`
+}
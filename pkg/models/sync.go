@@ -0,0 +1,138 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SyncResponse lists what changed since the caller's sync token. When Full
+// is set it is a complete snapshot: the client replaces its local copy
+// instead of applying the lists as changes.
+type SyncResponse struct {
+	Token          string              `json:"token"`
+	Full           bool                `json:"full"`
+	HasMore        bool                `json:"has_more"`
+	Dates          DateChanges         `json:"dates"`
+	Availabilities AvailabilityChanges `json:"availabilities"`
+	Groups         GroupChanges        `json:"groups"`
+}
+
+type SyncDate struct {
+	DateResponse
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SyncAvailability struct {
+	AvailabilityResponse
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SyncGroup struct {
+	GroupResponse
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DateChanges struct {
+	Created []SyncDate `json:"created"`
+	Updated []SyncDate `json:"updated"`
+	Deleted []uint     `json:"deleted"`
+}
+
+type AvailabilityChanges struct {
+	Created []SyncAvailability `json:"created"`
+	Updated []SyncAvailability `json:"updated"`
+	Deleted []uint             `json:"deleted"`
+}
+
+type GroupChanges struct {
+	Created []SyncGroup `json:"created"`
+	Updated []SyncGroup `json:"updated"`
+	Deleted []uint      `json:"deleted"`
+}
+
+const (
+	StrategyServerWins = "server-wins"
+	StrategyClientWins = "client-wins"
+	StrategyMerge      = "merge"
+
+	MutationCreate = "create"
+	MutationUpdate = "update"
+	MutationDelete = "delete"
+
+	MutationApplied  = "applied"
+	MutationResolved = "resolved"
+	MutationConflict = "conflict"
+	MutationRejected = "rejected"
+
+	MaxMutations = 100
+)
+
+// ReplayRequest is a batch of writes a client queued while offline, in the
+// order they were made. Strategy applies to every mutation that does not
+// choose its own.
+type ReplayRequest struct {
+	Strategy  string     `json:"strategy"`
+	Mutations []Mutation `json:"mutations"`
+}
+
+// Mutation is one queued write. Version is the updated_at the client last
+// saw for the resource; Base holds the field values it last saw, which the
+// merge strategy needs to tell whose change each field is. Data and Base
+// only contain the fields being written, using the names of the date or
+// availability responses.
+type Mutation struct {
+	ClientID string                 `json:"client_id"`
+	Resource string                 `json:"resource"`
+	Action   string                 `json:"action"`
+	ID       uint                   `json:"id"`
+	Version  *time.Time             `json:"version" extensions:"x-nullable"`
+	Strategy string                 `json:"strategy"`
+	Data     map[string]interface{} `json:"data"`
+	Base     map[string]interface{} `json:"base"`
+}
+
+func (b *ReplayRequest) Bind(r *http.Request) error {
+	if len(b.Mutations) == 0 {
+		return errors.New("mutations must not be empty")
+	} else if len(b.Mutations) > MaxMutations {
+		return fmt.Errorf("at most %d mutations per batch", MaxMutations)
+	} else if !validStrategy(b.Strategy) {
+		return errors.New("unknown strategy " + b.Strategy)
+	}
+	for i, mutation := range b.Mutations {
+		if mutation.Resource != "date" && mutation.Resource != "availability" {
+			return fmt.Errorf("mutations[%d]: resource must be date or availability", i)
+		} else if mutation.Action != MutationCreate && mutation.Action != MutationUpdate && mutation.Action != MutationDelete {
+			return fmt.Errorf("mutations[%d]: action must be create, update or delete", i)
+		} else if mutation.Action != MutationCreate && (mutation.ID == 0 || mutation.Version == nil) {
+			return fmt.Errorf("mutations[%d]: id and version are required to %s", i, mutation.Action)
+		} else if !validStrategy(mutation.Strategy) {
+			return fmt.Errorf("mutations[%d]: unknown strategy %s", i, mutation.Strategy)
+		}
+	}
+	return nil
+}
+
+func validStrategy(strategy string) bool {
+	return strategy == "" || strategy == StrategyServerWins || strategy == StrategyClientWins || strategy == StrategyMerge
+}
+
+// MutationResult reports what happened to one mutation. On a conflict,
+// Server holds the current server value, Client the value the client
+// wanted and Fields the fields both sides changed.
+type MutationResult struct {
+	ClientID string                 `json:"client_id"`
+	Status   string                 `json:"status"`
+	ID       uint                   `json:"id,omitempty"`
+	Version  *time.Time             `json:"version,omitempty"`
+	Fields   []string               `json:"fields,omitempty"`
+	Server   map[string]interface{} `json:"server,omitempty"`
+	Client   map[string]interface{} `json:"client,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+type ReplayResponse struct {
+	Results []MutationResult `json:"results"`
+}
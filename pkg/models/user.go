@@ -3,14 +3,46 @@ package models
 import (
 	"errors"
 	"net/http"
+	"strings"
 )
 
+// ValidateUsername refuses usernames containing "@", which is how logins
+// that accept either tell an email from a username.
+func ValidateUsername(username string) error {
+	if strings.Contains(username, "@") {
+		return errors.New("username must not contain @")
+	}
+	return nil
+}
+
 type UserRequest struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+// UpdateUserRequest changes an account. CurrentPassword is the caller's
+// password, asked again since the change signs every session out.
+type UpdateUserRequest struct {
+	Username        string `json:"username"`
+	Email           string `json:"email"`
+	Password        string `json:"password"`
+	CurrentPassword string `json:"current_password"`
+}
+
+func (u *UpdateUserRequest) Bind(r *http.Request) error {
+	if u.Email == "" {
+		return errors.New("email must not be null")
+	} else if u.Password == "" {
+		return errors.New("password must not be null")
+	} else if u.Username == "" {
+		return errors.New("username must not be null")
+	} else if u.CurrentPassword == "" {
+		return errors.New("current_password must not be null")
+	}
+	return ValidateUsername(u.Username)
+}
+
 type GetUserRequest struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
@@ -34,4 +66,5 @@ type UserResponse struct {
 	Name     string `json:"name"`
 	Surname  string `json:"surname"`
 	ColorID  uint   `json:"color_id"`
+	Verified bool   `json:"verified"`
 }
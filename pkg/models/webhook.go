@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type WebhookRequest struct {
+	URL     string   `json:"url"`
+	GroupID uint     `json:"group_id"`
+	Events  []string `json:"events"`
+	Active  *bool    `json:"active"`
+}
+
+func (w *WebhookRequest) Bind(r *http.Request) error {
+	if w.URL == "" {
+		return errors.New("url must not be null")
+	}
+	return nil
+}
+
+type WebhookResponse struct {
+	ID               uint       `json:"id"`
+	URL              string     `json:"url"`
+	GroupID          uint       `json:"group_id"`
+	Events           []string   `json:"events"`
+	Active           bool       `json:"active"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastStatus       string     `json:"last_status"`
+	LastResponseCode int        `json:"last_response_code"`
+	LastDeliveredAt  *time.Time `json:"last_delivered_at" extensions:"x-nullable"`
+	Secret           string     `json:"secret,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID               uint       `json:"id"`
+	EventID          string     `json:"event_id"`
+	EventType        string     `json:"event_type"`
+	Status           string     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	NextAttemptAt    time.Time  `json:"next_attempt_at"`
+	LastResponseCode int        `json:"last_response_code"`
+	LastError        string     `json:"last_error"`
+	DeliveredAt      *time.Time `json:"delivered_at" extensions:"x-nullable"`
+	CreatedAt        time.Time  `json:"created_at"`
+	Payload          string     `json:"payload"`
+}
@@ -0,0 +1,80 @@
+// Package netguard keeps the requests the server makes on behalf of users,
+// such as webhook deliveries and calendar subscriptions, away from the
+// server's own network: loopback, link-local, private and unspecified
+// addresses are refused, when the URL is saved and again when connecting,
+// since DNS may answer differently between the two.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for hosts that resolve to an address
+// users may not reach through the server.
+var ErrForbiddenAddress = errors.New("address not allowed")
+
+// AllowPrivate reports whether ALLOW_PRIVATE_NETWORKS lifts the checks, for
+// local development against services on the same machine.
+func AllowPrivate() bool {
+	return os.Getenv("ALLOW_PRIVATE_NETWORKS") == "true"
+}
+
+// Allowed reports whether ip is a public address.
+func Allowed(ip net.IP) bool {
+	if AllowPrivate() {
+		return true
+	}
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// CheckURL resolves the host of an absolute URL and fails if any of its
+// addresses is not allowed.
+func CheckURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return errors.New("url must be absolute")
+	}
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s", parsed.Hostname())
+	}
+	for _, address := range addresses {
+		if !Allowed(address.IP) {
+			return fmt.Errorf("%s: %w", parsed.Hostname(), ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// NewClient returns an HTTP client that refuses to connect to addresses
+// that are not allowed, whatever the host resolved to when it was checked,
+// including after redirects.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !Allowed(ip) {
+				return fmt.Errorf("%s: %w", host, ErrForbiddenAddress)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
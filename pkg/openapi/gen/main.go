@@ -0,0 +1,114 @@
+// Command gen builds openapi.json from the swag annotations of the
+// controllers. It parses them as Swagger 2.0, converts the result to
+// OpenAPI 3 and prefixes every path with the base path, so that paths in
+// the document are the routes as mounted in main.go.
+//
+// An operation served outside the base path says so with
+//
+//	// @x-base-path "/"
+//
+// With -check, nothing is written and gen fails when the file is out of
+// date.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/swaggo/swag"
+)
+
+const basePathExtension = "x-base-path"
+
+func main() {
+	dir := flag.String("dir", ".", "Root of the module, where main.go is")
+	output := flag.String("o", "openapi.json", "File to write")
+	check := flag.Bool("check", false, "Fail if the file is out of date instead of writing it")
+	flag.Parse()
+
+	document, err := generate(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *check {
+		current, err := os.ReadFile(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !bytes.Equal(current, document) {
+			log.Fatalf("%s is out of date: run go generate ./pkg/openapi", *output)
+		}
+		return
+	}
+	if err := os.WriteFile(*output, document, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(dir string) ([]byte, error) {
+	parser := swag.New(swag.SetDebugger(log.New(io.Discard, "", 0)))
+	if err := parser.ParseAPI(dir, "main.go", 100); err != nil {
+		return nil, err
+	}
+	swagger, err := json.Marshal(parser.GetSwagger())
+	if err != nil {
+		return nil, err
+	}
+	doc2 := &openapi2.T{}
+	if err := json.Unmarshal(swagger, doc2); err != nil {
+		return nil, err
+	}
+	doc3, err := openapi2conv.ToV3(doc2)
+	if err != nil {
+		return nil, err
+	}
+	doc3.Servers = nil
+	if doc3.Paths, err = rebase(doc3.Paths, doc2.BasePath); err != nil {
+		return nil, err
+	}
+	if err := doc3.Validate(openapi3.NewLoader().Context); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	document, err := json.MarshalIndent(doc3, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(document, '\n'), nil
+}
+
+// rebase prefixes each path with the base path, or with the one given by
+// its operations' x-base-path extension.
+func rebase(paths *openapi3.Paths, basePath string) (*openapi3.Paths, error) {
+	rebased := openapi3.NewPathsWithCapacity(paths.Len())
+	for route, item := range paths.Map() {
+		prefix := basePath
+		for method, operation := range item.Operations() {
+			override, ok := operation.Extensions[basePathExtension]
+			if !ok {
+				continue
+			}
+			value, isString := override.(string)
+			if !isString {
+				return nil, fmt.Errorf("%s %s: %s must be a string", method, route, basePathExtension)
+			}
+			prefix = value
+			delete(operation.Extensions, basePathExtension)
+		}
+		full := path.Join(prefix, route)
+		if strings.HasSuffix(route, "/") && !strings.HasSuffix(full, "/") {
+			full += "/"
+		}
+		rebased.Set(full, item)
+	}
+	return rebased, nil
+}
@@ -0,0 +1,82 @@
+// Package openapi serves the OpenAPI 3 description of the API, generated
+// from the swag annotations of the controllers, and checks requests and
+// responses against it.
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+//go:generate go run ./gen -dir ../.. -o openapi.json
+
+//go:embed openapi.json
+var document []byte
+
+var (
+	loadOnce sync.Once
+	loaded   *openapi3.T
+	loadErr  error
+)
+
+// Load returns the parsed document.
+func Load() (*openapi3.T, error) {
+	loadOnce.Do(func() {
+		loaded, loadErr = openapi3.NewLoader().LoadFromData(document)
+	})
+	return loaded, loadErr
+}
+
+// @Summary		Get the OpenAPI document
+// @Description	OpenAPI 3 description of this API, generated from the annotations of the controllers
+// @Tags		openapi
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}
+// @Router		/openapi.json [get]
+func Document(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(document)
+}
+
+// CheckRoutes compares the routes of router with the document and
+// describes each route under /api that is not documented and each
+// documented operation that is not routed.
+func CheckRoutes(router chi.Routes) ([]string, error) {
+	doc, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	documented := make(map[string]bool)
+	for route, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+route] = true
+		}
+	}
+
+	var problems []string
+	err = chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route = strings.ReplaceAll(route, "/*/", "/")
+		operation := method + " " + route
+		if documented[operation] {
+			delete(documented, operation)
+		} else if strings.HasPrefix(route, "/api/") {
+			problems = append(problems, fmt.Sprintf("%s is not documented", operation))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for operation := range documented {
+		problems = append(problems, fmt.Sprintf("%s is documented but not routed", operation))
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
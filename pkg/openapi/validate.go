@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// Mode says what Validator does with requests and responses that do not
+// match the document.
+type Mode string
+
+const (
+	// ModeOff skips validation.
+	ModeOff Mode = "off"
+	// ModeReport logs mismatches and lets them through.
+	ModeReport Mode = "report"
+	// ModeEnforce rejects mismatching requests with 400 and replaces
+	// mismatching responses with a 500.
+	ModeEnforce Mode = "enforce"
+)
+
+// ParseMode reads a mode; the empty string is ModeOff.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(value); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeReport, ModeEnforce:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown OpenAPI validation mode %q: use off, report or enforce", value)
+}
+
+// Validator checks documented requests and their successful responses
+// against the document. Only JSON bodies are checked: files, calendars and
+// event streams pass through untouched, as do error responses, which are
+// plain text. Authentication is left to the authentication middleware.
+func Validator(mode Mode) func(http.Handler) http.Handler {
+	if mode == ModeOff {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	doc, err := Load()
+	if err != nil {
+		panic("openapi: invalid embedded document: " + err.Error())
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		panic("openapi: " + err.Error())
+	}
+	validator := &validator{mode: mode, router: router}
+	return validator.middleware
+}
+
+type validator struct {
+	mode   Mode
+	router routers.Router
+}
+
+func (validator *validator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := validator.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				ExcludeRequestBody: !isJSON(r.Header.Get("Content-Type")),
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			log.Printf("OpenAPI: %s %s: invalid request: %v", r.Method, r.URL.Path, err)
+			if validator.mode == ModeEnforce {
+				http.Error(w, "Request does not match the API specification: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if !returnsJSON(route.Operation) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		if recorder.status < 300 && isJSON(recorder.header.Get("Content-Type")) {
+			err := openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 recorder.status,
+				Header:                 recorder.header,
+				Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+				Options:                &openapi3filter.Options{MultiError: true},
+			})
+			if err != nil {
+				log.Printf("OpenAPI: %s %s: invalid response: %v", r.Method, r.URL.Path, err)
+				if validator.mode == ModeEnforce {
+					http.Error(w, "Response does not match the API specification", http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+		for key, values := range recorder.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.status)
+		w.Write(recorder.body.Bytes())
+	})
+}
+
+// returnsJSON reports whether the successful responses of an operation
+// are JSON, so that buffering them cannot hold back a stream or a protocol
+// switch.
+func returnsJSON(operation *openapi3.Operation) bool {
+	for status, response := range operation.Responses.Map() {
+		if status == "101" {
+			return false
+		}
+		if len(status) == 0 || status[0] != '2' || response.Value == nil {
+			continue
+		}
+		for contentType := range response.Value.Content {
+			if !isJSON(contentType) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// responseRecorder holds a response back until it has been validated.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (recorder *responseRecorder) Header() http.Header {
+	return recorder.header
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	recorder.status = status
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	return recorder.body.Write(data)
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/authorization"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userKey struct{}
+
+type authenticator struct {
+	*config.Config
+	keys *authentication.Keys
+}
+
+// intercept validates the access token from the "authorization" metadata,
+// the same way AuthMiddleware does for HTTP, and stores the caller in the
+// context.
+func (auth *authenticator) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+	accessToken, err := authentication.ParseAccessToken(auth.keys, values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if err := authentication.CheckSession(auth.SessionRepository, accessToken.SessionID); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "session expired")
+	}
+	user, err := accessToken.FindUser(auth.UserRepository)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "user not found")
+	}
+	return handler(context.WithValue(ctx, userKey{}, user), req)
+}
+
+func callerFrom(ctx context.Context) *dbmodel.User {
+	return ctx.Value(userKey{}).(*dbmodel.User)
+}
+
+// checkVisible fails with NotFound unless the caller may see userID, by the
+// same rules as the REST API.
+func checkVisible(cfg *config.Config, caller *dbmodel.User, userID uint) error {
+	visible, err := authorization.CanSeeUser(cfg.GroupRepository, caller, userID)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to retrieve groups")
+	}
+	if !visible {
+		return status.Error(codes.NotFound, "user not found")
+	}
+	return nil
+}
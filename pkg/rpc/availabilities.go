@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"context"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type availabilityServer struct {
+	pb.UnimplementedAvailabilityServiceServer
+	*config.Config
+}
+
+func (config *availabilityServer) ListAvailabilities(ctx context.Context, req *pb.ListAvailabilitiesRequest) (*pb.ListAvailabilitiesResponse, error) {
+	caller := callerFrom(ctx)
+	userID := userOrCaller(req.UserId, caller)
+	if err := checkVisible(config.Config, caller, userID); err != nil {
+		return nil, err
+	}
+	availabilities, err := config.AvailabilityRepository.FindByUserIDsInRange([]uint{userID}, fromTimestamp(req.From), fromTimestamp(req.To))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to retrieve availabilities")
+	}
+	response := &pb.ListAvailabilitiesResponse{}
+	for i := range availabilities {
+		response.Availabilities = append(response.Availabilities, toAvailability(&availabilities[i]))
+	}
+	return response, nil
+}
+
+func (config *availabilityServer) CreateAvailability(ctx context.Context, req *pb.CreateAvailabilityRequest) (*pb.Availability, error) {
+	if err := validateRange(req.Begin, req.End); err != nil {
+		return nil, err
+	}
+	availability := &dbmodel.Availability{
+		UserID:    callerFrom(ctx).ID,
+		BeginTime: req.Begin.AsTime(),
+		EndTime:   req.End.AsTime(),
+	}
+	createdAvailability, err := config.AvailabilityRepository.Create(availability)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create availability")
+	}
+	config.Events.Publish(events.AvailabilityEvent(events.ActionCreated, createdAvailability))
+	return toAvailability(createdAvailability), nil
+}
+
+func (config *availabilityServer) DeleteAvailability(ctx context.Context, req *pb.DeleteAvailabilityRequest) (*emptypb.Empty, error) {
+	availability, err := config.AvailabilityRepository.FindByID(uint(req.Id))
+	if err != nil || availability.UserID != callerFrom(ctx).ID {
+		return nil, status.Error(codes.NotFound, "availability not found")
+	}
+	if err := config.AvailabilityRepository.DeleteByID(availability.ID); err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete availability")
+	}
+	config.Events.Publish(events.AvailabilityEvent(events.ActionDeleted, availability))
+	return &emptypb.Empty{}, nil
+}
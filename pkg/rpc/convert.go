@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"time"
+
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toUser(user *dbmodel.User) *pb.User {
+	return &pb.User{
+		Id:       uint64(user.ID),
+		Username: user.Username,
+		Email:    user.Email,
+		Name:     user.Name,
+		Surname:  user.Surname,
+		ColorId:  uint64(user.ColorID),
+	}
+}
+
+func toGroup(group *dbmodel.Group) *pb.Group {
+	return &pb.Group{
+		Id:        uint64(group.ID),
+		Name:      group.Name,
+		CreatorId: uint64(group.CreatorID),
+	}
+}
+
+// toDate shows other users' private dates as busy time.
+func toDate(date *dbmodel.Date, callerID uint) *pb.Date {
+	message := &pb.Date{
+		Id:             uint64(date.ID),
+		Title:          date.Title,
+		Body:           date.Body,
+		Begin:          timestamppb.New(date.BeginTime),
+		End:            timestamppb.New(date.EndTime),
+		UserId:         uint64(date.UserID),
+		Private:        date.Private,
+		RecurrenceId:   uint64(date.RecurrenceID),
+		ColorId:        uint64(date.ColorID),
+		SubscriptionId: uint64(date.SubscriptionID),
+	}
+	if date.Private && date.UserID != callerID {
+		message.Title, message.Body = "Busy", ""
+	}
+	return message
+}
+
+func toAvailability(availability *dbmodel.Availability) *pb.Availability {
+	return &pb.Availability{
+		Id:     uint64(availability.ID),
+		UserId: uint64(availability.UserID),
+		Begin:  timestamppb.New(availability.BeginTime),
+		End:    timestamppb.New(availability.EndTime),
+	}
+}
+
+func toPeriod(begin time.Time, end time.Time) *pb.Period {
+	return &pb.Period{Begin: timestamppb.New(begin), End: timestamppb.New(end)}
+}
+
+// fromTimestamp returns the zero time for a missing timestamp, which the
+// range queries treat as an open bound.
+func fromTimestamp(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return timestamp.AsTime()
+}
+
+func validateRange(begin *timestamppb.Timestamp, end *timestamppb.Timestamp) error {
+	if begin == nil {
+		return status.Error(codes.InvalidArgument, "begin must not be null")
+	} else if end == nil {
+		return status.Error(codes.InvalidArgument, "end must not be null")
+	} else if end.AsTime().Before(begin.AsTime()) {
+		return status.Error(codes.InvalidArgument, "end must not be before begin")
+	}
+	return nil
+}
+
+// userOrCaller defaults an omitted user ID to the caller.
+func userOrCaller(userID uint64, caller *dbmodel.User) uint {
+	if userID == 0 {
+		return caller.ID
+	}
+	return uint(userID)
+}
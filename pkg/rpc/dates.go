@@ -0,0 +1,164 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/ical"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type dateServer struct {
+	pb.UnimplementedDateServiceServer
+	*config.Config
+}
+
+func (config *dateServer) ListDates(ctx context.Context, req *pb.ListDatesRequest) (*pb.ListDatesResponse, error) {
+	caller := callerFrom(ctx)
+	userID := userOrCaller(req.UserId, caller)
+	if err := checkVisible(config.Config, caller, userID); err != nil {
+		return nil, err
+	}
+	dates, err := config.DateRepository.FindByUserIDsInRange([]uint{userID}, fromTimestamp(req.From), fromTimestamp(req.To))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to retrieve dates")
+	}
+	response := &pb.ListDatesResponse{}
+	for i := range dates {
+		response.Dates = append(response.Dates, toDate(&dates[i], caller.ID))
+	}
+	return response, nil
+}
+
+func (config *dateServer) GetDate(ctx context.Context, req *pb.GetDateRequest) (*pb.Date, error) {
+	caller := callerFrom(ctx)
+	date, err := config.DateRepository.FindByID(uint(req.Id))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "date not found")
+	}
+	if err := checkVisible(config.Config, caller, date.UserID); err != nil {
+		return nil, status.Error(codes.NotFound, "date not found")
+	}
+	return toDate(date, caller.ID), nil
+}
+
+func (config *dateServer) CreateDate(ctx context.Context, req *pb.CreateDateRequest) (*pb.Date, error) {
+	caller := callerFrom(ctx)
+	if req.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "title must not be null")
+	}
+	if err := validateRange(req.Begin, req.End); err != nil {
+		return nil, err
+	}
+	if err := config.usableColor(caller, uint(req.ColorId)); err != nil {
+		return nil, err
+	}
+	date := &dbmodel.Date{
+		Title:     req.Title,
+		Body:      req.Body,
+		BeginTime: req.Begin.AsTime(),
+		EndTime:   req.End.AsTime(),
+		UserID:    caller.ID,
+		Private:   req.Private,
+		ColorID:   uint(req.ColorId),
+	}
+	createdDate, err := config.DateRepository.CreateWithOccurrences(date, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create date")
+	}
+	config.Events.Publish(events.DateEvent(events.ActionCreated, createdDate))
+	return toDate(createdDate, caller.ID), nil
+}
+
+func (config *dateServer) UpdateDate(ctx context.Context, req *pb.UpdateDateRequest) (*pb.Date, error) {
+	caller := callerFrom(ctx)
+	date, err := config.writableDate(uint(req.Id), caller.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "title must not be null")
+	}
+	if err := validateRange(req.Begin, req.End); err != nil {
+		return nil, err
+	}
+	if uint(req.ColorId) != date.ColorID {
+		if err := config.usableColor(caller, uint(req.ColorId)); err != nil {
+			return nil, err
+		}
+	}
+	date.Title = req.Title
+	date.Body = req.Body
+	date.BeginTime = req.Begin.AsTime()
+	date.EndTime = req.End.AsTime()
+	date.Private = req.Private
+	date.ColorID = uint(req.ColorId)
+	occurrences, err := ical.DateOccurrences(date)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid recurrence: "+err.Error())
+	}
+	if err := config.DateRepository.SaveWithOccurrences(date, occurrences); err != nil {
+		return nil, writeError(err, "failed to update date")
+	}
+	config.Events.Publish(events.DateEvent(events.ActionUpdated, date))
+	return toDate(date, caller.ID), nil
+}
+
+func (config *dateServer) DeleteDate(ctx context.Context, req *pb.DeleteDateRequest) (*emptypb.Empty, error) {
+	date, err := config.writableDate(uint(req.Id), callerFrom(ctx).ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.DateRepository.DeleteWithOccurrences(date); err != nil {
+		return nil, writeError(err, "failed to delete date")
+	}
+	config.Events.Publish(events.DateEvent(events.ActionDeleted, date))
+	return &emptypb.Empty{}, nil
+}
+
+// writableDate only lets callers change their own dates, and rejects
+// changes to dates mirrored from a calendar subscription.
+func (config *dateServer) writableDate(id uint, userID uint) (*dbmodel.Date, error) {
+	date, err := config.DateRepository.FindByID(id)
+	if err != nil || date.UserID != userID {
+		return nil, status.Error(codes.NotFound, "date not found")
+	}
+	if date.SubscriptionID != 0 {
+		return nil, status.Error(codes.FailedPrecondition, "date is read-only: it is mirrored from a calendar subscription")
+	}
+	return date, nil
+}
+
+// usableColor rejects the colors the caller may not give to a date: the
+// other users' ones and those that do not exist.
+func (config *dateServer) usableColor(caller *dbmodel.User, colorID uint) error {
+	usable, err := authorization.CanUseColor(config.ColorRepository, caller, colorID)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to retrieve color")
+	}
+	if !usable {
+		return status.Error(codes.InvalidArgument, "color not found")
+	}
+	return nil
+}
+
+// writeError reports a failed date write, telling apart the writes to one
+// occurrence of a recurring date, which are regenerated from it, and those
+// that lost the race to another write.
+func writeError(err error, message string) error {
+	if errors.Is(err, dbmodel.ErrOccurrence) {
+		return status.Error(codes.FailedPrecondition, "date is an occurrence of a recurring date: change the recurring date instead")
+	}
+	if errors.Is(err, dbmodel.ErrModified) {
+		return status.Error(codes.Aborted, "date was changed by another request, try again")
+	}
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/freebusy"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type freeBusyServer struct {
+	pb.UnimplementedFreeBusyServiceServer
+	*config.Config
+}
+
+// GetFreeBusy reports the busy periods of each user and the gaps between
+// the busy periods of all users.
+func (config *freeBusyServer) GetFreeBusy(ctx context.Context, req *pb.FreeBusyRequest) (*pb.FreeBusyResponse, error) {
+	caller := callerFrom(ctx)
+	if err := validateRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+	from, to := req.From.AsTime(), req.To.AsTime()
+	userIDs := make([]uint, 0, len(req.UserIds))
+	for _, userID := range req.UserIds {
+		userIDs = append(userIDs, uint(userID))
+	}
+	if len(userIDs) == 0 {
+		userIDs = append(userIDs, caller.ID)
+	}
+	for _, userID := range userIDs {
+		if err := checkVisible(config.Config, caller, userID); err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil, status.Errorf(codes.NotFound, "user %d not found", userID)
+			}
+			return nil, err
+		}
+	}
+	dates, err := config.DateRepository.FindByUserIDsInRange(userIDs, from, to)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to retrieve dates")
+	}
+
+	byUser := make(map[uint][]dbmodel.Date, len(userIDs))
+	for _, date := range dates {
+		byUser[date.UserID] = append(byUser[date.UserID], date)
+	}
+	response := &pb.FreeBusyResponse{}
+	for _, userID := range userIDs {
+		userBusy := &pb.UserBusy{UserId: uint64(userID)}
+		for _, period := range freebusy.Busy(byUser[userID], from, to) {
+			userBusy.Busy = append(userBusy.Busy, toPeriod(period.Begin, period.End))
+		}
+		response.Users = append(response.Users, userBusy)
+	}
+	for _, period := range freebusy.Free(freebusy.Busy(dates, from, to), from, to, 0) {
+		response.Free = append(response.Free, toPeriod(period.Begin, period.End))
+	}
+	return response, nil
+}
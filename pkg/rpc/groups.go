@@ -0,0 +1,88 @@
+package rpc
+
+import (
+	"context"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type groupServer struct {
+	pb.UnimplementedGroupServiceServer
+	*config.Config
+}
+
+func (config *groupServer) ListGroups(ctx context.Context, _ *emptypb.Empty) (*pb.ListGroupsResponse, error) {
+	groups, err := config.GroupRepository.FindByMemberID(callerFrom(ctx).ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to retrieve groups")
+	}
+	response := &pb.ListGroupsResponse{}
+	for i := range groups {
+		response.Groups = append(response.Groups, toGroup(&groups[i]))
+	}
+	return response, nil
+}
+
+func (config *groupServer) GetGroup(ctx context.Context, req *pb.GetGroupRequest) (*pb.Group, error) {
+	if err := config.checkMember(uint(req.Id), callerFrom(ctx)); err != nil {
+		return nil, err
+	}
+	group, err := config.GroupRepository.FindByID(uint(req.Id))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "group not found")
+	}
+	return toGroup(group), nil
+}
+
+// ListMembers lists the creator first, then the members in the order they
+// joined. Guests may not list them.
+func (config *groupServer) ListMembers(ctx context.Context, req *pb.ListMembersRequest) (*pb.ListMembersResponse, error) {
+	if err := config.checkMember(uint(req.GroupId), callerFrom(ctx)); err != nil {
+		return nil, err
+	}
+	group, err := config.GroupRepository.FindByID(uint(req.GroupId))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "group not found")
+	}
+	if allowed, err := authorization.CanInGroup(config.UserGroupRepository, callerFrom(ctx), group, authorization.SeeMembers); err != nil || !allowed {
+		return nil, status.Error(codes.PermissionDenied, "guests cannot list the members")
+	}
+	userGroups, err := config.UserGroupRepository.FindByGroupID(group.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to retrieve members")
+	}
+	response := &pb.ListMembersResponse{}
+	creator := &pb.Member{UserId: uint64(group.CreatorID), GroupId: uint64(group.ID)}
+	response.Members = append(response.Members, creator)
+	for _, userGroup := range userGroups {
+		if userGroup.UserID == group.CreatorID {
+			creator.ColorId = uint64(userGroup.ColorID)
+			continue
+		}
+		response.Members = append(response.Members, &pb.Member{
+			UserId:  uint64(userGroup.UserID),
+			GroupId: uint64(userGroup.GroupID),
+			ColorId: uint64(userGroup.ColorID),
+		})
+	}
+	return response, nil
+}
+
+// checkMember hides the groups the caller does not belong to.
+func (config *groupServer) checkMember(groupID uint, caller *dbmodel.User) error {
+	member, err := authorization.CanSeeGroup(config.GroupRepository, caller, groupID)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to retrieve group")
+	}
+	if !member {
+		return status.Error(codes.NotFound, "group not found")
+	}
+	return nil
+}
@@ -0,0 +1,1667 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: yplanning.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Surname       string                 `protobuf:"bytes,5,opt,name=surname,proto3" json:"surname,omitempty"`
+	ColorId       uint64                 `protobuf:"varint,6,opt,name=color_id,json=colorId,proto3" json:"color_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_yplanning_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetSurname() string {
+	if x != nil {
+		return x.Surname
+	}
+	return ""
+}
+
+func (x *User) GetColorId() uint64 {
+	if x != nil {
+		return x.ColorId
+	}
+	return 0
+}
+
+type Group struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatorId     uint64                 `protobuf:"varint,3,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_yplanning_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Group) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Group) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Group) GetCreatorId() uint64 {
+	if x != nil {
+		return x.CreatorId
+	}
+	return 0
+}
+
+type Member struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GroupId uint64                 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// The color the member picked for this group, 0 if none.
+	ColorId       uint64 `protobuf:"varint,3,opt,name=color_id,json=colorId,proto3" json:"color_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Member) Reset() {
+	*x = Member{}
+	mi := &file_yplanning_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Member) ProtoMessage() {}
+
+func (x *Member) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Member.ProtoReflect.Descriptor instead.
+func (*Member) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Member) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Member) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *Member) GetColorId() uint64 {
+	if x != nil {
+		return x.ColorId
+	}
+	return 0
+}
+
+type Date struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body           string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Begin          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=begin,proto3" json:"begin,omitempty"`
+	End            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	UserId         uint64                 `protobuf:"varint,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Private        bool                   `protobuf:"varint,7,opt,name=private,proto3" json:"private,omitempty"`
+	RecurrenceId   uint64                 `protobuf:"varint,8,opt,name=recurrence_id,json=recurrenceId,proto3" json:"recurrence_id,omitempty"`
+	ColorId        uint64                 `protobuf:"varint,9,opt,name=color_id,json=colorId,proto3" json:"color_id,omitempty"`
+	SubscriptionId uint64                 `protobuf:"varint,10,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Date) Reset() {
+	*x = Date{}
+	mi := &file_yplanning_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Date) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Date) ProtoMessage() {}
+
+func (x *Date) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Date.ProtoReflect.Descriptor instead.
+func (*Date) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Date) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Date) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Date) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Date) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *Date) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Date) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Date) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *Date) GetRecurrenceId() uint64 {
+	if x != nil {
+		return x.RecurrenceId
+	}
+	return 0
+}
+
+func (x *Date) GetColorId() uint64 {
+	if x != nil {
+		return x.ColorId
+	}
+	return 0
+}
+
+func (x *Date) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+type Availability struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        uint64                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Begin         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=begin,proto3" json:"begin,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Availability) Reset() {
+	*x = Availability{}
+	mi := &file_yplanning_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Availability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Availability) ProtoMessage() {}
+
+func (x *Availability) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Availability.ProtoReflect.Descriptor instead.
+func (*Availability) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Availability) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Availability) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Availability) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *Availability) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type Period struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Begin         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=begin,proto3" json:"begin,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Period) Reset() {
+	*x = Period{}
+	mi := &file_yplanning_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Period) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Period) ProtoMessage() {}
+
+func (x *Period) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Period.ProtoReflect.Descriptor instead.
+func (*Period) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Period) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *Period) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_yplanning_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUserRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupRequest) Reset() {
+	*x = GetGroupRequest{}
+	mi := &file_yplanning_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupRequest) ProtoMessage() {}
+
+func (x *GetGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetGroupRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetGroupRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*Group               `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsResponse) Reset() {
+	*x = ListGroupsResponse{}
+	mi := &file_yplanning_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsResponse) ProtoMessage() {}
+
+func (x *ListGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListGroupsResponse) GetGroups() []*Group {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type ListMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       uint64                 `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembersRequest) Reset() {
+	*x = ListMembersRequest{}
+	mi := &file_yplanning_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembersRequest) ProtoMessage() {}
+
+func (x *ListMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListMembersRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListMembersRequest) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+type ListMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*Member              `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembersResponse) Reset() {
+	*x = ListMembersResponse{}
+	mi := &file_yplanning_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembersResponse) ProtoMessage() {}
+
+func (x *ListMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListMembersResponse) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListMembersResponse) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+// A missing bound leaves that side of the range open.
+type ListDatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDatesRequest) Reset() {
+	*x = ListDatesRequest{}
+	mi := &file_yplanning_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDatesRequest) ProtoMessage() {}
+
+func (x *ListDatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDatesRequest.ProtoReflect.Descriptor instead.
+func (*ListDatesRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListDatesRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListDatesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListDatesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ListDatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dates         []*Date                `protobuf:"bytes,1,rep,name=dates,proto3" json:"dates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDatesResponse) Reset() {
+	*x = ListDatesResponse{}
+	mi := &file_yplanning_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDatesResponse) ProtoMessage() {}
+
+func (x *ListDatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDatesResponse.ProtoReflect.Descriptor instead.
+func (*ListDatesResponse) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListDatesResponse) GetDates() []*Date {
+	if x != nil {
+		return x.Dates
+	}
+	return nil
+}
+
+type GetDateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDateRequest) Reset() {
+	*x = GetDateRequest{}
+	mi := &file_yplanning_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDateRequest) ProtoMessage() {}
+
+func (x *GetDateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDateRequest.ProtoReflect.Descriptor instead.
+func (*GetDateRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDateRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateDateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	Begin         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=begin,proto3" json:"begin,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	Private       bool                   `protobuf:"varint,5,opt,name=private,proto3" json:"private,omitempty"`
+	ColorId       uint64                 `protobuf:"varint,6,opt,name=color_id,json=colorId,proto3" json:"color_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDateRequest) Reset() {
+	*x = CreateDateRequest{}
+	mi := &file_yplanning_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDateRequest) ProtoMessage() {}
+
+func (x *CreateDateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDateRequest.ProtoReflect.Descriptor instead.
+func (*CreateDateRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateDateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateDateRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CreateDateRequest) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *CreateDateRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *CreateDateRequest) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *CreateDateRequest) GetColorId() uint64 {
+	if x != nil {
+		return x.ColorId
+	}
+	return 0
+}
+
+// All fields are replaced.
+type UpdateDateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Begin         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=begin,proto3" json:"begin,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Private       bool                   `protobuf:"varint,6,opt,name=private,proto3" json:"private,omitempty"`
+	ColorId       uint64                 `protobuf:"varint,7,opt,name=color_id,json=colorId,proto3" json:"color_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDateRequest) Reset() {
+	*x = UpdateDateRequest{}
+	mi := &file_yplanning_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDateRequest) ProtoMessage() {}
+
+func (x *UpdateDateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDateRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateDateRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateDateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateDateRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *UpdateDateRequest) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *UpdateDateRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *UpdateDateRequest) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *UpdateDateRequest) GetColorId() uint64 {
+	if x != nil {
+		return x.ColorId
+	}
+	return 0
+}
+
+type DeleteDateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDateRequest) Reset() {
+	*x = DeleteDateRequest{}
+	mi := &file_yplanning_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDateRequest) ProtoMessage() {}
+
+func (x *DeleteDateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDateRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDateRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteDateRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// A missing bound leaves that side of the range open.
+type ListAvailabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAvailabilitiesRequest) Reset() {
+	*x = ListAvailabilitiesRequest{}
+	mi := &file_yplanning_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAvailabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAvailabilitiesRequest) ProtoMessage() {}
+
+func (x *ListAvailabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAvailabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListAvailabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListAvailabilitiesRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListAvailabilitiesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListAvailabilitiesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ListAvailabilitiesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Availabilities []*Availability        `protobuf:"bytes,1,rep,name=availabilities,proto3" json:"availabilities,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListAvailabilitiesResponse) Reset() {
+	*x = ListAvailabilitiesResponse{}
+	mi := &file_yplanning_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAvailabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAvailabilitiesResponse) ProtoMessage() {}
+
+func (x *ListAvailabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAvailabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*ListAvailabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListAvailabilitiesResponse) GetAvailabilities() []*Availability {
+	if x != nil {
+		return x.Availabilities
+	}
+	return nil
+}
+
+type CreateAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Begin         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=begin,proto3" json:"begin,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAvailabilityRequest) Reset() {
+	*x = CreateAvailabilityRequest{}
+	mi := &file_yplanning_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAvailabilityRequest) ProtoMessage() {}
+
+func (x *CreateAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*CreateAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateAvailabilityRequest) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *CreateAvailabilityRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type DeleteAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAvailabilityRequest) Reset() {
+	*x = DeleteAvailabilityRequest{}
+	mi := &file_yplanning_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAvailabilityRequest) ProtoMessage() {}
+
+func (x *DeleteAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteAvailabilityRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type FreeBusyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []uint64               `protobuf:"varint,1,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyRequest) Reset() {
+	*x = FreeBusyRequest{}
+	mi := &file_yplanning_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyRequest) ProtoMessage() {}
+
+func (x *FreeBusyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyRequest.ProtoReflect.Descriptor instead.
+func (*FreeBusyRequest) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FreeBusyRequest) GetUserIds() []uint64 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type UserBusy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Busy          []*Period              `protobuf:"bytes,2,rep,name=busy,proto3" json:"busy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserBusy) Reset() {
+	*x = UserBusy{}
+	mi := &file_yplanning_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserBusy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserBusy) ProtoMessage() {}
+
+func (x *UserBusy) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserBusy.ProtoReflect.Descriptor instead.
+func (*UserBusy) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UserBusy) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserBusy) GetBusy() []*Period {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+type FreeBusyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserBusy            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Free          []*Period              `protobuf:"bytes,2,rep,name=free,proto3" json:"free,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyResponse) Reset() {
+	*x = FreeBusyResponse{}
+	mi := &file_yplanning_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyResponse) ProtoMessage() {}
+
+func (x *FreeBusyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_yplanning_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyResponse.ProtoReflect.Descriptor instead.
+func (*FreeBusyResponse) Descriptor() ([]byte, []int) {
+	return file_yplanning_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *FreeBusyResponse) GetUsers() []*UserBusy {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *FreeBusyResponse) GetFree() []*Period {
+	if x != nil {
+		return x.Free
+	}
+	return nil
+}
+
+var File_yplanning_proto protoreflect.FileDescriptor
+
+const file_yplanning_proto_rawDesc = "" +
+	"\n" +
+	"\x0fyplanning.proto\x12\fyplanning.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x91\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x18\n" +
+	"\asurname\x18\x05 \x01(\tR\asurname\x12\x19\n" +
+	"\bcolor_id\x18\x06 \x01(\x04R\acolorId\"J\n" +
+	"\x05Group\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"creator_id\x18\x03 \x01(\x04R\tcreatorId\"W\n" +
+	"\x06Member\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\x04R\agroupId\x12\x19\n" +
+	"\bcolor_id\x18\x03 \x01(\x04R\acolorId\"\xbc\x02\n" +
+	"\x04Date\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x120\n" +
+	"\x05begin\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\x04R\x06userId\x12\x18\n" +
+	"\aprivate\x18\a \x01(\bR\aprivate\x12#\n" +
+	"\rrecurrence_id\x18\b \x01(\x04R\frecurrenceId\x12\x19\n" +
+	"\bcolor_id\x18\t \x01(\x04R\acolorId\x12'\n" +
+	"\x0fsubscription_id\x18\n" +
+	" \x01(\x04R\x0esubscriptionId\"\x97\x01\n" +
+	"\fAvailability\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x04R\x06userId\x120\n" +
+	"\x05begin\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"h\n" +
+	"\x06Period\x120\n" +
+	"\x05begin\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\" \n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"!\n" +
+	"\x0fGetGroupRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"A\n" +
+	"\x12ListGroupsResponse\x12+\n" +
+	"\x06groups\x18\x01 \x03(\v2\x13.yplanning.v1.GroupR\x06groups\"/\n" +
+	"\x12ListMembersRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x04R\agroupId\"E\n" +
+	"\x13ListMembersResponse\x12.\n" +
+	"\amembers\x18\x01 \x03(\v2\x14.yplanning.v1.MemberR\amembers\"\x87\x01\n" +
+	"\x10ListDatesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"=\n" +
+	"\x11ListDatesResponse\x12(\n" +
+	"\x05dates\x18\x01 \x03(\v2\x12.yplanning.v1.DateR\x05dates\" \n" +
+	"\x0eGetDateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xd2\x01\n" +
+	"\x11CreateDateRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x120\n" +
+	"\x05begin\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x18\n" +
+	"\aprivate\x18\x05 \x01(\bR\aprivate\x12\x19\n" +
+	"\bcolor_id\x18\x06 \x01(\x04R\acolorId\"\xe2\x01\n" +
+	"\x11UpdateDateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x120\n" +
+	"\x05begin\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x18\n" +
+	"\aprivate\x18\x06 \x01(\bR\aprivate\x12\x19\n" +
+	"\bcolor_id\x18\a \x01(\x04R\acolorId\"#\n" +
+	"\x11DeleteDateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x90\x01\n" +
+	"\x19ListAvailabilitiesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"`\n" +
+	"\x1aListAvailabilitiesResponse\x12B\n" +
+	"\x0eavailabilities\x18\x01 \x03(\v2\x1a.yplanning.v1.AvailabilityR\x0eavailabilities\"{\n" +
+	"\x19CreateAvailabilityRequest\x120\n" +
+	"\x05begin\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"+\n" +
+	"\x19DeleteAvailabilityRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x88\x01\n" +
+	"\x0fFreeBusyRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\x04R\auserIds\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"M\n" +
+	"\bUserBusy\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12(\n" +
+	"\x04busy\x18\x02 \x03(\v2\x14.yplanning.v1.PeriodR\x04busy\"j\n" +
+	"\x10FreeBusyResponse\x12,\n" +
+	"\x05users\x18\x01 \x03(\v2\x16.yplanning.v1.UserBusyR\x05users\x12(\n" +
+	"\x04free\x18\x02 \x03(\v2\x14.yplanning.v1.PeriodR\x04free2\x7f\n" +
+	"\vUserService\x123\n" +
+	"\x05GetMe\x12\x16.google.protobuf.Empty\x1a\x12.yplanning.v1.User\x12;\n" +
+	"\aGetUser\x12\x1c.yplanning.v1.GetUserRequest\x1a\x12.yplanning.v1.User2\xea\x01\n" +
+	"\fGroupService\x12F\n" +
+	"\n" +
+	"ListGroups\x12\x16.google.protobuf.Empty\x1a .yplanning.v1.ListGroupsResponse\x12>\n" +
+	"\bGetGroup\x12\x1d.yplanning.v1.GetGroupRequest\x1a\x13.yplanning.v1.Group\x12R\n" +
+	"\vListMembers\x12 .yplanning.v1.ListMembersRequest\x1a!.yplanning.v1.ListMembersResponse2\xe5\x02\n" +
+	"\vDateService\x12L\n" +
+	"\tListDates\x12\x1e.yplanning.v1.ListDatesRequest\x1a\x1f.yplanning.v1.ListDatesResponse\x12;\n" +
+	"\aGetDate\x12\x1c.yplanning.v1.GetDateRequest\x1a\x12.yplanning.v1.Date\x12A\n" +
+	"\n" +
+	"CreateDate\x12\x1f.yplanning.v1.CreateDateRequest\x1a\x12.yplanning.v1.Date\x12A\n" +
+	"\n" +
+	"UpdateDate\x12\x1f.yplanning.v1.UpdateDateRequest\x1a\x12.yplanning.v1.Date\x12E\n" +
+	"\n" +
+	"DeleteDate\x12\x1f.yplanning.v1.DeleteDateRequest\x1a\x16.google.protobuf.Empty2\xb0\x02\n" +
+	"\x13AvailabilityService\x12g\n" +
+	"\x12ListAvailabilities\x12'.yplanning.v1.ListAvailabilitiesRequest\x1a(.yplanning.v1.ListAvailabilitiesResponse\x12Y\n" +
+	"\x12CreateAvailability\x12'.yplanning.v1.CreateAvailabilityRequest\x1a\x1a.yplanning.v1.Availability\x12U\n" +
+	"\x12DeleteAvailability\x12'.yplanning.v1.DeleteAvailabilityRequest\x1a\x16.google.protobuf.Empty2_\n" +
+	"\x0fFreeBusyService\x12L\n" +
+	"\vGetFreeBusy\x12\x1d.yplanning.v1.FreeBusyRequest\x1a\x1e.yplanning.v1.FreeBusyResponseB\x16Z\x14yplanning/pkg/rpc/pbb\x06proto3"
+
+var (
+	file_yplanning_proto_rawDescOnce sync.Once
+	file_yplanning_proto_rawDescData []byte
+)
+
+func file_yplanning_proto_rawDescGZIP() []byte {
+	file_yplanning_proto_rawDescOnce.Do(func() {
+		file_yplanning_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_yplanning_proto_rawDesc), len(file_yplanning_proto_rawDesc)))
+	})
+	return file_yplanning_proto_rawDescData
+}
+
+var file_yplanning_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_yplanning_proto_goTypes = []any{
+	(*User)(nil),                       // 0: yplanning.v1.User
+	(*Group)(nil),                      // 1: yplanning.v1.Group
+	(*Member)(nil),                     // 2: yplanning.v1.Member
+	(*Date)(nil),                       // 3: yplanning.v1.Date
+	(*Availability)(nil),               // 4: yplanning.v1.Availability
+	(*Period)(nil),                     // 5: yplanning.v1.Period
+	(*GetUserRequest)(nil),             // 6: yplanning.v1.GetUserRequest
+	(*GetGroupRequest)(nil),            // 7: yplanning.v1.GetGroupRequest
+	(*ListGroupsResponse)(nil),         // 8: yplanning.v1.ListGroupsResponse
+	(*ListMembersRequest)(nil),         // 9: yplanning.v1.ListMembersRequest
+	(*ListMembersResponse)(nil),        // 10: yplanning.v1.ListMembersResponse
+	(*ListDatesRequest)(nil),           // 11: yplanning.v1.ListDatesRequest
+	(*ListDatesResponse)(nil),          // 12: yplanning.v1.ListDatesResponse
+	(*GetDateRequest)(nil),             // 13: yplanning.v1.GetDateRequest
+	(*CreateDateRequest)(nil),          // 14: yplanning.v1.CreateDateRequest
+	(*UpdateDateRequest)(nil),          // 15: yplanning.v1.UpdateDateRequest
+	(*DeleteDateRequest)(nil),          // 16: yplanning.v1.DeleteDateRequest
+	(*ListAvailabilitiesRequest)(nil),  // 17: yplanning.v1.ListAvailabilitiesRequest
+	(*ListAvailabilitiesResponse)(nil), // 18: yplanning.v1.ListAvailabilitiesResponse
+	(*CreateAvailabilityRequest)(nil),  // 19: yplanning.v1.CreateAvailabilityRequest
+	(*DeleteAvailabilityRequest)(nil),  // 20: yplanning.v1.DeleteAvailabilityRequest
+	(*FreeBusyRequest)(nil),            // 21: yplanning.v1.FreeBusyRequest
+	(*UserBusy)(nil),                   // 22: yplanning.v1.UserBusy
+	(*FreeBusyResponse)(nil),           // 23: yplanning.v1.FreeBusyResponse
+	(*timestamppb.Timestamp)(nil),      // 24: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 25: google.protobuf.Empty
+}
+var file_yplanning_proto_depIdxs = []int32{
+	24, // 0: yplanning.v1.Date.begin:type_name -> google.protobuf.Timestamp
+	24, // 1: yplanning.v1.Date.end:type_name -> google.protobuf.Timestamp
+	24, // 2: yplanning.v1.Availability.begin:type_name -> google.protobuf.Timestamp
+	24, // 3: yplanning.v1.Availability.end:type_name -> google.protobuf.Timestamp
+	24, // 4: yplanning.v1.Period.begin:type_name -> google.protobuf.Timestamp
+	24, // 5: yplanning.v1.Period.end:type_name -> google.protobuf.Timestamp
+	1,  // 6: yplanning.v1.ListGroupsResponse.groups:type_name -> yplanning.v1.Group
+	2,  // 7: yplanning.v1.ListMembersResponse.members:type_name -> yplanning.v1.Member
+	24, // 8: yplanning.v1.ListDatesRequest.from:type_name -> google.protobuf.Timestamp
+	24, // 9: yplanning.v1.ListDatesRequest.to:type_name -> google.protobuf.Timestamp
+	3,  // 10: yplanning.v1.ListDatesResponse.dates:type_name -> yplanning.v1.Date
+	24, // 11: yplanning.v1.CreateDateRequest.begin:type_name -> google.protobuf.Timestamp
+	24, // 12: yplanning.v1.CreateDateRequest.end:type_name -> google.protobuf.Timestamp
+	24, // 13: yplanning.v1.UpdateDateRequest.begin:type_name -> google.protobuf.Timestamp
+	24, // 14: yplanning.v1.UpdateDateRequest.end:type_name -> google.protobuf.Timestamp
+	24, // 15: yplanning.v1.ListAvailabilitiesRequest.from:type_name -> google.protobuf.Timestamp
+	24, // 16: yplanning.v1.ListAvailabilitiesRequest.to:type_name -> google.protobuf.Timestamp
+	4,  // 17: yplanning.v1.ListAvailabilitiesResponse.availabilities:type_name -> yplanning.v1.Availability
+	24, // 18: yplanning.v1.CreateAvailabilityRequest.begin:type_name -> google.protobuf.Timestamp
+	24, // 19: yplanning.v1.CreateAvailabilityRequest.end:type_name -> google.protobuf.Timestamp
+	24, // 20: yplanning.v1.FreeBusyRequest.from:type_name -> google.protobuf.Timestamp
+	24, // 21: yplanning.v1.FreeBusyRequest.to:type_name -> google.protobuf.Timestamp
+	5,  // 22: yplanning.v1.UserBusy.busy:type_name -> yplanning.v1.Period
+	22, // 23: yplanning.v1.FreeBusyResponse.users:type_name -> yplanning.v1.UserBusy
+	5,  // 24: yplanning.v1.FreeBusyResponse.free:type_name -> yplanning.v1.Period
+	25, // 25: yplanning.v1.UserService.GetMe:input_type -> google.protobuf.Empty
+	6,  // 26: yplanning.v1.UserService.GetUser:input_type -> yplanning.v1.GetUserRequest
+	25, // 27: yplanning.v1.GroupService.ListGroups:input_type -> google.protobuf.Empty
+	7,  // 28: yplanning.v1.GroupService.GetGroup:input_type -> yplanning.v1.GetGroupRequest
+	9,  // 29: yplanning.v1.GroupService.ListMembers:input_type -> yplanning.v1.ListMembersRequest
+	11, // 30: yplanning.v1.DateService.ListDates:input_type -> yplanning.v1.ListDatesRequest
+	13, // 31: yplanning.v1.DateService.GetDate:input_type -> yplanning.v1.GetDateRequest
+	14, // 32: yplanning.v1.DateService.CreateDate:input_type -> yplanning.v1.CreateDateRequest
+	15, // 33: yplanning.v1.DateService.UpdateDate:input_type -> yplanning.v1.UpdateDateRequest
+	16, // 34: yplanning.v1.DateService.DeleteDate:input_type -> yplanning.v1.DeleteDateRequest
+	17, // 35: yplanning.v1.AvailabilityService.ListAvailabilities:input_type -> yplanning.v1.ListAvailabilitiesRequest
+	19, // 36: yplanning.v1.AvailabilityService.CreateAvailability:input_type -> yplanning.v1.CreateAvailabilityRequest
+	20, // 37: yplanning.v1.AvailabilityService.DeleteAvailability:input_type -> yplanning.v1.DeleteAvailabilityRequest
+	21, // 38: yplanning.v1.FreeBusyService.GetFreeBusy:input_type -> yplanning.v1.FreeBusyRequest
+	0,  // 39: yplanning.v1.UserService.GetMe:output_type -> yplanning.v1.User
+	0,  // 40: yplanning.v1.UserService.GetUser:output_type -> yplanning.v1.User
+	8,  // 41: yplanning.v1.GroupService.ListGroups:output_type -> yplanning.v1.ListGroupsResponse
+	1,  // 42: yplanning.v1.GroupService.GetGroup:output_type -> yplanning.v1.Group
+	10, // 43: yplanning.v1.GroupService.ListMembers:output_type -> yplanning.v1.ListMembersResponse
+	12, // 44: yplanning.v1.DateService.ListDates:output_type -> yplanning.v1.ListDatesResponse
+	3,  // 45: yplanning.v1.DateService.GetDate:output_type -> yplanning.v1.Date
+	3,  // 46: yplanning.v1.DateService.CreateDate:output_type -> yplanning.v1.Date
+	3,  // 47: yplanning.v1.DateService.UpdateDate:output_type -> yplanning.v1.Date
+	25, // 48: yplanning.v1.DateService.DeleteDate:output_type -> google.protobuf.Empty
+	18, // 49: yplanning.v1.AvailabilityService.ListAvailabilities:output_type -> yplanning.v1.ListAvailabilitiesResponse
+	4,  // 50: yplanning.v1.AvailabilityService.CreateAvailability:output_type -> yplanning.v1.Availability
+	25, // 51: yplanning.v1.AvailabilityService.DeleteAvailability:output_type -> google.protobuf.Empty
+	23, // 52: yplanning.v1.FreeBusyService.GetFreeBusy:output_type -> yplanning.v1.FreeBusyResponse
+	39, // [39:53] is the sub-list for method output_type
+	25, // [25:39] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_yplanning_proto_init() }
+func file_yplanning_proto_init() {
+	if File_yplanning_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_yplanning_proto_rawDesc), len(file_yplanning_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   5,
+		},
+		GoTypes:           file_yplanning_proto_goTypes,
+		DependencyIndexes: file_yplanning_proto_depIdxs,
+		MessageInfos:      file_yplanning_proto_msgTypes,
+	}.Build()
+	File_yplanning_proto = out.File
+	file_yplanning_proto_goTypes = nil
+	file_yplanning_proto_depIdxs = nil
+}
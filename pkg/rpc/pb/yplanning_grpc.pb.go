@@ -0,0 +1,886 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: yplanning.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserService_GetMe_FullMethodName   = "/yplanning.v1.UserService/GetMe"
+	UserService_GetUser_FullMethodName = "/yplanning.v1.UserService/GetUser"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserServiceClient interface {
+	// The authenticated user.
+	GetMe(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*User, error)
+	// A user the caller shares a group with, or the caller.
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) GetMe(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_GetMe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+type UserServiceServer interface {
+	// The authenticated user.
+	GetMe(context.Context, *emptypb.Empty) (*User, error)
+	// A user the caller shares a group with, or the caller.
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) GetMe(context.Context, *emptypb.Empty) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMe not implemented")
+}
+func (UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_GetMe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetMe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetMe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetMe(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "yplanning.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMe",
+			Handler:    _UserService_GetMe_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "yplanning.proto",
+}
+
+const (
+	GroupService_ListGroups_FullMethodName  = "/yplanning.v1.GroupService/ListGroups"
+	GroupService_GetGroup_FullMethodName    = "/yplanning.v1.GroupService/GetGroup"
+	GroupService_ListMembers_FullMethodName = "/yplanning.v1.GroupService/ListMembers"
+)
+
+// GroupServiceClient is the client API for GroupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GroupServiceClient interface {
+	// The groups the caller belongs to.
+	ListGroups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error)
+}
+
+type groupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGroupServiceClient(cc grpc.ClientConnInterface) GroupServiceClient {
+	return &groupServiceClient{cc}
+}
+
+func (c *groupServiceClient) ListGroups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, GroupService_ListGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, GroupService_GetGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMembersResponse)
+	err := c.cc.Invoke(ctx, GroupService_ListMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GroupServiceServer is the server API for GroupService service.
+// All implementations must embed UnimplementedGroupServiceServer
+// for forward compatibility.
+type GroupServiceServer interface {
+	// The groups the caller belongs to.
+	ListGroups(context.Context, *emptypb.Empty) (*ListGroupsResponse, error)
+	GetGroup(context.Context, *GetGroupRequest) (*Group, error)
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error)
+	mustEmbedUnimplementedGroupServiceServer()
+}
+
+// UnimplementedGroupServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGroupServiceServer struct{}
+
+func (UnimplementedGroupServiceServer) ListGroups(context.Context, *emptypb.Empty) (*ListGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedGroupServiceServer) GetGroup(context.Context, *GetGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedGroupServiceServer) ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedGroupServiceServer) mustEmbedUnimplementedGroupServiceServer() {}
+func (UnimplementedGroupServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeGroupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GroupServiceServer will
+// result in compilation errors.
+type UnsafeGroupServiceServer interface {
+	mustEmbedUnimplementedGroupServiceServer()
+}
+
+func RegisterGroupServiceServer(s grpc.ServiceRegistrar, srv GroupServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGroupServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GroupService_ServiceDesc, srv)
+}
+
+func _GroupService_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_ListGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListGroups(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_ListMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListMembers(ctx, req.(*ListMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GroupService_ServiceDesc is the grpc.ServiceDesc for GroupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GroupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "yplanning.v1.GroupService",
+	HandlerType: (*GroupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListGroups",
+			Handler:    _GroupService_ListGroups_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _GroupService_GetGroup_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _GroupService_ListMembers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "yplanning.proto",
+}
+
+const (
+	DateService_ListDates_FullMethodName  = "/yplanning.v1.DateService/ListDates"
+	DateService_GetDate_FullMethodName    = "/yplanning.v1.DateService/GetDate"
+	DateService_CreateDate_FullMethodName = "/yplanning.v1.DateService/CreateDate"
+	DateService_UpdateDate_FullMethodName = "/yplanning.v1.DateService/UpdateDate"
+	DateService_DeleteDate_FullMethodName = "/yplanning.v1.DateService/DeleteDate"
+)
+
+// DateServiceClient is the client API for DateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DateServiceClient interface {
+	ListDates(ctx context.Context, in *ListDatesRequest, opts ...grpc.CallOption) (*ListDatesResponse, error)
+	GetDate(ctx context.Context, in *GetDateRequest, opts ...grpc.CallOption) (*Date, error)
+	// Dates are always created for the caller.
+	CreateDate(ctx context.Context, in *CreateDateRequest, opts ...grpc.CallOption) (*Date, error)
+	UpdateDate(ctx context.Context, in *UpdateDateRequest, opts ...grpc.CallOption) (*Date, error)
+	DeleteDate(ctx context.Context, in *DeleteDateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type dateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDateServiceClient(cc grpc.ClientConnInterface) DateServiceClient {
+	return &dateServiceClient{cc}
+}
+
+func (c *dateServiceClient) ListDates(ctx context.Context, in *ListDatesRequest, opts ...grpc.CallOption) (*ListDatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDatesResponse)
+	err := c.cc.Invoke(ctx, DateService_ListDates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dateServiceClient) GetDate(ctx context.Context, in *GetDateRequest, opts ...grpc.CallOption) (*Date, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Date)
+	err := c.cc.Invoke(ctx, DateService_GetDate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dateServiceClient) CreateDate(ctx context.Context, in *CreateDateRequest, opts ...grpc.CallOption) (*Date, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Date)
+	err := c.cc.Invoke(ctx, DateService_CreateDate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dateServiceClient) UpdateDate(ctx context.Context, in *UpdateDateRequest, opts ...grpc.CallOption) (*Date, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Date)
+	err := c.cc.Invoke(ctx, DateService_UpdateDate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dateServiceClient) DeleteDate(ctx context.Context, in *DeleteDateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, DateService_DeleteDate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DateServiceServer is the server API for DateService service.
+// All implementations must embed UnimplementedDateServiceServer
+// for forward compatibility.
+type DateServiceServer interface {
+	ListDates(context.Context, *ListDatesRequest) (*ListDatesResponse, error)
+	GetDate(context.Context, *GetDateRequest) (*Date, error)
+	// Dates are always created for the caller.
+	CreateDate(context.Context, *CreateDateRequest) (*Date, error)
+	UpdateDate(context.Context, *UpdateDateRequest) (*Date, error)
+	DeleteDate(context.Context, *DeleteDateRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedDateServiceServer()
+}
+
+// UnimplementedDateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDateServiceServer struct{}
+
+func (UnimplementedDateServiceServer) ListDates(context.Context, *ListDatesRequest) (*ListDatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDates not implemented")
+}
+func (UnimplementedDateServiceServer) GetDate(context.Context, *GetDateRequest) (*Date, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDate not implemented")
+}
+func (UnimplementedDateServiceServer) CreateDate(context.Context, *CreateDateRequest) (*Date, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDate not implemented")
+}
+func (UnimplementedDateServiceServer) UpdateDate(context.Context, *UpdateDateRequest) (*Date, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDate not implemented")
+}
+func (UnimplementedDateServiceServer) DeleteDate(context.Context, *DeleteDateRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDate not implemented")
+}
+func (UnimplementedDateServiceServer) mustEmbedUnimplementedDateServiceServer() {}
+func (UnimplementedDateServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeDateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DateServiceServer will
+// result in compilation errors.
+type UnsafeDateServiceServer interface {
+	mustEmbedUnimplementedDateServiceServer()
+}
+
+func RegisterDateServiceServer(s grpc.ServiceRegistrar, srv DateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DateService_ServiceDesc, srv)
+}
+
+func _DateService_ListDates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DateServiceServer).ListDates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DateService_ListDates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DateServiceServer).ListDates(ctx, req.(*ListDatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DateService_GetDate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DateServiceServer).GetDate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DateService_GetDate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DateServiceServer).GetDate(ctx, req.(*GetDateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DateService_CreateDate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DateServiceServer).CreateDate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DateService_CreateDate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DateServiceServer).CreateDate(ctx, req.(*CreateDateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DateService_UpdateDate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DateServiceServer).UpdateDate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DateService_UpdateDate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DateServiceServer).UpdateDate(ctx, req.(*UpdateDateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DateService_DeleteDate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DateServiceServer).DeleteDate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DateService_DeleteDate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DateServiceServer).DeleteDate(ctx, req.(*DeleteDateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DateService_ServiceDesc is the grpc.ServiceDesc for DateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "yplanning.v1.DateService",
+	HandlerType: (*DateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDates",
+			Handler:    _DateService_ListDates_Handler,
+		},
+		{
+			MethodName: "GetDate",
+			Handler:    _DateService_GetDate_Handler,
+		},
+		{
+			MethodName: "CreateDate",
+			Handler:    _DateService_CreateDate_Handler,
+		},
+		{
+			MethodName: "UpdateDate",
+			Handler:    _DateService_UpdateDate_Handler,
+		},
+		{
+			MethodName: "DeleteDate",
+			Handler:    _DateService_DeleteDate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "yplanning.proto",
+}
+
+const (
+	AvailabilityService_ListAvailabilities_FullMethodName = "/yplanning.v1.AvailabilityService/ListAvailabilities"
+	AvailabilityService_CreateAvailability_FullMethodName = "/yplanning.v1.AvailabilityService/CreateAvailability"
+	AvailabilityService_DeleteAvailability_FullMethodName = "/yplanning.v1.AvailabilityService/DeleteAvailability"
+)
+
+// AvailabilityServiceClient is the client API for AvailabilityService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AvailabilityServiceClient interface {
+	ListAvailabilities(ctx context.Context, in *ListAvailabilitiesRequest, opts ...grpc.CallOption) (*ListAvailabilitiesResponse, error)
+	// Availabilities are always created for the caller.
+	CreateAvailability(ctx context.Context, in *CreateAvailabilityRequest, opts ...grpc.CallOption) (*Availability, error)
+	DeleteAvailability(ctx context.Context, in *DeleteAvailabilityRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type availabilityServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAvailabilityServiceClient(cc grpc.ClientConnInterface) AvailabilityServiceClient {
+	return &availabilityServiceClient{cc}
+}
+
+func (c *availabilityServiceClient) ListAvailabilities(ctx context.Context, in *ListAvailabilitiesRequest, opts ...grpc.CallOption) (*ListAvailabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAvailabilitiesResponse)
+	err := c.cc.Invoke(ctx, AvailabilityService_ListAvailabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *availabilityServiceClient) CreateAvailability(ctx context.Context, in *CreateAvailabilityRequest, opts ...grpc.CallOption) (*Availability, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Availability)
+	err := c.cc.Invoke(ctx, AvailabilityService_CreateAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *availabilityServiceClient) DeleteAvailability(ctx context.Context, in *DeleteAvailabilityRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AvailabilityService_DeleteAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AvailabilityServiceServer is the server API for AvailabilityService service.
+// All implementations must embed UnimplementedAvailabilityServiceServer
+// for forward compatibility.
+type AvailabilityServiceServer interface {
+	ListAvailabilities(context.Context, *ListAvailabilitiesRequest) (*ListAvailabilitiesResponse, error)
+	// Availabilities are always created for the caller.
+	CreateAvailability(context.Context, *CreateAvailabilityRequest) (*Availability, error)
+	DeleteAvailability(context.Context, *DeleteAvailabilityRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedAvailabilityServiceServer()
+}
+
+// UnimplementedAvailabilityServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAvailabilityServiceServer struct{}
+
+func (UnimplementedAvailabilityServiceServer) ListAvailabilities(context.Context, *ListAvailabilitiesRequest) (*ListAvailabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAvailabilities not implemented")
+}
+func (UnimplementedAvailabilityServiceServer) CreateAvailability(context.Context, *CreateAvailabilityRequest) (*Availability, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAvailability not implemented")
+}
+func (UnimplementedAvailabilityServiceServer) DeleteAvailability(context.Context, *DeleteAvailabilityRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAvailability not implemented")
+}
+func (UnimplementedAvailabilityServiceServer) mustEmbedUnimplementedAvailabilityServiceServer() {}
+func (UnimplementedAvailabilityServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeAvailabilityServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AvailabilityServiceServer will
+// result in compilation errors.
+type UnsafeAvailabilityServiceServer interface {
+	mustEmbedUnimplementedAvailabilityServiceServer()
+}
+
+func RegisterAvailabilityServiceServer(s grpc.ServiceRegistrar, srv AvailabilityServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAvailabilityServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AvailabilityService_ServiceDesc, srv)
+}
+
+func _AvailabilityService_ListAvailabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAvailabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvailabilityServiceServer).ListAvailabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AvailabilityService_ListAvailabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvailabilityServiceServer).ListAvailabilities(ctx, req.(*ListAvailabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AvailabilityService_CreateAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvailabilityServiceServer).CreateAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AvailabilityService_CreateAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvailabilityServiceServer).CreateAvailability(ctx, req.(*CreateAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AvailabilityService_DeleteAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvailabilityServiceServer).DeleteAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AvailabilityService_DeleteAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvailabilityServiceServer).DeleteAvailability(ctx, req.(*DeleteAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AvailabilityService_ServiceDesc is the grpc.ServiceDesc for AvailabilityService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AvailabilityService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "yplanning.v1.AvailabilityService",
+	HandlerType: (*AvailabilityServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAvailabilities",
+			Handler:    _AvailabilityService_ListAvailabilities_Handler,
+		},
+		{
+			MethodName: "CreateAvailability",
+			Handler:    _AvailabilityService_CreateAvailability_Handler,
+		},
+		{
+			MethodName: "DeleteAvailability",
+			Handler:    _AvailabilityService_DeleteAvailability_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "yplanning.proto",
+}
+
+const (
+	FreeBusyService_GetFreeBusy_FullMethodName = "/yplanning.v1.FreeBusyService/GetFreeBusy"
+)
+
+// FreeBusyServiceClient is the client API for FreeBusyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FreeBusyServiceClient interface {
+	// The busy periods of each user over a range, and the periods in which
+	// all of them are free.
+	GetFreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error)
+}
+
+type freeBusyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFreeBusyServiceClient(cc grpc.ClientConnInterface) FreeBusyServiceClient {
+	return &freeBusyServiceClient{cc}
+}
+
+func (c *freeBusyServiceClient) GetFreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreeBusyResponse)
+	err := c.cc.Invoke(ctx, FreeBusyService_GetFreeBusy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FreeBusyServiceServer is the server API for FreeBusyService service.
+// All implementations must embed UnimplementedFreeBusyServiceServer
+// for forward compatibility.
+type FreeBusyServiceServer interface {
+	// The busy periods of each user over a range, and the periods in which
+	// all of them are free.
+	GetFreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error)
+	mustEmbedUnimplementedFreeBusyServiceServer()
+}
+
+// UnimplementedFreeBusyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFreeBusyServiceServer struct{}
+
+func (UnimplementedFreeBusyServiceServer) GetFreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreeBusy not implemented")
+}
+func (UnimplementedFreeBusyServiceServer) mustEmbedUnimplementedFreeBusyServiceServer() {}
+func (UnimplementedFreeBusyServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeFreeBusyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FreeBusyServiceServer will
+// result in compilation errors.
+type UnsafeFreeBusyServiceServer interface {
+	mustEmbedUnimplementedFreeBusyServiceServer()
+}
+
+func RegisterFreeBusyServiceServer(s grpc.ServiceRegistrar, srv FreeBusyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFreeBusyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FreeBusyService_ServiceDesc, srv)
+}
+
+func _FreeBusyService_GetFreeBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FreeBusyServiceServer).GetFreeBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FreeBusyService_GetFreeBusy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FreeBusyServiceServer).GetFreeBusy(ctx, req.(*FreeBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FreeBusyService_ServiceDesc is the grpc.ServiceDesc for FreeBusyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FreeBusyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "yplanning.v1.FreeBusyService",
+	HandlerType: (*FreeBusyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFreeBusy",
+			Handler:    _FreeBusyService_GetFreeBusy_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "yplanning.proto",
+}
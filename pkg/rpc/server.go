@@ -0,0 +1,40 @@
+package rpc
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=yplanning --go-grpc_out=../.. --go-grpc_opt=module=yplanning yplanning.proto
+
+import (
+	"net"
+
+	"yplanning/config"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultPort is used when GRPC_PORT is not set.
+const DefaultPort = "50051"
+
+// NewServer returns a gRPC server exposing the user, group, date,
+// availability and free/busy services over the same repositories as the
+// REST API. Calls are authenticated with the JWT access tokens checked by
+// keys.
+func NewServer(cfg *config.Config, keys *authentication.Keys) *grpc.Server {
+	auth := &authenticator{Config: cfg, keys: keys}
+	server := grpc.NewServer(grpc.UnaryInterceptor(auth.intercept))
+	pb.RegisterUserServiceServer(server, &userServer{Config: cfg})
+	pb.RegisterGroupServiceServer(server, &groupServer{Config: cfg})
+	pb.RegisterDateServiceServer(server, &dateServer{Config: cfg})
+	pb.RegisterAvailabilityServiceServer(server, &availabilityServer{Config: cfg})
+	pb.RegisterFreeBusyServiceServer(server, &freeBusyServer{Config: cfg})
+	return server
+}
+
+// ListenAndServe serves the gRPC API on addr until it fails.
+func ListenAndServe(cfg *config.Config, keys *authentication.Keys, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return NewServer(cfg, keys).Serve(listener)
+}
@@ -0,0 +1,32 @@
+package rpc
+
+import (
+	"context"
+
+	"yplanning/config"
+	"yplanning/pkg/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	*config.Config
+}
+
+func (config *userServer) GetMe(ctx context.Context, _ *emptypb.Empty) (*pb.User, error) {
+	return toUser(callerFrom(ctx)), nil
+}
+
+func (config *userServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	if err := checkVisible(config.Config, callerFrom(ctx), uint(req.Id)); err != nil {
+		return nil, err
+	}
+	user, err := config.UserRepository.FindByID(uint(req.Id))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return toUser(user), nil
+}
@@ -0,0 +1,234 @@
+package subscription
+
+import (
+	"net/http"
+	"strconv"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type SubscriptionConfig struct {
+	*config.Config
+	syncer *Syncer
+}
+
+func NewSubscriptionConfig(cfg *config.Config) *SubscriptionConfig {
+	return &SubscriptionConfig{Config: cfg, syncer: NewSyncer(cfg)}
+}
+
+// @Summary		List calendar subscriptions
+// @Description	List the external calendars the current user is subscribed to
+// @Tags		subscriptions
+// @Produce		json
+// @Success		200	{array}		models.SubscriptionResponse
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/subscription/ [get]
+func (config *SubscriptionConfig) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	subscriptions, err := config.SubscriptionRepository.FindByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve subscriptions", http.StatusInternalServerError)
+		return
+	}
+	subscriptionResponse := make([]models.SubscriptionResponse, 0)
+	for _, subscription := range subscriptions {
+		subscriptionResponse = append(subscriptionResponse, *config.toSubscriptionResponse(&subscription))
+	}
+	render.JSON(w, r, subscriptionResponse)
+}
+
+// @Summary		Subscribe to an external calendar
+// @Description	Register an external iCalendar URL (http, https or webcal). It is fetched in the background right away, then every refresh_minutes, and its events are mirrored as read-only dates.
+// @Tags		subscriptions
+// @Accept		json
+// @Produce		json
+// @Param		request	body	models.SubscriptionRequest	true	"Subscription data"
+// @Success		200	{object}	models.SubscriptionResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/subscription/ [post]
+func (config *SubscriptionConfig) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	req := &models.SubscriptionRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := ValidateURL(r.Context(), req.URL); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	subscription := &dbmodel.CalendarSubscription{
+		UserID:         user.ID,
+		Name:           req.Name,
+		URL:            req.URL,
+		RefreshMinutes: refreshMinutes(req.RefreshMinutes),
+	}
+	created, err := config.SubscriptionRepository.Create(subscription)
+	if err != nil {
+		http.Error(w, "Failed to create subscription", http.StatusInternalServerError)
+		return
+	}
+	// The worker fetches it in the background; a failing first sync is
+	// reported through last_error, since the source may only be down briefly.
+	Schedule()
+	render.JSON(w, r, config.toSubscriptionResponse(created))
+}
+
+// @Summary		Update a calendar subscription
+// @Description	Change the name, URL or refresh interval of a subscription
+// @Tags		subscriptions
+// @Accept		json
+// @Produce		json
+// @Param		id	path	int	true	"Subscription ID"
+// @Param		request	body	models.SubscriptionRequest	true	"Subscription data"
+// @Success		200	{object}	models.SubscriptionResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/subscription/{id} [put]
+func (config *SubscriptionConfig) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := config.ownedSubscription(w, r)
+	if !ok {
+		return
+	}
+	req := &models.SubscriptionRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := ValidateURL(r.Context(), req.URL); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	urlChanged := req.URL != subscription.URL
+	subscription.Name = req.Name
+	subscription.URL = req.URL
+	subscription.RefreshMinutes = refreshMinutes(req.RefreshMinutes)
+	if err := config.SubscriptionRepository.UpdateByID(subscription.ID, subscription); err != nil {
+		http.Error(w, "Failed to update subscription", http.StatusInternalServerError)
+		return
+	}
+	if urlChanged {
+		if err := config.SubscriptionRepository.ResetSyncStatusByID(subscription.ID); err != nil {
+			http.Error(w, "Failed to update subscription", http.StatusInternalServerError)
+			return
+		}
+		subscription.ETag, subscription.LastModified, subscription.LastSyncedAt = "", "", nil
+		Schedule()
+	}
+	render.JSON(w, r, config.toSubscriptionResponse(subscription))
+}
+
+// @Summary		Sync a calendar subscription now
+// @Description	Fetch the external calendar immediately instead of waiting for the next refresh
+// @Tags		subscriptions
+// @Produce		json
+// @Param		id	path	int	true	"Subscription ID"
+// @Success		200	{object}	models.SubscriptionResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Security 	BearerAuth
+// @Router		/subscription/{id}/sync [post]
+func (config *SubscriptionConfig) SyncSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := config.ownedSubscription(w, r)
+	if !ok {
+		return
+	}
+	_ = config.syncer.Sync(subscription)
+	render.JSON(w, r, config.toSubscriptionResponse(subscription))
+}
+
+// @Summary		Delete a calendar subscription
+// @Description	Unsubscribe from an external calendar and delete the dates mirrored from it
+// @Tags		subscriptions
+// @Produce		json
+// @Param		id	path	int	true	"Subscription ID"
+// @Success		200	{object}	map[string]string
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/subscription/{id} [delete]
+func (config *SubscriptionConfig) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := config.ownedSubscription(w, r)
+	if !ok {
+		return
+	}
+	dates, err := config.DateRepository.FindBySubscriptionID(subscription.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve mirrored dates", http.StatusInternalServerError)
+		return
+	}
+	if err := config.DateRepository.DeleteBySubscriptionID(subscription.ID); err != nil {
+		http.Error(w, "Failed to delete mirrored dates", http.StatusInternalServerError)
+		return
+	}
+	for i := range dates {
+		if dates[i].RecurrenceID == 0 {
+			config.Events.Publish(events.DateEvent(events.ActionDeleted, &dates[i]))
+		}
+	}
+	if err := config.SubscriptionRepository.DeleteByID(subscription.ID); err != nil {
+		http.Error(w, "Failed to delete subscription", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, map[string]string{"message": "Subscription deleted successfully"})
+}
+
+func (config *SubscriptionConfig) ownedSubscription(w http.ResponseWriter, r *http.Request) (*dbmodel.CalendarSubscription, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return nil, false
+	}
+	if id < 1 {
+		http.Error(w, "id must be >= 1", http.StatusBadRequest)
+		return nil, false
+	}
+	user := authentication.UserFromContext(r.Context())
+	subscription, err := config.SubscriptionRepository.FindByID(uint(id))
+	if err != nil || subscription.UserID != user.ID {
+		http.Error(w, "Subscription not found", http.StatusNotFound)
+		return nil, false
+	}
+	return subscription, true
+}
+
+func (config *SubscriptionConfig) toSubscriptionResponse(subscription *dbmodel.CalendarSubscription) *models.SubscriptionResponse {
+	dates, _ := config.DateRepository.FindBySubscriptionID(subscription.ID)
+	return &models.SubscriptionResponse{
+		ID:             subscription.ID,
+		Name:           subscription.Name,
+		URL:            subscription.URL,
+		RefreshMinutes: subscription.RefreshMinutes,
+		LastSyncedAt:   subscription.LastSyncedAt,
+		LastError:      subscription.LastError,
+		DateCount:      len(dates),
+	}
+}
+
+func refreshMinutes(requested int) int {
+	if requested == 0 {
+		return DefaultRefreshMinutes
+	}
+	if requested < MinRefreshMinutes {
+		return MinRefreshMinutes
+	}
+	return requested
+}
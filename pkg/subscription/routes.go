@@ -0,0 +1,27 @@
+package subscription
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+subscription routes:
+GET /subscription/ - List the current user's calendar subscriptions
+POST /subscription/ - Subscribe to an external calendar
+PUT /subscription/{id} - Update a subscription
+POST /subscription/{id}/sync - Sync a subscription now
+DELETE /subscription/{id} - Delete a subscription and its mirrored dates
+*/
+
+func Routes(config *config.Config) chi.Router {
+	SubscriptionConfig := NewSubscriptionConfig(config)
+	router := chi.NewRouter()
+	router.Get("/", SubscriptionConfig.GetSubscriptions)
+	router.Post("/", SubscriptionConfig.CreateSubscription)
+	router.Put("/{id}", SubscriptionConfig.UpdateSubscription)
+	router.Post("/{id}/sync", SubscriptionConfig.SyncSubscription)
+	router.Delete("/{id}", SubscriptionConfig.DeleteSubscription)
+	return router
+}
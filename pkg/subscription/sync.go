@@ -0,0 +1,212 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+	"yplanning/pkg/ical"
+	"yplanning/pkg/netguard"
+)
+
+const (
+	DefaultRefreshMinutes = 60
+	MinRefreshMinutes     = 15
+	maxCalendarSize       = 10 << 20
+	syncHorizonYears      = 1
+	cancelledEvent        = "CANCELLED"
+)
+
+var subscriptionLocks sync.Map
+
+// wake tells the worker that a subscription is due now.
+var wake = make(chan struct{}, 1)
+
+// Schedule has the worker sync due subscriptions without waiting for its
+// next tick.
+func Schedule() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+type Syncer struct {
+	*config.Config
+	Client *http.Client
+}
+
+func NewSyncer(cfg *config.Config) *Syncer {
+	return &Syncer{Config: cfg, Client: netguard.NewClient(30 * time.Second)}
+}
+
+// Sync fetches the subscribed calendar and mirrors it into read-only dates
+// owned by the subscriber. The outcome is recorded on the subscription.
+func (syncer *Syncer) Sync(subscription *dbmodel.CalendarSubscription) error {
+	lock, _ := subscriptionLocks.LoadOrStore(subscription.ID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	now := time.Now()
+	calendar, etag, lastModified, err := syncer.fetch(subscription)
+	if err == nil && calendar != nil {
+		err = syncer.mirror(subscription, calendar, now)
+	}
+	if err != nil {
+		etag, lastModified = subscription.ETag, subscription.LastModified
+		subscription.LastError = err.Error()
+	} else {
+		subscription.LastError = ""
+	}
+	subscription.ETag, subscription.LastModified, subscription.LastSyncedAt = etag, lastModified, &now
+	if updateErr := syncer.SubscriptionRepository.UpdateSyncStatus(subscription.ID, now, etag, lastModified, subscription.LastError); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// fetch returns a nil calendar when the server reports it unchanged.
+func (syncer *Syncer) fetch(subscription *dbmodel.CalendarSubscription) (*ical.Calendar, string, string, error) {
+	request, err := http.NewRequest(http.MethodGet, FetchURL(subscription.URL), nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	request.Header.Set("Accept", "text/calendar")
+	if subscription.ETag != "" {
+		request.Header.Set("If-None-Match", subscription.ETag)
+	}
+	if subscription.LastModified != "" {
+		request.Header.Set("If-Modified-Since", subscription.LastModified)
+	}
+
+	response, err := syncer.Client.Do(request)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotModified {
+		return nil, subscription.ETag, subscription.LastModified, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	calendar, err := ical.Parse(io.LimitReader(response.Body, maxCalendarSize))
+	if err != nil {
+		return nil, "", "", err
+	}
+	return calendar, response.Header.Get("ETag"), response.Header.Get("Last-Modified"), nil
+}
+
+func (syncer *Syncer) mirror(subscription *dbmodel.CalendarSubscription, calendar *ical.Calendar, now time.Time) error {
+	existing, err := syncer.DateRepository.FindBySubscriptionID(subscription.ID)
+	if err != nil {
+		return err
+	}
+	masters := make(map[string]*dbmodel.Date)
+	instances := make(map[uint][]time.Time)
+	for i := range existing {
+		if existing[i].RecurrenceID != 0 {
+			instances[existing[i].RecurrenceID] = append(instances[existing[i].RecurrenceID], existing[i].BeginTime)
+		} else if existing[i].UID != "" {
+			masters[existing[i].UID] = &existing[i]
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, event := range calendar.Events {
+		if event.UID == "" || seen[event.UID] || !event.RecurrenceID.IsZero() || event.Status == cancelledEvent {
+			continue
+		}
+		horizon := now
+		if event.Start.After(now) {
+			horizon = event.Start
+		}
+		occurrences, err := event.Occurrences(horizon.AddDate(syncHorizonYears, 0, 0))
+		if err != nil {
+			log.Printf("Subscription %d: skipping event %q: %v", subscription.ID, event.UID, err)
+			continue
+		}
+		seen[event.UID] = true
+
+		date := ical.ToDate(event, subscription.UserID)
+		date.SubscriptionID = subscription.ID
+		current, exists := masters[event.UID]
+		switch {
+		case !exists:
+			if _, err := syncer.DateRepository.CreateWithOccurrences(date, occurrences); err != nil {
+				return err
+			}
+			syncer.Events.Publish(events.DateEvent(events.ActionCreated, date))
+		case !current.HasSameContent(date):
+			date.ID = current.ID
+			date.CreatedAt = current.CreatedAt
+			date.UpdatedAt = current.UpdatedAt
+			if err := syncer.DateRepository.SaveWithOccurrences(date, occurrences); err != nil {
+				return err
+			}
+			syncer.Events.Publish(events.DateEvent(events.ActionUpdated, date))
+		case !sameInstances(current, instances[current.ID], occurrences):
+			if err := syncer.DateRepository.ReplaceOccurrences(current, occurrences); err != nil {
+				return err
+			}
+		}
+	}
+
+	for uid, master := range masters {
+		if seen[uid] {
+			continue
+		}
+		if err := syncer.DateRepository.DeleteWithOccurrences(master); err != nil {
+			return err
+		}
+		syncer.Events.Publish(events.DateEvent(events.ActionDeleted, master))
+	}
+	return nil
+}
+
+func sameInstances(master *dbmodel.Date, instances []time.Time, occurrences []time.Time) bool {
+	expected := make(map[int64]bool)
+	for _, occurrence := range occurrences {
+		if !occurrence.Equal(master.BeginTime) {
+			expected[occurrence.Unix()] = true
+		}
+	}
+	if len(expected) != len(instances) {
+		return false
+	}
+	for _, instance := range instances {
+		if !expected[instance.Unix()] {
+			return false
+		}
+	}
+	return true
+}
+
+// FetchURL maps webcal:// links, as published by most calendar apps, to
+// the https URL they stand for.
+func FetchURL(rawURL string) string {
+	if strings.HasPrefix(strings.ToLower(rawURL), "webcal://") {
+		return "https://" + rawURL[len("webcal://"):]
+	}
+	return rawURL
+}
+
+// ValidateURL checks that a subscription URL uses http, https or webcal and
+// points at a public address.
+func ValidateURL(ctx context.Context, rawURL string) error {
+	lower := strings.ToLower(rawURL)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "webcal://") {
+		return errors.New("url must use http, https or webcal")
+	}
+	return netguard.CheckURL(ctx, FetchURL(rawURL))
+}
@@ -0,0 +1,218 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/netguard"
+)
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantErr   bool
+		forbidden bool
+	}{
+		{name: "https", url: "https://93.184.216.34/calendar.ics"},
+		{name: "http", url: "http://93.184.216.34/calendar.ics"},
+		{name: "webcal", url: "webcal://93.184.216.34/calendar.ics"},
+		{name: "upper case scheme", url: "HTTPS://93.184.216.34/calendar.ics"},
+		{name: "ftp", url: "ftp://93.184.216.34/calendar.ics", wantErr: true},
+		{name: "no scheme", url: "93.184.216.34/calendar.ics", wantErr: true},
+		{name: "no host", url: "https:///calendar.ics", wantErr: true},
+		{name: "loopback", url: "http://127.0.0.1/calendar.ics", wantErr: true, forbidden: true},
+		{name: "loopback v6", url: "http://[::1]/calendar.ics", wantErr: true, forbidden: true},
+		{name: "webcal loopback", url: "webcal://127.0.0.1:8080/calendar.ics", wantErr: true, forbidden: true},
+		{name: "private", url: "https://10.0.0.1/calendar.ics", wantErr: true, forbidden: true},
+		{name: "link-local metadata", url: "http://169.254.169.254/latest", wantErr: true, forbidden: true},
+		{name: "unspecified", url: "http://0.0.0.0/calendar.ics", wantErr: true, forbidden: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateURL(context.Background(), test.url)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ValidateURL(%q) = %v, want error %v", test.url, err, test.wantErr)
+			}
+			if errors.Is(err, netguard.ErrForbiddenAddress) != test.forbidden {
+				t.Fatalf("ValidateURL(%q) = %v, want forbidden %v", test.url, err, test.forbidden)
+			}
+		})
+	}
+}
+
+func TestValidateURLAllowPrivate(t *testing.T) {
+	t.Setenv("ALLOW_PRIVATE_NETWORKS", "true")
+	if err := ValidateURL(context.Background(), "http://127.0.0.1/calendar.ics"); err != nil {
+		t.Fatalf("ValidateURL with ALLOW_PRIVATE_NETWORKS = %v, want nil", err)
+	}
+}
+
+const fixtureCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//fixture//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@fixture\r\n" +
+	"DTSTART:20990105T090000Z\r\n" +
+	"DTEND:20990105T093000Z\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:review@fixture\r\n" +
+	"DTSTART:20990106T140000Z\r\n" +
+	"DTEND:20990106T150000Z\r\n" +
+	"SUMMARY:Review\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// fixture serves a calendar with an ETag and records the requests it got.
+type fixture struct {
+	mu       sync.Mutex
+	body     string
+	etag     string
+	status   int
+	requests []*http.Request
+}
+
+func (fixture *fixture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fixture.mu.Lock()
+	defer fixture.mu.Unlock()
+	fixture.requests = append(fixture.requests, r)
+	if fixture.status != 0 {
+		w.WriteHeader(fixture.status)
+		return
+	}
+	if r.Header.Get("If-None-Match") == fixture.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", fixture.etag)
+	w.Header().Set("Content-Type", "text/calendar")
+	_, _ = w.Write([]byte(fixture.body))
+}
+
+func newTestSubscription(t *testing.T, url string) (*config.Config, *dbmodel.CalendarSubscription) {
+	t.Helper()
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := cfg.UserRepository.Create(&dbmodel.User{Username: "subscriber", Email: "subscriber@example.com", Password: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscription, err := cfg.SubscriptionRepository.Create(&dbmodel.CalendarSubscription{
+		UserID:         user.ID,
+		Name:           "Fixture",
+		URL:            url,
+		RefreshMinutes: DefaultRefreshMinutes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg, subscription
+}
+
+func mirroredUIDs(t *testing.T, cfg *config.Config, subscriptionID uint) []string {
+	t.Helper()
+	dates, err := cfg.DateRepository.FindBySubscriptionID(subscriptionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uids []string
+	for _, date := range dates {
+		if date.RecurrenceID == 0 {
+			uids = append(uids, date.UID)
+		}
+	}
+	return uids
+}
+
+func TestSyncMirrorsFixture(t *testing.T) {
+	source := &fixture{body: fixtureCalendar, etag: `"v1"`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	cfg, subscription := newTestSubscription(t, server.URL+"/calendar.ics")
+	syncer := NewSyncer(cfg)
+	// The fixture listens on loopback, which the guarded client refuses.
+	syncer.Client = server.Client()
+
+	if err := syncer.Sync(subscription); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if uids := mirroredUIDs(t, cfg, subscription.ID); len(uids) != 2 {
+		t.Fatalf("first sync mirrored %v, want 2 events", uids)
+	}
+	if subscription.ETag != `"v1"` || subscription.LastSyncedAt == nil {
+		t.Fatalf("sync status not recorded: etag %q, last synced %v", subscription.ETag, subscription.LastSyncedAt)
+	}
+
+	// Unchanged: the ETag is sent back and nothing is touched.
+	if err := syncer.Sync(subscription); err != nil {
+		t.Fatalf("unchanged sync: %v", err)
+	}
+	if got := source.requests[1].Header.Get("If-None-Match"); got != `"v1"` {
+		t.Fatalf("If-None-Match = %q, want %q", got, `"v1"`)
+	}
+	if uids := mirroredUIDs(t, cfg, subscription.ID); len(uids) != 2 {
+		t.Fatalf("unchanged sync left %v, want 2 events", uids)
+	}
+
+	// An event removed at the source is removed from the mirror.
+	source.mu.Lock()
+	source.body = strings.Replace(fixtureCalendar, "BEGIN:VEVENT\r\nUID:review@fixture", "BEGIN:VTODO\r\nUID:review@fixture", 1)
+	source.body = strings.Replace(source.body, "SUMMARY:Review\r\nEND:VEVENT", "SUMMARY:Review\r\nEND:VTODO", 1)
+	source.etag = `"v2"`
+	source.mu.Unlock()
+	if err := syncer.Sync(subscription); err != nil {
+		t.Fatalf("changed sync: %v", err)
+	}
+	if uids := mirroredUIDs(t, cfg, subscription.ID); len(uids) != 1 || uids[0] != "standup@fixture" {
+		t.Fatalf("changed sync left %v, want [standup@fixture]", uids)
+	}
+
+	// A failing source is reported and keeps the mirror as it was.
+	source.mu.Lock()
+	source.status = http.StatusInternalServerError
+	source.mu.Unlock()
+	if err := syncer.Sync(subscription); err == nil {
+		t.Fatal("sync of a failing source succeeded")
+	}
+	stored, err := cfg.SubscriptionRepository.FindByID(subscription.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stored.LastError, "500") || stored.ETag != `"v2"` {
+		t.Fatalf("stored last error %q, etag %q", stored.LastError, stored.ETag)
+	}
+	if uids := mirroredUIDs(t, cfg, subscription.ID); len(uids) != 1 {
+		t.Fatalf("failed sync left %v, want 1 event", uids)
+	}
+}
+
+func TestSyncRefusesPrivateAddressAtDialTime(t *testing.T) {
+	source := &fixture{body: fixtureCalendar, etag: `"v1"`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	cfg, subscription := newTestSubscription(t, server.URL+"/calendar.ics")
+	err := NewSyncer(cfg).Sync(subscription)
+	if !errors.Is(err, netguard.ErrForbiddenAddress) {
+		t.Fatalf("Sync = %v, want %v", err, netguard.ErrForbiddenAddress)
+	}
+	if len(source.requests) != 0 {
+		t.Fatalf("fixture got %d requests, want none", len(source.requests))
+	}
+	if uids := mirroredUIDs(t, cfg, subscription.ID); len(uids) != 0 {
+		t.Fatalf("mirrored %v from a refused source", uids)
+	}
+}
@@ -0,0 +1,52 @@
+package subscription
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"yplanning/config"
+)
+
+const pollInterval = time.Minute
+
+type Worker struct {
+	syncer   *Syncer
+	interval time.Duration
+}
+
+func NewWorker(cfg *config.Config) *Worker {
+	return &Worker{syncer: NewSyncer(cfg), interval: pollInterval}
+}
+
+// Run syncs every subscription whose refresh interval has elapsed, then
+// checks again on each tick until ctx is cancelled.
+func (worker *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+	for {
+		worker.syncDue(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+func (worker *Worker) syncDue(now time.Time) {
+	subscriptions, err := worker.syncer.SubscriptionRepository.FindAll()
+	if err != nil {
+		log.Println("Failed to retrieve calendar subscriptions:", err)
+		return
+	}
+	for i := range subscriptions {
+		if !subscriptions[i].IsDue(now) {
+			continue
+		}
+		if err := worker.syncer.Sync(&subscriptions[i]); err != nil {
+			log.Printf("Subscription %d: sync failed: %v", subscriptions[i].ID, err)
+		}
+	}
+}
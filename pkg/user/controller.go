@@ -1,16 +1,21 @@
 package user
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"yplanning/config"
 	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
 	"yplanning/pkg/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserConfig struct {
@@ -22,12 +27,13 @@ func NewUserConfig(cfg *config.Config) *UserConfig {
 }
 
 // @Summary		Get all users
-// @Description	Retrieve a list of all users
+// @Description	Retrieve a list of all users (administrators only)
 // @Tags		users
 // @Accept		json
 // @Produce		json
 // @Success		200	{array}		models.UserResponse
-// @Failure 	400 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
 // @Security 	BearerAuth
 // @Router		/user/users [get]
 func (config *UserConfig) GetAllUsers(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +52,7 @@ func (config *UserConfig) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 			Name:     user.Name,
 			Surname:  user.Surname,
 			ColorID:  user.ColorID,
+			Verified: user.Verified,
 		})
 	}
 	render.JSON(w, r, UserResponse)
@@ -58,7 +65,8 @@ func (config *UserConfig) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 // @Produce		json
 // @Param		id	path		int	true	"User ID"
 // @Success		200	{object}	models.UserResponse
-// @Failure 	400 {object}	http.Error
+// @Failure 	400 {string}	string
+// @Failure 	404 {string}	string
 // @Security 	BearerAuth
 // @Router		/user/{id} [get]
 func (config *UserConfig) GetUserByID(w http.ResponseWriter, r *http.Request) {
@@ -71,13 +79,17 @@ func (config *UserConfig) GetUserByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "User ID must be greater than 0", http.StatusBadRequest)
 		return
 	}
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, authorization.UserFromContext(r.Context()), uint(id)); err != nil || !visible {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 
 	user, err := config.UserRepository.FindByID(uint(id))
 	if err != nil {
 		http.Error(w, "Failed to retrieve user", http.StatusInternalServerError)
 		return
 	}
-	userResponse := &models.UserResponse{ID: user.ID, Email: user.Email, Username: user.Username}
+	userResponse := &models.UserResponse{ID: user.ID, Email: user.Email, Username: user.Username, Verified: user.Verified}
 	render.JSON(w, r, userResponse)
 }
 
@@ -87,7 +99,8 @@ func (config *UserConfig) GetUserByID(w http.ResponseWriter, r *http.Request) {
 // @Accept		json
 // @Produce		json
 // @Success		200	{object}	models.UserResponse
-// @Failure 	400 {object}	http.Error
+// @Failure 	400 {string}	string
+// @Failure 	404 {string}	string
 // @Security 	BearerAuth
 // @Router		/user/ [get]
 func (config *UserConfig) GetUser(w http.ResponseWriter, r *http.Request) {
@@ -110,10 +123,18 @@ func (config *UserConfig) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to retrieve user", http.StatusInternalServerError)
 		return
 	}
+	if visible, err := authorization.CanSeeUser(config.GroupRepository, authorization.UserFromContext(r.Context()), user.ID); err != nil || !visible {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 
 	userResponse := &models.UserResponse{
 		ID:       user.ID,
@@ -122,20 +143,23 @@ func (config *UserConfig) GetUser(w http.ResponseWriter, r *http.Request) {
 		Name:     user.Name,
 		Surname:  user.Surname,
 		ColorID:  user.ColorID,
+		Verified: user.Verified,
 	}
 
 	render.JSON(w, r, userResponse)
 }
 
 // @Summary		Update a user
-// @Description	Update a user by its ID
+// @Description	Update a user by its ID. current_password is the caller's password. Every session of the user is signed out. The email cannot be changed here: PUT /auth/email verifies the new address first.
 // @Tags		users
 // @Accept		json
 // @Produce		json
 // @Param		id		path	int					true	"User ID"
-// @Param		request	body	models.UserRequest	true	"Updated user data"
+// @Param		request	body	models.UpdateUserRequest	true	"Updated user data"
 // @Success		200	{object}	models.UserResponse
-// @Failure 	400 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	404 {string} 	string
 // @Security 	BearerAuth
 // @Router		/user/{id} [put]
 func (config *UserConfig) UpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -145,7 +169,7 @@ func (config *UserConfig) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req := &models.UserRequest{}
+	req := &models.UpdateUserRequest{}
 	if err := render.Bind(r, req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -155,26 +179,56 @@ func (config *UserConfig) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "User ID must be greater than 0", http.StatusBadRequest)
 		return
 	}
+	caller := authorization.UserFromContext(r.Context())
+	if !authorization.CanChangeUser(caller, uint(id)) {
+		http.Error(w, "You can only change your own account", http.StatusForbidden)
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(caller.Password), []byte(req.CurrentPassword)); err != nil {
+		http.Error(w, "Invalid current password", http.StatusForbidden)
+		return
+	}
+
+	current, err := config.UserRepository.FindByID(uint(id))
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if req.Email != current.Email {
+		http.Error(w, "Change your email with PUT /api/auth/email, which verifies it first", http.StatusBadRequest)
+		return
+	}
 
-	user := &dbmodel.User{Email: req.Email, Password: req.Password, Username: req.Username}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+		return
+	}
+	user := &dbmodel.User{Email: req.Email, Password: string(hashedPassword), Username: req.Username}
 	updated, err := config.UserRepository.UpdateByID(uint(id), user)
 	if err != nil {
 		http.Error(w, "Failed to update user", http.StatusInternalServerError)
 		return
 	}
+	if err := config.SessionRepository.RevokeByUserID(uint(id)); err != nil {
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
 
-	userResponse := &models.UserResponse{ID: uint(id), Email: updated.Email, Username: updated.Username}
+	userResponse := &models.UserResponse{ID: uint(id), Email: updated.Email, Username: updated.Username, Verified: current.Verified}
 	render.JSON(w, r, userResponse)
 }
 
 // @Summary		Delete a user
-// @Description	Delete a user by its ID
+// @Description	Delete a user by its ID. Users who own groups must transfer or delete them first.
 // @Tags		users
 // @Accept		json
 // @Produce		json
 // @Param		id	path		int		true	"User ID"
 // @Success		200	{string}	string	"Successfully deleted entry"
-// @Failure 	400 {object} 	http.Error
+// @Failure 	400 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	409 {string} 	string
 // @Security 	BearerAuth
 // @Router		/user/{id} [delete]
 func (config *UserConfig) DeleteUser(w http.ResponseWriter, r *http.Request) {
@@ -187,6 +241,25 @@ func (config *UserConfig) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "User ID must be greater than 0", http.StatusBadRequest)
 		return
 	}
+	if !authorization.CanChangeUser(authorization.UserFromContext(r.Context()), uint(id)) {
+		http.Error(w, "You can only delete your own account", http.StatusForbidden)
+		return
+	}
+	// Users are soft-deleted, so their groups would be left without an
+	// owner rather than deleted with them.
+	owned, err := config.GroupRepository.FindByCreatorIDs([]uint{uint(id)})
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	if len(owned) > 0 {
+		names := make([]string, 0, len(owned))
+		for _, group := range owned {
+			names = append(names, group.Name)
+		}
+		http.Error(w, "Transfer the ownership of these groups or delete them first: "+strings.Join(names, ", "), http.StatusConflict)
+		return
+	}
 	err = config.UserRepository.DeleteByID(uint(id))
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to delete user: %v", err), http.StatusInternalServerError)
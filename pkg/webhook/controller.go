@@ -0,0 +1,391 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authentication"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/models"
+	"yplanning/pkg/netguard"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	secretBytes     = 32
+	deliveriesLimit = 50
+)
+
+type WebhookConfig struct {
+	*config.Config
+	client *http.Client
+}
+
+func NewWebhookConfig(cfg *config.Config) *WebhookConfig {
+	return &WebhookConfig{Config: cfg, client: netguard.NewClient(deliveryTimeout)}
+}
+
+// @Summary		List webhooks
+// @Description	List the current user's webhooks and the webhooks of the groups they administer
+// @Tags		webhooks
+// @Produce		json
+// @Success		200	{array}		models.WebhookResponse
+// @Failure 	401 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/ [get]
+func (config *WebhookConfig) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	webhooks, err := config.WebhookRepository.FindByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve webhooks", http.StatusInternalServerError)
+		return
+	}
+	groups, err := config.GroupRepository.FindByMemberID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve groups", http.StatusInternalServerError)
+		return
+	}
+	for _, group := range groups {
+		if !config.canManageGroup(user, &group) {
+			continue
+		}
+		groupWebhooks, err := config.WebhookRepository.FindByGroupID(group.ID)
+		if err != nil {
+			http.Error(w, "Failed to retrieve webhooks", http.StatusInternalServerError)
+			return
+		}
+		webhooks = append(webhooks, groupWebhooks...)
+	}
+	webhookResponse := make([]models.WebhookResponse, 0)
+	for _, webhook := range webhooks {
+		webhookResponse = append(webhookResponse, *config.toWebhookResponse(&webhook))
+	}
+	render.JSON(w, r, webhookResponse)
+}
+
+// @Summary		Register a webhook
+// @Description	Register a URL that receives a signed JSON POST for every matching event. Set group_id to register it for a group you administer. Events are limited to what the owner may see through the API, and a group webhook stops delivering once its owner no longer administers the group. The URL must resolve to a public address. The signing secret is only returned once.
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		request	body	models.WebhookRequest	true	"Webhook data"
+// @Success		200	{object}	models.WebhookResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	403 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/ [post]
+func (config *WebhookConfig) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	user := authentication.UserFromContext(r.Context())
+	req := &models.WebhookRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateWebhook(r.Context(), req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.GroupID != 0 {
+		group, err := config.GroupRepository.FindByID(req.GroupID)
+		if err != nil || !config.canManageGroup(user, group) {
+			http.Error(w, "You are not an admin of this group", http.StatusForbidden)
+			return
+		}
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		http.Error(w, "Failed to generate secret", http.StatusInternalServerError)
+		return
+	}
+	webhook := &dbmodel.Webhook{
+		UserID:  user.ID,
+		GroupID: req.GroupID,
+		URL:     req.URL,
+		Secret:  secret,
+		Events:  strings.Join(req.Events, ","),
+		Active:  req.Active == nil || *req.Active,
+	}
+	created, err := config.WebhookRepository.Create(webhook)
+	if err != nil {
+		http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+	webhookResponse := config.toWebhookResponse(created)
+	webhookResponse.Secret = secret
+	render.JSON(w, r, webhookResponse)
+}
+
+// @Summary		Get a webhook
+// @Description	Get a webhook with the outcome of its last delivery
+// @Tags		webhooks
+// @Produce		json
+// @Param		id	path	int	true	"Webhook ID"
+// @Success		200	{object}	models.WebhookResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/{id} [get]
+func (config *WebhookConfig) GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := config.managedWebhook(w, r)
+	if !ok {
+		return
+	}
+	render.JSON(w, r, config.toWebhookResponse(webhook))
+}
+
+// @Summary		Update a webhook
+// @Description	Change the URL, event filter or active flag of a webhook
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		id	path	int	true	"Webhook ID"
+// @Param		request	body	models.WebhookRequest	true	"Webhook data"
+// @Success		200	{object}	models.WebhookResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/{id} [put]
+func (config *WebhookConfig) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := config.managedWebhook(w, r)
+	if !ok {
+		return
+	}
+	req := &models.WebhookRequest{}
+	if err := render.Bind(r, req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateWebhook(r.Context(), req); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	webhook.URL = req.URL
+	webhook.Events = strings.Join(req.Events, ",")
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if err := config.WebhookRepository.UpdateByID(webhook.ID, webhook); err != nil {
+		http.Error(w, "Failed to update webhook", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, config.toWebhookResponse(webhook))
+}
+
+// @Summary		Delete a webhook
+// @Description	Delete a webhook and its delivery history
+// @Tags		webhooks
+// @Produce		json
+// @Param		id	path	int	true	"Webhook ID"
+// @Success		200	{object}	map[string]string
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/{id} [delete]
+func (config *WebhookConfig) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := config.managedWebhook(w, r)
+	if !ok {
+		return
+	}
+	if err := config.WebhookRepository.DeleteByID(webhook.ID); err != nil {
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, map[string]string{"message": "Webhook deleted successfully"})
+}
+
+// @Summary		List webhook deliveries
+// @Description	List the most recent deliveries of a webhook, newest first
+// @Tags		webhooks
+// @Produce		json
+// @Param		id	path	int	true	"Webhook ID"
+// @Success		200	{array}		models.WebhookDeliveryResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/{id}/deliveries [get]
+func (config *WebhookConfig) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := config.managedWebhook(w, r)
+	if !ok {
+		return
+	}
+	deliveries, err := config.DeliveryRepository.FindByWebhookID(webhook.ID, deliveriesLimit)
+	if err != nil {
+		http.Error(w, "Failed to retrieve deliveries", http.StatusInternalServerError)
+		return
+	}
+	deliveryResponse := make([]models.WebhookDeliveryResponse, 0)
+	for _, delivery := range deliveries {
+		deliveryResponse = append(deliveryResponse, *toDeliveryResponse(&delivery))
+	}
+	render.JSON(w, r, deliveryResponse)
+}
+
+// @Summary		Redeliver a webhook payload
+// @Description	Send the payload of a past delivery again as a new delivery. It is attempted right away and retried like any other delivery if it fails.
+// @Tags		webhooks
+// @Produce		json
+// @Param		id	path	int	true	"Webhook ID"
+// @Param		deliveryID	path	int	true	"Delivery ID"
+// @Success		200	{object}	models.WebhookDeliveryResponse
+// @Failure 	400 {string} 	string
+// @Failure 	401 {string} 	string
+// @Failure 	404 {string} 	string
+// @Failure 	500 {string} 	string
+// @Security 	BearerAuth
+// @Router		/webhook/{id}/deliveries/{deliveryID}/redeliver [post]
+func (config *WebhookConfig) Redeliver(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := config.managedWebhook(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.Atoi(chi.URLParam(r, "deliveryID"))
+	if err != nil || deliveryID < 1 {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+	original, err := config.DeliveryRepository.FindByID(uint(deliveryID))
+	if err != nil || original.WebhookID != webhook.ID {
+		http.Error(w, "Delivery not found", http.StatusNotFound)
+		return
+	}
+	delivery, err := config.DeliveryRepository.Create(&dbmodel.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        dbmodel.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to queue delivery", http.StatusInternalServerError)
+		return
+	}
+	// The outcome is reported in the delivery itself, so a failing receiver
+	// is not an error for this endpoint.
+	_ = Deliver(config.client, webhook, delivery, time.Now())
+	if err := config.DeliveryRepository.Update(delivery); err != nil {
+		http.Error(w, "Failed to save delivery", http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, toDeliveryResponse(delivery))
+}
+
+func (config *WebhookConfig) managedWebhook(w http.ResponseWriter, r *http.Request) (*dbmodel.Webhook, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return nil, false
+	}
+	if id < 1 {
+		http.Error(w, "id must be >= 1", http.StatusBadRequest)
+		return nil, false
+	}
+	user := authentication.UserFromContext(r.Context())
+	webhook, err := config.WebhookRepository.FindByID(uint(id))
+	if err != nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return nil, false
+	}
+	if webhook.GroupID == 0 {
+		if webhook.UserID != user.ID {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return nil, false
+		}
+		return webhook, true
+	}
+	group, err := config.GroupRepository.FindByID(webhook.GroupID)
+	if err != nil || !config.canManageGroup(user, group) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return nil, false
+	}
+	return webhook, true
+}
+
+func (config *WebhookConfig) toWebhookResponse(webhook *dbmodel.Webhook) *models.WebhookResponse {
+	webhookResponse := &models.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		GroupID:   webhook.GroupID,
+		Events:    SplitEvents(webhook.Events),
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+	}
+	if webhookResponse.Events == nil {
+		webhookResponse.Events = []string{}
+	}
+	if latest, err := config.DeliveryRepository.FindLatestByWebhookID(webhook.ID); err == nil {
+		webhookResponse.LastStatus = latest.Status
+		webhookResponse.LastResponseCode = latest.LastResponseCode
+		webhookResponse.LastDeliveredAt = latest.DeliveredAt
+	}
+	return webhookResponse
+}
+
+func toDeliveryResponse(delivery *dbmodel.WebhookDelivery) *models.WebhookDeliveryResponse {
+	return &models.WebhookDeliveryResponse{
+		ID:               delivery.ID,
+		EventID:          delivery.EventID,
+		EventType:        delivery.EventType,
+		Status:           delivery.Status,
+		Attempts:         delivery.Attempts,
+		NextAttemptAt:    delivery.NextAttemptAt,
+		LastResponseCode: delivery.LastResponseCode,
+		LastError:        delivery.LastError,
+		DeliveredAt:      delivery.DeliveredAt,
+		CreatedAt:        delivery.CreatedAt,
+		Payload:          delivery.Payload,
+	}
+}
+
+// canManageGroup reports whether a user may administer a group's webhooks.
+func (config *WebhookConfig) canManageGroup(user *dbmodel.User, group *dbmodel.Group) bool {
+	allowed, err := authorization.CanInGroup(config.UserGroupRepository, user, group, authorization.ManageMembers)
+	return err == nil && allowed
+}
+
+func validateWebhook(ctx context.Context, req *models.WebhookRequest) error {
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if err := netguard.CheckURL(ctx, req.URL); err != nil {
+		return err
+	}
+	for _, pattern := range req.Events {
+		if !events.ValidPattern(pattern) {
+			return errors.New("unknown event " + pattern)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	buffer := make([]byte, secretBytes)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
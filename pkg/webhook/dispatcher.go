@@ -0,0 +1,339 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/authorization"
+	"yplanning/pkg/events"
+	"yplanning/pkg/netguard"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxAttempts     = 8
+	initialBackoff  = 30 * time.Second
+	maxBackoff      = time.Hour
+	pollInterval    = 10 * time.Second
+	batchSize       = 50
+	deliveryTimeout = 10 * time.Second
+	queueSize       = 1024
+	signaturePrefix = "sha256="
+)
+
+// Dispatcher turns bus events into persisted deliveries for every matching
+// webhook and sends them, retrying failures with exponential backoff.
+type Dispatcher struct {
+	*config.Config
+	Client *http.Client
+	wake   chan struct{}
+	queue  chan events.Event
+}
+
+// payload is the body of a delivery: the event, identified by a UUID
+// rather than by the bus sequence number, which restarts with the server.
+type payload struct {
+	events.Event
+	ID string `json:"id"`
+}
+
+func NewDispatcher(cfg *config.Config) *Dispatcher {
+	return &Dispatcher{
+		Config: cfg,
+		Client: netguard.NewClient(deliveryTimeout),
+		wake:   make(chan struct{}, 1),
+		queue:  make(chan events.Event, queueSize),
+	}
+}
+
+// Run subscribes to the event bus and delivers queued payloads until ctx is
+// cancelled. Deliveries left pending by a previous run are picked up too.
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	unsubscribe := dispatcher.Events.Subscribe(dispatcher.enqueue)
+	defer unsubscribe()
+	go dispatcher.store(ctx)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		dispatcher.deliverDue(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-dispatcher.wake:
+		}
+	}
+}
+
+// enqueue hands an event over to store without blocking the publisher.
+func (dispatcher *Dispatcher) enqueue(event events.Event) {
+	select {
+	case dispatcher.queue <- event:
+	default:
+		log.Printf("Webhook queue full: dropped event %s", event.Type)
+	}
+}
+
+// store turns queued events into deliveries until ctx is cancelled.
+func (dispatcher *Dispatcher) store(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-dispatcher.queue:
+			dispatcher.createDeliveries(event)
+		}
+	}
+}
+
+func (dispatcher *Dispatcher) createDeliveries(event events.Event) {
+	eventID := uuid.NewString()
+	webhooks, err := dispatcher.WebhookRepository.FindActive()
+	if err != nil {
+		log.Println("Failed to retrieve webhooks:", err)
+		return
+	}
+	queued := false
+	for i := range webhooks {
+		if !dispatcher.matches(&webhooks[i], event) {
+			continue
+		}
+		body, err := json.Marshal(payload{Event: event.RedactedFor(webhooks[i].UserID), ID: eventID})
+		if err != nil {
+			log.Printf("Webhook %d: failed to encode event %s: %v", webhooks[i].ID, eventID, err)
+			continue
+		}
+		delivery := &dbmodel.WebhookDelivery{
+			WebhookID:     webhooks[i].ID,
+			EventID:       eventID,
+			EventType:     event.Type,
+			Payload:       string(body),
+			Status:        dbmodel.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		}
+		if _, err := dispatcher.DeliveryRepository.Create(delivery); err != nil {
+			log.Printf("Webhook %d: failed to queue event %s: %v", webhooks[i].ID, eventID, err)
+			continue
+		}
+		queued = true
+	}
+	if queued {
+		select {
+		case dispatcher.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// matches reports whether a webhook should hear about an event. Personal
+// webhooks follow their owner's resources and the groups the owner belongs
+// to; group webhooks follow the group and the resources of its members.
+// Both only hear what their owner could read through the API at the time
+// of the event, and group webhooks stop once their owner no longer
+// administers the group.
+func (dispatcher *Dispatcher) matches(webhook *dbmodel.Webhook, event events.Event) bool {
+	if !WantsEvent(webhook, event.Type) {
+		return false
+	}
+	owner, err := dispatcher.UserRepository.FindByID(webhook.UserID)
+	if err != nil {
+		return false
+	}
+	if webhook.GroupID == 0 {
+		if event.UserID == owner.ID {
+			return true
+		}
+		return event.GroupID != 0 && dispatcher.canSeeGroupEvent(owner, event)
+	}
+	if !dispatcher.ownerAdministers(webhook) {
+		return false
+	}
+	if event.GroupID != 0 {
+		return event.GroupID == webhook.GroupID
+	}
+	return dispatcher.isMember(webhook.GroupID, event.UserID) && dispatcher.canSeeUser(owner, event.UserID)
+}
+
+// ownerAdministers reports whether the owner of a webhook may still
+// administer its group, which personal webhooks need not.
+func (dispatcher *Dispatcher) ownerAdministers(webhook *dbmodel.Webhook) bool {
+	if webhook.GroupID == 0 {
+		return true
+	}
+	owner, err := dispatcher.UserRepository.FindByID(webhook.UserID)
+	if err != nil {
+		return false
+	}
+	group, err := dispatcher.GroupRepository.FindByID(webhook.GroupID)
+	return err == nil && dispatcher.can(owner, group, authorization.ManageMembers)
+}
+
+// canSeeGroupEvent reports whether a member may hear about a change to a
+// group: any member for the group itself, but only those who may see the
+// members for someone else's membership.
+func (dispatcher *Dispatcher) canSeeGroupEvent(owner *dbmodel.User, event events.Event) bool {
+	group, err := dispatcher.GroupRepository.FindByID(event.GroupID)
+	if err != nil {
+		return false
+	}
+	if event.Resource == events.ResourceMembership {
+		return dispatcher.can(owner, group, authorization.SeeMembers)
+	}
+	role, err := authorization.GroupRole(dispatcher.UserGroupRepository, owner, group)
+	return err == nil && role != ""
+}
+
+func (dispatcher *Dispatcher) can(owner *dbmodel.User, group *dbmodel.Group, permission authorization.GroupPermission) bool {
+	allowed, err := authorization.CanInGroup(dispatcher.UserGroupRepository, owner, group, permission)
+	return err == nil && allowed
+}
+
+func (dispatcher *Dispatcher) canSeeUser(owner *dbmodel.User, userID uint) bool {
+	visible, err := authorization.CanSeeUser(dispatcher.GroupRepository, owner, userID)
+	return err == nil && visible
+}
+
+func (dispatcher *Dispatcher) isMember(groupID uint, userID uint) bool {
+	isMember, err := dispatcher.GroupRepository.IsMember(groupID, userID)
+	return err == nil && isMember
+}
+
+func (dispatcher *Dispatcher) deliverDue(now time.Time) {
+	deliveries, err := dispatcher.DeliveryRepository.FindDue(now, batchSize)
+	if err != nil {
+		log.Println("Failed to retrieve webhook deliveries:", err)
+		return
+	}
+	for i := range deliveries {
+		webhook, err := dispatcher.WebhookRepository.FindByID(deliveries[i].WebhookID)
+		if err != nil {
+			deliveries[i].Status = dbmodel.DeliveryFailed
+			deliveries[i].LastError = "webhook no longer exists"
+			dispatcher.DeliveryRepository.Update(&deliveries[i])
+			continue
+		}
+		if !dispatcher.ownerAdministers(webhook) {
+			deliveries[i].Status = dbmodel.DeliveryFailed
+			deliveries[i].LastError = "webhook owner no longer administers the group"
+			dispatcher.DeliveryRepository.Update(&deliveries[i])
+			continue
+		}
+		if err := Deliver(dispatcher.Client, webhook, &deliveries[i], time.Now()); err != nil {
+			log.Printf("Webhook %d: delivery %d failed: %v", webhook.ID, deliveries[i].ID, err)
+		}
+		if err := dispatcher.DeliveryRepository.Update(&deliveries[i]); err != nil {
+			log.Printf("Webhook %d: failed to save delivery %d: %v", webhook.ID, deliveries[i].ID, err)
+		}
+	}
+}
+
+// Deliver makes one attempt at sending a delivery and records the outcome
+// on it; the caller persists the delivery. A non-2xx answer or transport
+// error schedules a retry, until MaxAttempts is reached.
+func Deliver(client *http.Client, webhook *dbmodel.Webhook, delivery *dbmodel.WebhookDelivery, now time.Time) error {
+	delivery.Attempts++
+	err := send(client, webhook, delivery, now)
+	if err == nil {
+		delivery.Status = dbmodel.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return nil
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= MaxAttempts {
+		delivery.Status = dbmodel.DeliveryFailed
+	} else {
+		delivery.Status = dbmodel.DeliveryPending
+		delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+	}
+	return err
+}
+
+// Backoff returns how long to wait after the given number of failed
+// attempts: 30s, 1m, 2m, ... capped at one hour.
+func Backoff(attempts int) time.Duration {
+	backoff := initialBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+func send(client *http.Client, webhook *dbmodel.Webhook, delivery *dbmodel.WebhookDelivery, now time.Time) error {
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.LastResponseCode = 0
+		return err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "yplanning-webhooks")
+	request.Header.Set("X-Yplanning-Event", delivery.EventType)
+	request.Header.Set("X-Yplanning-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set("X-Yplanning-Timestamp", timestamp)
+	request.Header.Set("X-Yplanning-Signature", Sign(webhook.Secret, timestamp, body))
+
+	response, err := client.Do(request)
+	if err != nil {
+		delivery.LastResponseCode = 0
+		return err
+	}
+	response.Body.Close()
+	delivery.LastResponseCode = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+	return nil
+}
+
+// Sign computes the signature header value: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WantsEvent reports whether an event type passes the webhook's filter.
+// An empty filter selects every event.
+func WantsEvent(webhook *dbmodel.Webhook, eventType string) bool {
+	patterns := SplitEvents(webhook.Events)
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if events.Matches(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func SplitEvents(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
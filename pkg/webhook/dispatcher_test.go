@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"path/filepath"
+	"testing"
+
+	"yplanning/config"
+	"yplanning/database/dbmodel"
+	"yplanning/pkg/events"
+)
+
+func TestMatchesFollowsOwnerAccess(t *testing.T) {
+	cfg, err := config.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := func(username string) *dbmodel.User {
+		created, err := cfg.UserRepository.Create(&dbmodel.User{Username: username, Email: username + "@example.com", Password: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+	alice, bob, carol, dave := user("alice"), user("bob"), user("carol"), user("dave")
+	group, err := cfg.GroupRepository.Create(&dbmodel.Group{Name: "Team", CreatorID: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, membership := range []dbmodel.UserGroup{
+		{UserID: bob.ID, GroupID: group.ID, Role: dbmodel.RoleAdmin},
+		{UserID: carol.ID, GroupID: group.ID, Role: dbmodel.RoleGuest},
+		{UserID: dave.ID, GroupID: group.ID, Role: dbmodel.RoleMember},
+	} {
+		if _, err := cfg.UserGroupRepository.Create(&membership); err != nil {
+			t.Fatal(err)
+		}
+	}
+	groupWebhook := &dbmodel.Webhook{UserID: bob.ID, GroupID: group.ID, Active: true}
+	guestWebhook := &dbmodel.Webhook{UserID: carol.ID, Active: true}
+	dateOf := func(user *dbmodel.User) events.Event {
+		return events.DateEvent(events.ActionCreated, &dbmodel.Date{UserID: user.ID})
+	}
+	dispatcher := NewDispatcher(cfg)
+
+	tests := []struct {
+		name    string
+		webhook *dbmodel.Webhook
+		event   events.Event
+		want    bool
+	}{
+		{name: "member's date", webhook: groupWebhook, event: dateOf(dave), want: true},
+		{name: "guest's date", webhook: groupWebhook, event: dateOf(carol), want: false},
+		{name: "outsider's date", webhook: groupWebhook, event: dateOf(user("erin")), want: false},
+		{name: "group change for a guest", webhook: guestWebhook, event: events.GroupEvent(events.ActionUpdated, group), want: true},
+		{name: "other membership for a guest", webhook: guestWebhook, event: events.MembershipEvent(events.ActionUpdated, &dbmodel.UserGroup{UserID: dave.ID, GroupID: group.ID}), want: false},
+		{name: "own membership for a guest", webhook: guestWebhook, event: events.MembershipEvent(events.ActionUpdated, &dbmodel.UserGroup{UserID: carol.ID, GroupID: group.ID}), want: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := dispatcher.matches(test.webhook, test.event); got != test.want {
+				t.Errorf("matches = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if err := cfg.UserGroupRepository.UpdateRoleByUserIDAndGroupID(bob.ID, group.ID, dbmodel.RoleMember); err != nil {
+		t.Fatal(err)
+	}
+	if dispatcher.matches(groupWebhook, dateOf(dave)) {
+		t.Error("group webhook still matches after its owner stopped administering the group")
+	}
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"yplanning/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+webhook routes:
+GET /webhook/ - List the current user's and administered groups' webhooks
+POST /webhook/ - Register a webhook
+GET /webhook/{id} - Get a webhook
+PUT /webhook/{id} - Update a webhook
+DELETE /webhook/{id} - Delete a webhook
+GET /webhook/{id}/deliveries - List recent deliveries of a webhook
+POST /webhook/{id}/deliveries/{deliveryID}/redeliver - Send a delivery again
+*/
+
+func Routes(config *config.Config) chi.Router {
+	WebhookConfig := NewWebhookConfig(config)
+	router := chi.NewRouter()
+	router.Get("/", WebhookConfig.GetWebhooks)
+	router.Post("/", WebhookConfig.CreateWebhook)
+	router.Get("/{id}", WebhookConfig.GetWebhookByID)
+	router.Put("/{id}", WebhookConfig.UpdateWebhook)
+	router.Delete("/{id}", WebhookConfig.DeleteWebhook)
+	router.Get("/{id}/deliveries", WebhookConfig.GetDeliveries)
+	router.Post("/{id}/deliveries/{deliveryID}/redeliver", WebhookConfig.Redeliver)
+	return router
+}